@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -21,15 +22,23 @@ import (
 	"github.com/jpillora/overseer"
 	"github.com/mattn/go-isatty"
 
+	"github.com/trufflesecurity/trufflehog/v3/pkg/blobcache"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/cleantemp"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/config"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/engine"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/exitcode"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/handlers"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/log"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/output"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/output/progress"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/scanconfig"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/docker"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/git"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/support"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/tui"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/updater"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/version"
@@ -41,6 +50,8 @@ var (
 	debug               = cli.Flag("debug", "Run in debug mode.").Bool()
 	trace               = cli.Flag("trace", "Run in trace mode.").Bool()
 	profile             = cli.Flag("profile", "Enables profiling and sets a pprof and fgprof server on :18066.").Bool()
+	metricsAddr         = cli.Flag("metrics-addr", "Address to serve Prometheus scan metrics on (e.g. :9090). Leave unset to disable.").String()
+	progressFlag        = cli.Flag("progress", "Render a live progress display while scanning. Falls back to periodic log lines when stderr isn't a terminal.").Bool()
 	localDev            = cli.Flag("local-dev", "Hidden feature to disable overseer for local dev.").Hidden().Bool()
 	jsonOut             = cli.Flag("json", "Output in JSON format.").Short('j').Bool()
 	jsonLegacy          = cli.Flag("json-legacy", "Use the pre-v3.0 JSON format. Only works with git, gitlab, and github sources.").Bool()
@@ -59,28 +70,35 @@ var (
 	// rules = cli.Flag("rules", "Path to file with custom rules.").String()
 	printAvgDetectorTime = cli.Flag("print-avg-detector-time", "Print the average time spent on each detector.").Bool()
 	noUpdate             = cli.Flag("no-update", "Don't check for updates.").Bool()
-	fail                 = cli.Flag("fail", "Exit with code 183 if results are found.").Bool()
-	verifiers            = cli.Flag("verifier", "Set custom verification endpoints.").StringMap()
-	customVerifiersOnly  = cli.Flag("custom-verifiers-only", "Only use custom verification endpoints.").Bool()
-	archiveMaxSize       = cli.Flag("archive-max-size", "Maximum size of archive to scan. (Byte units eg. 512B, 2KB, 4MB)").Bytes()
-	archiveMaxDepth      = cli.Flag("archive-max-depth", "Maximum depth of archive to scan.").Int()
-	archiveTimeout       = cli.Flag("archive-timeout", "Maximum time to spend extracting an archive.").Duration()
-	includeDetectors     = cli.Flag("include-detectors", "Comma separated list of detector types to include. Protobuf name or IDs may be used, as well as ranges.").Default("all").String()
-	excludeDetectors     = cli.Flag("exclude-detectors", "Comma separated list of detector types to exclude. Protobuf name or IDs may be used, as well as ranges. IDs defined here take precedence over the include list.").String()
-	jobReportFile        = cli.Flag("output-report", "Write a scan report to the provided path.").Hidden().OpenFile(os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
-
-	gitScan             = cli.Command("git", "Find credentials in git repositories.")
-	gitScanURI          = gitScan.Arg("uri", "Git repository URL. https://, file://, or ssh:// schema expected.").Required().String()
-	gitScanIncludePaths = gitScan.Flag("include-paths", "Path to file with newline separated regexes for files to include in scan.").Short('i').String()
-	gitScanExcludePaths = gitScan.Flag("exclude-paths", "Path to file with newline separated regexes for files to exclude in scan.").Short('x').String()
-	gitScanExcludeGlobs = gitScan.Flag("exclude-globs", "Comma separated list of globs to exclude in scan. This option filters at the `git log` level, resulting in faster scans.").String()
-	gitScanSinceCommit  = gitScan.Flag("since-commit", "Commit to start scan from.").String()
-	gitScanBranch       = gitScan.Flag("branch", "Branch to scan.").String()
-	gitScanMaxDepth     = gitScan.Flag("max-depth", "Maximum depth of commits to scan.").Int()
-	gitScanBare         = gitScan.Flag("bare", "Scan bare repository (e.g. useful while using in pre-receive hooks)").Bool()
-	_                   = gitScan.Flag("allow", "No-op flag for backwards compat.").Bool()
-	_                   = gitScan.Flag("entropy", "No-op flag for backwards compat.").Bool()
-	_                   = gitScan.Flag("regex", "No-op flag for backwards compat.").Bool()
+	fail                 = cli.Flag("fail", "Exit with code 183 if results are found. Deprecated: use --fail-on=verified instead.").Bool()
+	failOn               = cli.Flag("fail-on", "Exit with a categorized non-zero code depending on what the scan found: verified, unverified, any, or errors (see pkg/exitcode).").Enum(
+		string(exitcode.FailOnVerified), string(exitcode.FailOnUnverified), string(exitcode.FailOnAny), string(exitcode.FailOnErrors),
+	)
+	verifiers           = cli.Flag("verifier", "Set custom verification endpoints.").StringMap()
+	customVerifiersOnly = cli.Flag("custom-verifiers-only", "Only use custom verification endpoints.").Bool()
+	archiveMaxSize      = cli.Flag("archive-max-size", "Maximum size of archive to scan. (Byte units eg. 512B, 2KB, 4MB)").Bytes()
+	archiveMaxDepth     = cli.Flag("archive-max-depth", "Maximum depth of archive to scan.").Int()
+	archiveTimeout      = cli.Flag("archive-timeout", "Maximum time to spend extracting an archive.").Duration()
+	includeDetectors    = cli.Flag("include-detectors", "Comma separated list of detector types to include. Protobuf name or IDs may be used, as well as ranges.").Default("all").String()
+	excludeDetectors    = cli.Flag("exclude-detectors", "Comma separated list of detector types to exclude. Protobuf name or IDs may be used, as well as ranges. IDs defined here take precedence over the include list.").String()
+	jobReportFile       = cli.Flag("output-report", "Write a scan report to the provided path.").Hidden().OpenFile(os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	blobCacheDir        = cli.Flag("blob-cache-dir", "Directory to cache scanned git blob results in, keyed by blob hash, to skip re-scanning identical binaries seen across commits. Leave unset to disable.").String()
+	blobCacheSize       = cli.Flag("blob-cache-size", "Maximum total size of --blob-cache-dir. (Byte units eg. 512B, 2KB, 4MB)").Default("1GB").Bytes()
+	exclusionConfigFile = cli.Flag("exclusion-config", "Path to a YAML file of paths, extensions, and strings to exclude from every source's scan.").ExistingFile()
+
+	gitScan                 = cli.Command("git", "Find credentials in git repositories.")
+	gitScanURI              = gitScan.Arg("uri", "Git repository URL. https://, file://, or ssh:// schema expected.").Required().String()
+	gitScanIncludePaths     = gitScan.Flag("include-paths", "Path to file with newline separated regexes for files to include in scan.").Short('i').String()
+	gitScanExcludePaths     = gitScan.Flag("exclude-paths", "Path to file with newline separated regexes for files to exclude in scan.").Short('x').String()
+	gitScanExcludeGlobs     = gitScan.Flag("exclude-globs", "Comma separated list of globs to exclude in scan. This option filters at the `git log` level, resulting in faster scans.").String()
+	gitScanSinceCommit      = gitScan.Flag("since-commit", "Commit to start scan from.").String()
+	gitScanBranch           = gitScan.Flag("branch", "Branch to scan.").String()
+	gitScanMaxDepth         = gitScan.Flag("max-depth", "Maximum depth of commits to scan.").Int()
+	gitScanBare             = gitScan.Flag("bare", "Scan bare repository (e.g. useful while using in pre-receive hooks)").Bool()
+	gitScanRespectGitignore = gitScan.Flag("respect-gitignore", "Skip files matched by the repository's .gitignore and .gitattributes linguist-generated rules.").Bool()
+	_                       = gitScan.Flag("allow", "No-op flag for backwards compat.").Bool()
+	_                       = gitScan.Flag("entropy", "No-op flag for backwards compat.").Bool()
+	_                       = gitScan.Flag("regex", "No-op flag for backwards compat.").Bool()
 
 	githubScan           = cli.Command("github", "Find credentials in GitHub repositories.")
 	githubScanEndpoint   = githubScan.Flag("endpoint", "GitHub endpoint.").Default("https://api.github.com").String()
@@ -106,6 +124,9 @@ var (
 	gitlabScanToken        = gitlabScan.Flag("token", "GitLab token. Can be provided with environment variable GITLAB_TOKEN.").Envar("GITLAB_TOKEN").Required().String()
 	gitlabScanIncludePaths = gitlabScan.Flag("include-paths", "Path to file with newline separated regexes for files to include in scan.").Short('i').String()
 	gitlabScanExcludePaths = gitlabScan.Flag("exclude-paths", "Path to file with newline separated regexes for files to exclude in scan.").Short('x').String()
+	gitlabScanDepth        = gitlabScan.Flag("depth", "Clone each repository shallowly to this many commits instead of its full history.").Int()
+	gitlabScanSince        = gitlabScan.Flag("since", "Skip projects with no activity since this RFC3339 timestamp, and exclude commits older than it from the scan.").String()
+	gitlabScanSingleBranch = gitlabScan.Flag("single-branch", "Clone only this branch instead of all branches.").String()
 
 	filesystemScan  = cli.Command("filesystem", "Find credentials in a filesystem.")
 	filesystemPaths = filesystemScan.Arg("path", "Path to file or directory to scan.").Strings()
@@ -145,12 +166,40 @@ var (
 	syslogTLSKey   = syslogScan.Flag("key", "Path to TLS key.").String()
 	syslogFormat   = syslogScan.Flag("format", "Log format. Can be rfc3164 or rfc5424").String()
 
+	journaldScan   = cli.Command("journald", "Scan the local systemd journal")
+	journaldUnit   = journaldScan.Flag("unit", "Restrict the scan to a single systemd unit.").String()
+	journaldSince  = journaldScan.Flag("since", "Restrict the scan to entries at or after this time (journalctl --since syntax).").String()
+	journaldFollow = journaldScan.Flag("follow", "Continue tailing the journal for new entries after the backlog is scanned.").Bool()
+
+	gclScan        = cli.Command("gcl", "Scan Google Cloud Logging")
+	gclProjectID   = gclScan.Flag("project-id", "GCP project whose log entries should be scanned.").Required().String()
+	gclFilter      = gclScan.Flag("filter", "Cloud Logging query used to restrict which entries are returned.").String()
+	gclAPIKey      = gclScan.Flag("api-key", "API key to authenticate with Google Cloud Logging.").String()
+	gclAccessToken = gclScan.Flag("access-token", "OAuth2 access token to authenticate with Google Cloud Logging.").String()
+
 	circleCiScan      = cli.Command("circleci", "Scan CircleCI")
 	circleCiScanToken = circleCiScan.Flag("token", "CircleCI token. Can also be provided with environment variable").Envar("CIRCLECI_TOKEN").Required().String()
 
-	dockerScan       = cli.Command("docker", "Scan Docker Image")
-	dockerScanImages = dockerScan.Flag("image", "Docker image to scan. Use the file:// prefix to point to a local tarball, otherwise a image registry is assumed.").Required().Strings()
-	dockerScanToken  = dockerScan.Flag("token", "Docker bearer token. Can also be provided with environment variable").Envar("DOCKER_TOKEN").String()
+	dockerScan             = cli.Command("docker", "Scan Docker Image")
+	dockerScanImages       = dockerScan.Flag("image", "Docker image to scan. Use the file:// prefix to point to a local tarball, otherwise a image registry is assumed.").Required().Strings()
+	dockerScanToken        = dockerScan.Flag("token", "Docker bearer token. Can also be provided with environment variable").Envar("DOCKER_TOKEN").String()
+	dockerScanPlatform     = dockerScan.Flag("platform", "Platform to scan for a multi-architecture image, e.g. linux/amd64. Can be repeated. Defaults to the host's platform.").Strings()
+	dockerScanAllPlatforms = dockerScan.Flag("all-platforms", "Scan every platform of a multi-architecture image.").Bool()
+
+	registryScan            = cli.Command("registry", "Scan every image in a container registry")
+	registryScanURL         = registryScan.Flag("url", "Base URL of the registry to scan, e.g. https://registry-1.docker.io").Required().String()
+	registryScanRepoInclude = registryScan.Flag("repo-include", "Glob of repository names to include in the scan. Can be repeated.").Strings()
+	registryScanRepoExclude = registryScan.Flag("repo-exclude", "Glob of repository names to exclude from the scan. Can be repeated.").Strings()
+	registryScanTagInclude  = registryScan.Flag("tag-include", "Glob of tags to include in the scan. Can be repeated.").Strings()
+	registryScanTagExclude  = registryScan.Flag("tag-exclude", "Glob of tags to exclude from the scan. Can be repeated.").Strings()
+	registryScanUsername    = registryScan.Flag("username", "Registry username for basic auth. Can also be provided with environment variable").Envar("REGISTRY_USERNAME").String()
+	registryScanPassword    = registryScan.Flag("password", "Registry password for basic auth. Can also be provided with environment variable").Envar("REGISTRY_PASSWORD").String()
+	registryScanToken       = registryScan.Flag("token", "Registry bearer token. Can also be provided with environment variable").Envar("REGISTRY_TOKEN").String()
+	registryScanConcurrency = registryScan.Flag("concurrency", "Number of repositories to scan concurrently.").Default(strconv.Itoa(runtime.NumCPU())).Int()
+	registryScanPlatform    = registryScan.Flag("platform", "Platform to scan for multi-architecture images, e.g. linux/amd64. Defaults to the registry's default platform.").String()
+
+	scanConfigCmd  = cli.Command("scan", "Scan multiple heterogeneous sources declared in a config file.")
+	scanConfigFile = scanConfigCmd.Flag("config", "Path to a YAML or JSON config file describing an ordered list of sources to scan.").Required().String()
 
 	travisCiScan      = cli.Command("travisci", "Scan TravisCI")
 	travisCiScanToken = travisCiScan.Flag("token", "TravisCI token. Can also be provided with environment variable").Envar("TRAVISCI_TOKEN").Required().String()
@@ -198,7 +247,18 @@ var (
 	jenkinsPassword              = jenkinsScan.Flag("password", "Jenkins password").Envar("JENKINS_PASSWORD").String()
 	jenkinsInsecureSkipVerifyTLS = jenkinsScan.Flag("insecure-skip-verify-tls", "Skip TLS verification").Envar("JENKINS_INSECURE_SKIP_VERIFY_TLS").Bool()
 
+	supportCmd       = cli.Command("support", "Support utilities for diagnosing trufflehog issues.")
+	supportBundleCmd = supportCmd.Command("bundle", "Gather diagnostic info into a single tar.gz for bug reports. Safe to run without performing a scan.")
+	supportBundleOut = supportBundleCmd.Flag("output", "Where to write the bundle: a file path, or - for stdout.").Default("-").String()
+
+	cacheCmd      = cli.Command("cache", "Manage the --blob-cache-dir blob scan cache.")
+	cachePruneCmd = cacheCmd.Command("prune", "Evict least-recently-used entries from --blob-cache-dir down to --blob-cache-size. Safe to run without performing a scan.")
+
 	usingTUI = false
+
+	// supportLogBuffer retains recent log output for inclusion in a
+	// `support bundle`.
+	supportLogBuffer = support.NewLogBuffer(2000)
 )
 
 func init() {
@@ -243,7 +303,7 @@ func main() {
 	if *jsonOut {
 		logFormat = log.WithJSONSink
 	}
-	logger, sync := log.New("trufflehog", logFormat(os.Stderr))
+	logger, sync := log.New("trufflehog", logFormat(io.MultiWriter(os.Stderr, supportLogBuffer)))
 	// make it the default logger for contexts
 	context.SetDefaultLogger(logger)
 
@@ -289,6 +349,24 @@ func run(state overseer.State) {
 	logger := ctx.Logger()
 	logFatal := logFatalFunc(logger)
 
+	if cmd == supportBundleCmd.FullCommand() {
+		if err := runSupportBundle(); err != nil {
+			logFatal(err, "failed to generate support bundle")
+		}
+		return
+	}
+
+	if cmd == cachePruneCmd.FullCommand() {
+		if err := runCachePrune(); err != nil {
+			logFatal(err, "failed to prune blob cache")
+		}
+		return
+	}
+
+	if err := validateFlags(); err != nil {
+		logFatalCodeFunc(logger, exitcode.ConfigError)(err, "invalid command-line flags")
+	}
+
 	killSignal := make(chan os.Signal, 1)
 	signal.Notify(killSignal, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	go func() {
@@ -304,7 +382,7 @@ func run(state overseer.State) {
 
 		time.Sleep(time.Second * 10)
 		logger.Info("10 seconds elapsed. Forcing shutdown.")
-		os.Exit(0)
+		os.Exit(exitcode.Interrupted)
 	}()
 
 	logger.V(2).Info(fmt.Sprintf("trufflehog %s", version.BuildVersion))
@@ -337,7 +415,7 @@ func run(state overseer.State) {
 		var err error
 		conf, err = config.Read(*configFilename)
 		if err != nil {
-			logFatal(err, "error parsing the provided configuration file")
+			logFatalCodeFunc(logger, exitcode.ConfigError)(err, "error parsing the provided configuration file")
 		}
 	}
 
@@ -351,6 +429,23 @@ func run(state overseer.State) {
 		handlers.SetArchiveMaxTimeout(*archiveTimeout)
 	}
 
+	if *blobCacheDir != "" {
+		cache, err := blobcache.New(*blobCacheDir, int64(*blobCacheSize))
+		if err != nil {
+			logFatalCodeFunc(logger, exitcode.ConfigError)(err, "error opening blob cache")
+		}
+		git.SetDefaultBlobCache(cache)
+	}
+
+	if *exclusionConfigFile != "" {
+		exclusionConfig, err := sources.LoadExclusionConfig(*exclusionConfigFile)
+		if err != nil {
+			logFatalCodeFunc(logger, exitcode.ConfigError)(err, "error loading exclusion config")
+		}
+		git.SetDefaultExclusionConfig(exclusionConfig)
+		docker.SetDefaultExclusionConfig(exclusionConfig)
+	}
+
 	// Set how the engine will print its results.
 	var printer engine.Printer
 	switch {
@@ -368,7 +463,21 @@ func run(state overseer.State) {
 		fmt.Fprintf(os.Stderr, "🐷🔑🐷  TruffleHog. Unearth your secrets. 🐷🔑🐷\n\n")
 	}
 
-	// Parse --results flag.
+	if *metricsAddr != "" {
+		engine.SetBuildVersion(version.BuildVersion)
+		printer = engine.NewMetricsPrinter(printer)
+
+		metricsServer := engine.StartMetricsServer(ctx, *metricsAddr)
+		logger.Info("serving Prometheus scan metrics", "addr", *metricsAddr)
+		defer func() {
+			if err := metricsServer.Shutdown(ctx); err != nil {
+				logger.Error(err, "error shutting down metrics server")
+			}
+		}()
+	}
+
+	// Parse --results flag. validateFlags has already rejected --only-verified
+	// combined with --results, so this can't clobber a user-provided value.
 	if *onlyVerified {
 		r := "verified"
 		results = &r
@@ -417,9 +526,19 @@ func run(state overseer.State) {
 		"trufflehog_version", version.BuildVersion,
 	)
 
-	if metrics.hasFoundResults && *fail {
+	if *failOn != "" {
+		code := exitcode.ForScan(exitcode.FailOn(*failOn), exitcode.Outcome{
+			VerifiedFound:   metrics.VerifiedSecretsFound > 0,
+			UnverifiedFound: metrics.UnverifiedSecretsFound > 0,
+			UnitErrors:      metrics.hasUnitErrors,
+		})
+		if code != exitcode.OK {
+			logger.V(2).Info("exiting with non-zero code", "code", code, "fail_on", *failOn)
+			os.Exit(code)
+		}
+	} else if metrics.hasFoundResults && *fail {
 		logger.V(2).Info("exiting with code 183 because results were found")
-		os.Exit(183)
+		os.Exit(exitcode.VerifiedFound)
 	}
 }
 
@@ -474,6 +593,7 @@ func compareMetrics(customMetrics, entireMetrics engine.Metrics) error {
 type metrics struct {
 	engine.Metrics
 	hasFoundResults bool
+	hasUnitErrors   bool
 }
 
 func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics, error) {
@@ -485,18 +605,46 @@ func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics,
 		jobReportWriter = *jobReportFile
 	}
 
-	handleFinishedMetrics := func(ctx context.Context, finishedMetrics <-chan sources.UnitMetrics, jobReportWriter io.WriteCloser) {
+	// Setup live progress rendering if requested.
+	var progressTracker *progress.Tracker
+	if *progressFlag {
+		renderer := progress.NewRenderer(os.Stderr, isatty.IsTerminal(os.Stderr.Fd()), ctx.Logger().Info)
+		progressTracker = progress.NewTracker(renderer, time.Second, 3)
+	}
+
+	// unitErrorsObserved records whether any source unit reported errors, so
+	// --fail-on=errors/any can be honored once the scan finishes.
+	var unitErrorsObserved atomic.Bool
+
+	// handleFinishedMetrics consumes the single finishedMetrics subscription
+	// shared by JSONL job report writing, progress rendering, and exit-code
+	// error tracking, so none of them need their own hook.
+	handleFinishedMetrics := func(ctx context.Context, finishedMetrics <-chan sources.UnitMetrics, jobReportWriter io.WriteCloser, progressTracker *progress.Tracker) {
 		go func() {
-			defer func() {
-				jobReportWriter.Close()
-				if namer, ok := jobReportWriter.(interface{ Name() string }); ok {
-					ctx.Logger().Info("report written", "path", namer.Name())
-				} else {
-					ctx.Logger().Info("report written")
-				}
-			}()
+			if jobReportWriter != nil {
+				defer func() {
+					jobReportWriter.Close()
+					if namer, ok := jobReportWriter.(interface{ Name() string }); ok {
+						ctx.Logger().Info("report written", "path", namer.Name())
+					} else {
+						ctx.Logger().Info("report written")
+					}
+				}()
+			}
 
 			for metrics := range finishedMetrics {
+				if len(metrics.Errors) > 0 {
+					unitErrorsObserved.Store(true)
+				}
+
+				if progressTracker != nil {
+					progressTracker.ObserveUnit(metrics.Unit.SourceUnitID())
+				}
+
+				if jobReportWriter == nil {
+					continue
+				}
+
 				metrics.Errors = common.ExportErrors(metrics.Errors...)
 				details, err := json.Marshal(map[string]any{
 					"version": 1,
@@ -521,10 +669,11 @@ func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics,
 		sources.WithBufferedOutput(defaultOutputBufferSize),
 	}
 
-	if jobReportWriter != nil {
+	trackUnitErrors := *failOn == string(exitcode.FailOnErrors) || *failOn == string(exitcode.FailOnAny)
+	if jobReportWriter != nil || progressTracker != nil || trackUnitErrors {
 		unitHook, finishedMetrics := sources.NewUnitHook(ctx)
 		opts = append(opts, sources.WithReportHook(unitHook))
-		handleFinishedMetrics(ctx, finishedMetrics, jobReportWriter)
+		handleFinishedMetrics(ctx, finishedMetrics, jobReportWriter, progressTracker)
 	}
 
 	cfg.SourceManager = sources.NewManager(opts...)
@@ -535,6 +684,15 @@ func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics,
 	}
 	eng.Start(ctx)
 
+	if progressTracker != nil {
+		progressDone := make(chan struct{})
+		defer close(progressDone)
+		go progressTracker.Run(progressDone, func() (uint64, uint64, uint64, uint64) {
+			m := eng.GetMetrics()
+			return uint64(m.ChunksScanned), uint64(m.BytesScanned), uint64(m.VerifiedSecretsFound), uint64(m.UnverifiedSecretsFound)
+		})
+	}
+
 	defer func() {
 		// Clean up temporary artifacts.
 		if err := cleantemp.CleanTempArtifacts(ctx); err != nil {
@@ -553,6 +711,7 @@ func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics,
 			MaxDepth:         *gitScanMaxDepth,
 			Bare:             *gitScanBare,
 			ExcludeGlobs:     *gitScanExcludeGlobs,
+			RespectGitignore: *gitScanRespectGitignore,
 		}
 		if err = eng.ScanGit(ctx, gitCfg); err != nil {
 			return scanMetrics, fmt.Errorf("failed to scan Git: %v", err)
@@ -591,11 +750,23 @@ func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics,
 			return scanMetrics, fmt.Errorf("could not create filter: %v", err)
 		}
 
+		var since time.Time
+		if *gitlabScanSince != "" {
+			since, err = time.Parse(time.RFC3339, *gitlabScanSince)
+			if err != nil {
+				return scanMetrics, fmt.Errorf("could not parse --since timestamp: %v", err)
+			}
+		}
+
 		cfg := sources.GitlabConfig{
-			Endpoint: *gitlabScanEndpoint,
-			Token:    *gitlabScanToken,
-			Repos:    *gitlabScanRepos,
-			Filter:   filter,
+			Endpoint:     *gitlabScanEndpoint,
+			Token:        *gitlabScanToken,
+			Repos:        *gitlabScanRepos,
+			Filter:       filter,
+			Concurrency:  *concurrency,
+			Depth:        *gitlabScanDepth,
+			Since:        since,
+			SingleBranch: *gitlabScanSingleBranch,
 		}
 		if err := eng.ScanGitLab(ctx, cfg); err != nil {
 			return scanMetrics, fmt.Errorf("failed to scan GitLab: %v", err)
@@ -641,6 +812,25 @@ func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics,
 		if err := eng.ScanSyslog(ctx, cfg); err != nil {
 			return scanMetrics, fmt.Errorf("failed to scan syslog: %v", err)
 		}
+	case journaldScan.FullCommand():
+		cfg := sources.JournaldConfig{
+			Unit:   *journaldUnit,
+			Since:  *journaldSince,
+			Follow: *journaldFollow,
+		}
+		if err := eng.ScanJournald(ctx, cfg); err != nil {
+			return scanMetrics, fmt.Errorf("failed to scan journald: %v", err)
+		}
+	case gclScan.FullCommand():
+		cfg := sources.GCLConfig{
+			ProjectID:   *gclProjectID,
+			Filter:      *gclFilter,
+			ApiKey:      *gclAPIKey,
+			AccessToken: *gclAccessToken,
+		}
+		if err := eng.ScanGCL(ctx, cfg); err != nil {
+			return scanMetrics, fmt.Errorf("failed to scan GCL: %v", err)
+		}
 	case circleCiScan.FullCommand():
 		if err := eng.ScanCircleCI(ctx, *circleCiScanToken); err != nil {
 			return scanMetrics, fmt.Errorf("failed to scan CircleCI: %v", err)
@@ -671,10 +861,36 @@ func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics,
 			BearerToken:       *dockerScanToken,
 			Images:            *dockerScanImages,
 			UseDockerKeychain: *dockerScanToken == "",
+			Platforms:         *dockerScanPlatform,
+			AllPlatforms:      *dockerScanAllPlatforms,
 		}
 		if err := eng.ScanDocker(ctx, cfg); err != nil {
 			return scanMetrics, fmt.Errorf("failed to scan Docker: %v", err)
 		}
+	case registryScan.FullCommand():
+		cfg := sources.RegistryConfig{
+			URL:          *registryScanURL,
+			RepoIncludes: *registryScanRepoInclude,
+			RepoExcludes: *registryScanRepoExclude,
+			TagIncludes:  *registryScanTagInclude,
+			TagExcludes:  *registryScanTagExclude,
+			Username:     *registryScanUsername,
+			Password:     *registryScanPassword,
+			Token:        *registryScanToken,
+			Platform:     *registryScanPlatform,
+			Concurrency:  *registryScanConcurrency,
+		}
+		if err := eng.ScanRegistry(ctx, cfg); err != nil {
+			return scanMetrics, fmt.Errorf("failed to scan registry: %v", err)
+		}
+	case scanConfigCmd.FullCommand():
+		doc, err := scanconfig.Load(*scanConfigFile)
+		if err != nil {
+			return scanMetrics, fmt.Errorf("failed to load scan config: %v", err)
+		}
+		if err := eng.ScanConfig(ctx, doc); err != nil {
+			return scanMetrics, fmt.Errorf("failed to run scan config: %v", err)
+		}
 	case postmanScan.FullCommand():
 		// handle deprecated flag
 		workspaceIDs := make([]string, 0, len(*postmanWorkspaceIDs)+len(*postmanWorkspaces))
@@ -751,7 +967,146 @@ func runSingleScan(ctx context.Context, cmd string, cfg engine.Config) (metrics,
 		printAverageDetectorTime(eng)
 	}
 
-	return metrics{Metrics: eng.GetMetrics(), hasFoundResults: eng.HasFoundResults()}, nil
+	detectorMetrics := eng.GetDetectorsMetrics()
+	if *metricsAddr != "" {
+		engine.RecordDetectorLatencies(detectorMetrics)
+	}
+
+	engMetrics := eng.GetMetrics()
+	if err := support.SaveLastRunMetrics(runMetricsFor(engMetrics, detectorMetrics)); err != nil {
+		ctx.Logger().V(2).Info("could not save metrics for support bundle", "error", err)
+	}
+
+	return metrics{Metrics: engMetrics, hasFoundResults: eng.HasFoundResults(), hasUnitErrors: unitErrorsObserved.Load()}, nil
+}
+
+// runMetricsFor translates engine metrics into the shape persisted for
+// `support bundle` to report on later.
+func runMetricsFor(m engine.Metrics, detectorLatency map[string]time.Duration) support.RunMetrics {
+	latency := make(map[string]string, len(detectorLatency))
+	for detector, d := range detectorLatency {
+		latency[detector] = d.String()
+	}
+
+	return support.RunMetrics{
+		ChunksScanned:          uint64(m.ChunksScanned),
+		BytesScanned:           uint64(m.BytesScanned),
+		VerifiedSecretsFound:   uint64(m.VerifiedSecretsFound),
+		UnverifiedSecretsFound: uint64(m.UnverifiedSecretsFound),
+		ScanDuration:           m.ScanDuration.String(),
+		DetectorLatency:        latency,
+	}
+}
+
+// runSupportBundle gathers diagnostic info for a bug report into a single
+// tar.gz and writes it to *supportBundleOut (a file path, or "-" for
+// stdout). It performs no scan and is safe to run at any time.
+// runCachePrune evicts least-recently-used entries from *blobCacheDir down
+// to *blobCacheSize. It performs no scan and is safe to run at any time.
+func runCachePrune() error {
+	if *blobCacheDir == "" {
+		return fmt.Errorf("--blob-cache-dir must be set to prune a blob cache")
+	}
+
+	cache, err := blobcache.New(*blobCacheDir, int64(*blobCacheSize))
+	if err != nil {
+		return err
+	}
+
+	before := cache.TotalSize()
+	if err := cache.Prune(); err != nil {
+		return err
+	}
+	after := cache.TotalSize()
+
+	fmt.Fprintf(os.Stdout, "blob cache pruned: %d bytes -> %d bytes\n", before, after)
+	return nil
+}
+
+func runSupportBundle() error {
+	detectors, err := detectorStatuses()
+	if err != nil {
+		return err
+	}
+
+	var configFile string
+	if *configFilename != "" {
+		data, err := os.ReadFile(*configFilename)
+		if err != nil {
+			return fmt.Errorf("could not read config file: %w", err)
+		}
+		configFile = string(data)
+	}
+
+	var lastRun *support.RunMetrics
+	if m, err := support.LoadLastRunMetrics(); err != nil {
+		return fmt.Errorf("could not load last run metrics: %w", err)
+	} else {
+		lastRun = m
+	}
+
+	bundle := support.Bundle{
+		Version:     version.BuildVersion,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Flags:       resolvedFlags(),
+		Detectors:   detectors,
+		RecentLog:   supportLogBuffer.Lines(),
+		LastRun:     lastRun,
+		ConfigFile:  configFile,
+		Runtime:     support.NewRuntimeInfo(),
+	}
+
+	archive, err := support.Generate(bundle)
+	if err != nil {
+		return err
+	}
+
+	if *supportBundleOut == "-" {
+		_, err := os.Stdout.Write(archive)
+		return err
+	}
+
+	return os.WriteFile(*supportBundleOut, archive, 0o600)
+}
+
+// resolvedFlags returns every kingpin flag's resolved value, keyed by flag
+// name, redacting anything that looks like a credential.
+func resolvedFlags() map[string]string {
+	flags := make(map[string]string)
+	for _, f := range cli.Model().Flags {
+		flags[f.Name] = support.RedactFlagValue(f.Name, f.Value.String())
+	}
+	return flags
+}
+
+// detectorStatuses reports, for every detector selected by
+// --include-detectors, whether --exclude-detectors disabled it.
+func detectorStatuses() ([]support.DetectorStatus, error) {
+	included, err := config.ParseDetectors(*includeDetectors)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse --include-detectors: %w", err)
+	}
+
+	excludedIDs := make(map[detectorspb.DetectorType]bool)
+	if *excludeDetectors != "" {
+		excluded, err := config.ParseDetectors(*excludeDetectors)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse --exclude-detectors: %w", err)
+		}
+		for _, id := range excluded {
+			excludedIDs[id.ID] = true
+		}
+	}
+
+	statuses := make([]support.DetectorStatus, 0, len(included))
+	for _, id := range included {
+		statuses = append(statuses, support.DetectorStatus{
+			Name:    id.ID.String(),
+			Enabled: !excludedIDs[id.ID],
+		})
+	}
+
+	return statuses, nil
 }
 
 // parseResults ensures that users provide valid CSV input to `--results`.
@@ -778,16 +1133,133 @@ func parseResults(input *string) (map[string]struct{}, error) {
 	return results, nil
 }
 
+// flagCheck names a flag and reports whether the user set it.
+type flagCheck struct {
+	name string
+	set  func() bool
+}
+
+// flagRule describes one constraint among a set of flags. command scopes the
+// rule to a single subcommand's FullCommand(); an empty command applies
+// regardless of which subcommand was invoked.
+type flagRule struct {
+	command string
+
+	// mutuallyExclusive fails if more than one of these flags is set.
+	mutuallyExclusive []flagCheck
+
+	// requires fails if requires[0] is set but any of requires[1:] is not.
+	requires []flagCheck
+
+	// forbids fails if forbids[0] is set and any of forbids[1:] is also set.
+	forbids []flagCheck
+}
+
+// flagRules is the declarative table validateFlags walks. Add new flag
+// conflicts here instead of checking them ad-hoc in run().
+var flagRules = []flagRule{
+	{
+		mutuallyExclusive: []flagCheck{
+			{"--json", func() bool { return *jsonOut }},
+			{"--json-legacy", func() bool { return *jsonLegacy }},
+			{"--github-actions", func() bool { return *gitHubActionsFormat }},
+		},
+	},
+	{
+		forbids: []flagCheck{
+			{"--only-verified", func() bool { return *onlyVerified }},
+			{"--results", func() bool { return *results != "" }},
+		},
+	},
+	{
+		forbids: []flagCheck{
+			{"--only-verified", func() bool { return *onlyVerified }},
+			{"--no-verification", func() bool { return *noVerification }},
+		},
+	},
+	{
+		requires: []flagCheck{
+			{"--custom-verifiers-only", func() bool { return *customVerifiersOnly }},
+			{"--verifier", func() bool { return len(*verifiers) > 0 }},
+		},
+	},
+	{
+		command: s3Scan.FullCommand(),
+		mutuallyExclusive: []flagCheck{
+			{"--bucket", func() bool { return len(*s3ScanBuckets) > 0 }},
+			{"--ignore-bucket", func() bool { return len(*s3ScanIgnoreBuckets) > 0 }},
+		},
+	},
+	{
+		command: gcsScan.FullCommand(),
+		mutuallyExclusive: []flagCheck{
+			{"--include-buckets", func() bool { return len(*gcsIncludeBuckets) > 0 }},
+			{"--exclude-buckets", func() bool { return len(*gcsExcludeBuckets) > 0 }},
+		},
+	},
+}
+
+// validateFlags walks flagRules and returns a single error aggregating every
+// violated constraint, so conflicting flags fail fast with a clear message
+// instead of one silently overriding another deep inside run().
+func validateFlags() error {
+	var violations []string
+
+	for _, rule := range flagRules {
+		if rule.command != "" && rule.command != cmd {
+			continue
+		}
+
+		var set []string
+		for _, c := range rule.mutuallyExclusive {
+			if c.set() {
+				set = append(set, c.name)
+			}
+		}
+		if len(set) > 1 {
+			violations = append(violations, fmt.Sprintf("%s are mutually exclusive", strings.Join(set, ", ")))
+		}
+
+		if len(rule.requires) > 0 && rule.requires[0].set() {
+			for _, c := range rule.requires[1:] {
+				if !c.set() {
+					violations = append(violations, fmt.Sprintf("%s requires %s", rule.requires[0].name, c.name))
+				}
+			}
+		}
+
+		if len(rule.forbids) > 0 && rule.forbids[0].set() {
+			for _, c := range rule.forbids[1:] {
+				if c.set() {
+					violations = append(violations, fmt.Sprintf("%s cannot be used with %s", rule.forbids[0].name, c.name))
+				}
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid flags:\n  %s", strings.Join(violations, "\n  "))
+}
+
 // logFatalFunc returns a log.Fatal style function. Calling the returned
 // function will terminate the program without cleanup.
 func logFatalFunc(logger logr.Logger) func(error, string, ...any) {
+	return logFatalCodeFunc(logger, 1)
+}
+
+// logFatalCodeFunc is like logFatalFunc, but exits with code instead of 1
+// when err is non-nil. Use it for callers that can attribute the failure to
+// one of the categorized exitcode constants.
+func logFatalCodeFunc(logger logr.Logger, code int) func(error, string, ...any) {
 	return func(err error, message string, keyAndVals ...any) {
 		logger.Error(err, message, keyAndVals...)
 		if err != nil {
-			os.Exit(1)
+			os.Exit(code)
 			return
 		}
-		os.Exit(0)
+		os.Exit(exitcode.OK)
 	}
 }
 
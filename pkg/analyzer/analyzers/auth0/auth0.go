@@ -0,0 +1,165 @@
+package auth0
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/analyzers"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/config"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+)
+
+var _ analyzers.Analyzer = (*Analyzer)(nil)
+
+type Analyzer struct {
+	Cfg *config.Config
+}
+
+// SecretInfo holds information about the Management API token derived from an
+// auth0oauth (client_id, client_secret, domain) triple.
+type SecretInfo struct {
+	Domain      string
+	Scopes      []string   // scopes granted to the Management API token
+	Permissions []string   // human readable permissions derived from Scopes
+	Resources   []Resource // list of resources the token has access to
+	ExpiresIn   int
+}
+
+// Resource holds information about a Management API resource the token can reach.
+type Resource struct {
+	ID         string
+	Name       string
+	Type       string
+	Metadata   map[string]string
+	Permission string
+}
+
+func (a Analyzer) Type() analyzers.AnalyzerType {
+	return analyzers.AnalyzerTypeAuth0
+}
+
+func (a Analyzer) Analyze(_ context.Context, credInfo map[string]string) (*analyzers.AnalyzerResult, error) {
+	clientID, ok := credInfo["client_id"]
+	if !ok {
+		return nil, errors.New("client_id not found in credentials info")
+	}
+	clientSecret, ok := credInfo["client_secret"]
+	if !ok {
+		return nil, errors.New("client_secret not found in credentials info")
+	}
+	domain, ok := credInfo["domain"]
+	if !ok {
+		return nil, errors.New("domain not found in credentials info")
+	}
+
+	info, err := AnalyzePermissions(a.Cfg, clientID, clientSecret, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return secretInfoToAnalyzerResult(info), nil
+}
+
+// AnalyzePermissions exchanges the (client_id, client_secret, domain) triple for a
+// Management API token, decodes its granted scopes, and probes representative
+// endpoints to determine which resources the token can reach.
+func AnalyzePermissions(cfg *config.Config, clientID, clientSecret, domain string) (*SecretInfo, error) {
+	client := analyzers.NewAnalyzeClient(cfg)
+
+	tokenResp, err := getManagementToken(client, domain, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := parseScopes(tokenResp.Scope)
+	if len(scopes) == 0 {
+		scopes, err = decodeJWTScopes(tokenResp.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	secretInfo := &SecretInfo{
+		Domain:    domain,
+		Scopes:    scopes,
+		ExpiresIn: tokenResp.ExpiresIn,
+	}
+
+	probeResources(client, domain, tokenResp.AccessToken, secretInfo)
+
+	return secretInfo, nil
+}
+
+func AnalyzeAndPrintPermissions(cfg *config.Config, clientID, clientSecret, domain string) {
+	info, err := AnalyzePermissions(cfg, clientID, clientSecret, domain)
+	if err != nil {
+		color.Red("[x] Error : %s", err.Error())
+		return
+	}
+
+	color.Green("[!] Valid Auth0 Management API credentials\n\n")
+	printScopes(info.Scopes)
+	printResources(info.Resources)
+	color.Yellow("\n[i] Expires: %d seconds", info.ExpiresIn)
+}
+
+// secretInfoToAnalyzerResult translates SecretInfo to AnalyzerResult
+func secretInfoToAnalyzerResult(info *SecretInfo) *analyzers.AnalyzerResult {
+	if info == nil {
+		return nil
+	}
+
+	result := analyzers.AnalyzerResult{
+		AnalyzerType: analyzers.AnalyzerTypeAuth0,
+		Metadata: map[string]any{
+			"domain":     info.Domain,
+			"expires_in": info.ExpiresIn,
+		},
+	}
+
+	for _, resource := range info.Resources {
+		binding := analyzers.Binding{
+			Resource: analyzers.Resource{
+				Name:               resource.Name,
+				FullyQualifiedName: fmt.Sprintf("%s/%s", info.Domain, resource.ID),
+				Type:               resource.Type,
+			},
+			Permission: analyzers.Permission{
+				Value: resource.Permission,
+			},
+		}
+
+		for key, value := range resource.Metadata {
+			binding.Resource.Metadata[key] = value
+		}
+
+		result.Bindings = append(result.Bindings, binding)
+	}
+
+	return &result
+}
+
+func printScopes(scopes []string) {
+	color.Yellow("[i] Scopes:")
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Scope"})
+	for _, scope := range scopes {
+		t.AppendRow(table.Row{color.GreenString(scope)})
+	}
+	t.Render()
+}
+
+func printResources(resources []Resource) {
+	color.Green("\n[i] Resources:")
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Resource Type", "Resource ID", "Resource Name", "Permission"})
+	for _, resource := range resources {
+		t.AppendRow(table.Row{color.GreenString(resource.Type), color.GreenString(resource.ID), color.GreenString(resource.Name), color.GreenString(resource.Permission)})
+	}
+	t.Render()
+}
@@ -0,0 +1,214 @@
+package auth0
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// tokenResponse is the /oauth/token response.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	Scope       string `json:"scope"`
+	ExpiresIn   int    `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
+// jwtClaims is the subset of the Management API access token claims we care about.
+type jwtClaims struct {
+	Scope string `json:"scope"`
+}
+
+// probeEndpoint describes a Management API endpoint gated by a scope, and how to
+// turn a successful probe into a Resource entry.
+type probeEndpoint struct {
+	scope  string
+	method string
+	path   string
+	// parseResources extracts zero or more resources from a successful response body.
+	parseResources func([]byte) []Resource
+}
+
+// scopeProbes maps representative Management API endpoints to the scope that
+// should gate access to them. A probe only runs if its scope is present on the
+// token, since most of these endpoints 404 or 403 rather than cleanly
+// distinguishing "no scope" from "no data".
+var scopeProbes = []probeEndpoint{
+	{
+		scope:  "read:clients",
+		method: http.MethodGet,
+		path:   "/api/v2/clients?fields=client_id,name&include_fields=true",
+		parseResources: func(body []byte) []Resource {
+			var clients []struct {
+				ClientID string `json:"client_id"`
+				Name     string `json:"name"`
+			}
+			if err := json.Unmarshal(body, &clients); err != nil {
+				return nil
+			}
+			resources := make([]Resource, 0, len(clients))
+			for _, c := range clients {
+				resources = append(resources, Resource{ID: c.ClientID, Name: c.Name, Type: "Client", Permission: "read:clients"})
+			}
+			return resources
+		},
+	},
+	{
+		scope:  "read:users",
+		method: http.MethodGet,
+		path:   "/api/v2/users?fields=user_id,email&include_fields=true&per_page=1",
+		parseResources: func(body []byte) []Resource {
+			var users []struct {
+				UserID string `json:"user_id"`
+				Email  string `json:"email"`
+			}
+			if err := json.Unmarshal(body, &users); err != nil {
+				return nil
+			}
+			resources := make([]Resource, 0, len(users))
+			for _, u := range users {
+				resources = append(resources, Resource{ID: u.UserID, Name: u.Email, Type: "User", Permission: "read:users"})
+			}
+			return resources
+		},
+	},
+	{
+		scope:  "read:connections",
+		method: http.MethodGet,
+		path:   "/api/v2/connections?fields=id,name&include_fields=true",
+		parseResources: func(body []byte) []Resource {
+			var conns []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(body, &conns); err != nil {
+				return nil
+			}
+			resources := make([]Resource, 0, len(conns))
+			for _, c := range conns {
+				resources = append(resources, Resource{ID: c.ID, Name: c.Name, Type: "Connection", Permission: "read:connections"})
+			}
+			return resources
+		},
+	},
+	{
+		scope:  "read:tenant_settings",
+		method: http.MethodGet,
+		path:   "/api/v2/tenants/settings?fields=friendly_name&include_fields=true",
+		parseResources: func(body []byte) []Resource {
+			var settings struct {
+				FriendlyName string `json:"friendly_name"`
+			}
+			if err := json.Unmarshal(body, &settings); err != nil {
+				return nil
+			}
+			return []Resource{{ID: "tenant", Name: settings.FriendlyName, Type: "TenantSettings", Permission: "read:tenant_settings"}}
+		},
+	},
+}
+
+// getManagementToken exchanges a service-principal client_id/client_secret for a
+// Management API access token via the client_credentials grant.
+func getManagementToken(client *http.Client, domain, clientID, clientSecret string) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", clientID)
+	form.Set("client_secret", clientSecret)
+	form.Set("audience", fmt.Sprintf("https://%s/api/v2/", domain))
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("https://%s/oauth/token", domain), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get Management API token: unexpected status code %d", resp.StatusCode)
+	}
+
+	var tokenResp tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, err
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("unable to get Management API token: no access_token in response")
+	}
+
+	return &tokenResp, nil
+}
+
+// parseScopes splits a space-delimited OAuth scope string.
+func parseScopes(scope string) []string {
+	if scope == "" {
+		return nil
+	}
+	return strings.Fields(scope)
+}
+
+// decodeJWTScopes decodes the scope claim directly out of the access token, for
+// tenants that omit `scope` from the token response body.
+func decodeJWTScopes(token string) ([]string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("access token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode JWT payload: %w", err)
+	}
+
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("could not unmarshal JWT claims: %w", err)
+	}
+
+	return parseScopes(claims.Scope), nil
+}
+
+// probeResources runs every scope-gated probe whose scope is present on the
+// token, recording permissions and resources on secretInfo.
+func probeResources(client *http.Client, domain, accessToken string, secretInfo *SecretInfo) {
+	granted := make(map[string]struct{}, len(secretInfo.Scopes))
+	for _, scope := range secretInfo.Scopes {
+		granted[scope] = struct{}{}
+	}
+
+	for _, probe := range scopeProbes {
+		if _, ok := granted[probe.scope]; !ok {
+			continue
+		}
+
+		req, err := http.NewRequest(probe.method, fmt.Sprintf("https://%s%s", domain, probe.path), nil)
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			body, err := io.ReadAll(resp.Body)
+			if err == nil {
+				secretInfo.Permissions = append(secretInfo.Permissions, probe.scope)
+				if probe.parseResources != nil {
+					secretInfo.Resources = append(secretInfo.Resources, probe.parseResources(body)...)
+				}
+			}
+		}
+		resp.Body.Close()
+	}
+}
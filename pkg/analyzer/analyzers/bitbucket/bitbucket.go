@@ -2,13 +2,19 @@ package bitbucket
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/jedib0t/go-pretty/table"
+	"golang.org/x/sync/errgroup"
+
 	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/analyzers"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/config"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/pb/analyzerpb"
@@ -47,6 +53,38 @@ type Repo struct {
 
 type RepoJSON struct {
 	Values []Repo `json:"values"`
+	// Next is the full URL of the following page, set by Bitbucket on
+	// every page but the last.
+	Next string `json:"next"`
+}
+
+const (
+	// defaultPageLen is the largest page size Bitbucket's list endpoints
+	// accept.
+	defaultPageLen = 100
+
+	// roleConcurrency bounds how many of the four roles getAllRepos fetches
+	// concurrently.
+	roleConcurrency = 4
+
+	// maxPageRetries bounds how many times a single page is retried after
+	// a 429 before getRepositories gives up on it.
+	maxPageRetries = 5
+
+	// rateLimitBaseBackoff is the wait before the first retry of a
+	// rate-limited page, when Bitbucket doesn't send a Retry-After header;
+	// it doubles on each subsequent retry.
+	rateLimitBaseBackoff = time.Second
+)
+
+// rolePriority ranks Bitbucket's repository roles from least to most
+// permissive, so that when a repo is visible under more than one role,
+// getAllRepos keeps the most permissive one.
+var rolePriority = map[string]int{
+	"member":      0,
+	"contributor": 1,
+	"admin":       2,
+	"owner":       3,
 }
 
 type Analyzer struct {
@@ -159,62 +197,136 @@ func scopesToBitbucketScopes(scopes ...analyzers.Permission) []BitbucketScope {
 	return scopesSlice
 }
 
-func getRepositories(cfg *config.Config, key string, role string) (RepoJSON, error) {
-	var repos RepoJSON
-
-	// client
+// getRepositories fetches every repository visible to key under role,
+// following Bitbucket's `next` cursor until it runs out of pages or, if
+// maxRepos is positive, until it has at least that many repos.
+func getRepositories(cfg *config.Config, key string, role string, maxRepos int) ([]Repo, error) {
 	client := analyzers.NewAnalyzeClient(cfg)
 
-	// request
-	req, err := http.NewRequest("GET", "https://api.bitbucket.org/2.0/repositories", nil)
-	if err != nil {
-		return repos, err
-	}
+	q := make(map[string]string)
+	q["role"] = role
+	q["pagelen"] = strconv.Itoa(defaultPageLen)
+	nextURL := "https://api.bitbucket.org/2.0/repositories?" + encodeQuery(q)
 
-	// headers
-	req.Header.Set("Authorization", "Bearer "+key)
+	var repos []Repo
+	for nextURL != "" {
+		if maxRepos > 0 && len(repos) >= maxRepos {
+			break
+		}
 
-	// add query params
-	q := req.URL.Query()
-	q.Add("role", role)
-	q.Add("pagelen", "100")
-	req.URL.RawQuery = q.Encode()
+		page, err := fetchRepoPage(client, key, nextURL)
+		if err != nil {
+			return nil, err
+		}
 
-	// response
-	resp, err := client.Do(req)
-	if err != nil {
-		return repos, err
+		repos = append(repos, page.Values...)
+		nextURL = page.Next
 	}
-	defer resp.Body.Close()
 
-	// parse response body
-	err = json.NewDecoder(resp.Body).Decode(&repos)
-	if err != nil {
-		return repos, err
+	if maxRepos > 0 && len(repos) > maxRepos {
+		repos = repos[:maxRepos]
 	}
-
 	return repos, nil
 }
 
-func getAllRepos(cfg *config.Config, key string) ([]Repo, error) {
-	roles := []string{"member", "contributor", "admin", "owner"}
+// fetchRepoPage fetches a single page from url, retrying with exponential
+// backoff - honoring Retry-After when Bitbucket sends one - if it's
+// rate-limited.
+func fetchRepoPage(client *http.Client, key, url string) (RepoJSON, error) {
+	var repos RepoJSON
 
-	var allRepos = make(map[string]Repo, 0)
-	for _, role := range roles {
-		repos, err := getRepositories(cfg, key, role)
+	backoff := rateLimitBaseBackoff
+	for attempt := 0; attempt <= maxPageRetries; attempt++ {
+		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
-			return nil, err
+			return repos, err
+		}
+		req.Header.Set("Authorization", "Bearer "+key)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return repos, err
 		}
-		// purposefully overwriting, so that get the most permissive role
-		for _, repo := range repos.Values {
-			repo.Role = role
-			allRepos[repo.FullName] = repo
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			resp.Body.Close()
+			wait := backoff
+			if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+				if secs, err := strconv.Atoi(retryAfter); err == nil {
+					wait = time.Duration(secs) * time.Second
+				}
+			}
+			time.Sleep(wait)
+			backoff *= 2
+			continue
 		}
+
+		err = json.NewDecoder(resp.Body).Decode(&repos)
+		resp.Body.Close()
+		if err != nil {
+			return repos, err
+		}
+		return repos, nil
 	}
+
+	return repos, fmt.Errorf("exceeded %d retries fetching %s: rate limited", maxPageRetries, url)
+}
+
+// encodeQuery is a tiny url.Values-style encoder kept local so
+// fetchRepoPage's retries can be handed the exact next URL Bitbucket
+// returns without re-parsing and re-encoding it.
+func encodeQuery(q map[string]string) string {
+	values := make([]string, 0, len(q))
+	for k, v := range q {
+		values = append(values, k+"="+v)
+	}
+	sort.Strings(values)
+	return strings.Join(values, "&")
+}
+
+func getAllRepos(cfg *config.Config, key string) ([]Repo, error) {
+	roles := []string{"member", "contributor", "admin", "owner"}
+
+	var mu sync.Mutex
+	allRepos := make(map[string]Repo)
+
+	g := &errgroup.Group{}
+	g.SetLimit(roleConcurrency)
+
+	for _, role := range roles {
+		role := role
+		g.Go(func() error {
+			repos, err := getRepositories(cfg, key, role, cfg.MaxRepos)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, repo := range repos {
+				// keep whichever role is more permissive, regardless of
+				// which goroutine's results are merged first
+				if existing, ok := allRepos[repo.FullName]; ok && rolePriority[existing.Role] >= rolePriority[role] {
+					continue
+				}
+				repo.Role = role
+				allRepos[repo.FullName] = repo
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
 	repoSlice := make([]Repo, 0, len(allRepos))
 	for _, repo := range allRepos {
 		repoSlice = append(repoSlice, repo)
 	}
+	if cfg.MaxRepos > 0 && len(repoSlice) > cfg.MaxRepos {
+		repoSlice = repoSlice[:cfg.MaxRepos]
+	}
 	return repoSlice, nil
 }
 
@@ -224,8 +336,7 @@ func AnalyzePermissions(cfg *config.Config, key string) (*SecretInfo, error) {
 		return nil, err
 	}
 
-	// get all repos available to user
-	// ToDo: pagination
+	// get all repos available to user, paginating through every role
 	repos, err := getAllRepos(cfg, key)
 	if err != nil {
 		return nil, err
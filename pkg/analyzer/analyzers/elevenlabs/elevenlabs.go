@@ -126,7 +126,7 @@ func secretInfoToAnalyzerResult(info *SecretInfo) *analyzers.AnalyzerResult {
 	result := analyzers.AnalyzerResult{
 		AnalyzerType: analyzers.AnalyzerTypeElevenLabs,
 		Metadata:     map[string]any{},
-		Bindings:     make([]analyzers.Binding, len(info.Permissions)),
+		Bindings:     make([]analyzers.Binding, 0, len(info.Permissions)),
 	}
 
 	// extract information from resource to create bindings and append to result bindings
@@ -203,30 +203,11 @@ func validateKey(client *http.Client, key string, secretInfo *SecretInfo) (*Secr
 	return nil, false, fmt.Errorf("unexpected status code: %d", statusCode)
 }
 
-// getResources gather resources the key can access
+// getResources runs the permission probe matrix (see probes.go) to discover
+// every resource type the key can read or write, beyond the UserRead check
+// already done in validateKey.
 func getResources(client *http.Client, key string, secretInfo *SecretInfo) (*SecretInfo, error) {
-	// history
-	var err error
-	secretInfo, err = getHistory(client, key, secretInfo)
-	if err != nil {
-		return secretInfo, err
-	}
-
-	secretInfo, err = deleteHistory(client, key, secretInfo)
-	if err != nil {
-		return secretInfo, err
-	}
-	// dubbings
-	// voices
-	// projects
-	// samples
-	// pronunciation dictionaries
-	// models
-	// audio native
-	// text to speech
-	// voice changer
-	// audio isolation
-
+	runProbeMatrix(client, key, secretInfo)
 	return secretInfo, nil
 }
 
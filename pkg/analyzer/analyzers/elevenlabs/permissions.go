@@ -0,0 +1,51 @@
+package elevenlabs
+
+// Permission is an ElevenLabs API scope that a key may or may not have been
+// granted.
+type Permission int
+
+const (
+	UserRead Permission = iota
+	TextToSpeech
+	SpeechToSpeech
+	SoundGeneration
+	AudioIsolation
+	DubbingRead
+	DubbingWrite
+	ProjectsRead
+	ProjectsWrite
+	AudioNativeRead
+	AudioNativeWrite
+	PronunciationDictionariesRead
+	PronunciationDictionariesWrite
+	VoicesRead
+	VoicesWrite
+	ModelsRead
+	SpeechHistoryRead
+	SpeechHistoryWrite
+	WorkspaceWrite
+)
+
+// PermissionStrings give the human readable name of each Permission, used in
+// printed output and as the analyzers.Binding permission value.
+var PermissionStrings = map[Permission]string{
+	UserRead:                       "user:read",
+	TextToSpeech:                   "text_to_speech",
+	SpeechToSpeech:                 "speech_to_speech",
+	SoundGeneration:                "sound_generation",
+	AudioIsolation:                 "audio_isolation",
+	DubbingRead:                    "dubbing:read",
+	DubbingWrite:                   "dubbing:write",
+	ProjectsRead:                   "projects:read",
+	ProjectsWrite:                  "projects:write",
+	AudioNativeRead:                "audio_native:read",
+	AudioNativeWrite:               "audio_native:write",
+	PronunciationDictionariesRead:  "pronunciation_dictionaries:read",
+	PronunciationDictionariesWrite: "pronunciation_dictionaries:write",
+	VoicesRead:                     "voices:read",
+	VoicesWrite:                    "voices:write",
+	ModelsRead:                     "models:read",
+	SpeechHistoryRead:              "speech_history:read",
+	SpeechHistoryWrite:             "speech_history:write",
+	WorkspaceWrite:                 "workspace:write",
+}
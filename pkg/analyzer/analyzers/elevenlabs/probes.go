@@ -0,0 +1,205 @@
+package elevenlabs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// probeKind determines how a probe's outcome is turned into a granted/denied
+// verdict.
+type probeKind int
+
+const (
+	// probeKindRead is a plain GET against a read-only endpoint: 200 means
+	// granted, anything else means denied.
+	probeKindRead probeKind = iota
+	// probeKindOptions issues an OPTIONS request, which several ElevenLabs
+	// write endpoints support as a side-effect-free way to check access.
+	probeKindOptions
+	// probeKindWriteDryRun issues a DELETE against a synthetic, non-existent
+	// resource ID. A 404 means the request was authorized but the resource
+	// doesn't exist (granted); a 403 means the key lacks the scope (denied).
+	probeKindWriteDryRun
+)
+
+// probe maps a single ElevenLabs permission scope to the (method, endpoint,
+// expected-status) triple used to test it.
+type probe struct {
+	permission Permission
+	kind       probeKind
+	path       string // relative to the API root; may contain %s for fakeID
+	// parseResources extracts zero or more resources from a successful read
+	// probe's response body. Only meaningful for probeKindRead.
+	parseResources func([]byte) []Resource
+}
+
+// probeMatrix enumerates every permission scope this analyzer can infer,
+// beyond the initial UserRead check done in validateKey.
+var probeMatrix = []probe{
+	{permission: SpeechHistoryRead, kind: probeKindRead, path: "/v1/history", parseResources: parseHistoryResources},
+	{permission: SpeechHistoryWrite, kind: probeKindWriteDryRun, path: "/v1/history/%s"},
+	{permission: VoicesRead, kind: probeKindRead, path: "/v1/voices", parseResources: parseVoiceResources},
+	{permission: VoicesWrite, kind: probeKindWriteDryRun, path: "/v1/voices/%s"},
+	{permission: ModelsRead, kind: probeKindRead, path: "/v1/models"},
+	{permission: ProjectsRead, kind: probeKindRead, path: "/v1/projects", parseResources: parseProjectResources},
+	{permission: ProjectsWrite, kind: probeKindWriteDryRun, path: "/v1/projects/%s"},
+	{permission: DubbingRead, kind: probeKindRead, path: "/v1/dubbing"},
+	{permission: DubbingWrite, kind: probeKindWriteDryRun, path: "/v1/dubbing/%s"},
+	{permission: PronunciationDictionariesRead, kind: probeKindRead, path: "/v1/pronunciation-dictionaries"},
+	{permission: PronunciationDictionariesWrite, kind: probeKindOptions, path: "/v1/pronunciation-dictionaries/%s"},
+	{permission: AudioNativeRead, kind: probeKindRead, path: "/v1/audio-native"},
+	{permission: AudioNativeWrite, kind: probeKindWriteDryRun, path: "/v1/audio-native/%s"},
+	{permission: WorkspaceWrite, kind: probeKindOptions, path: "/v1/workspace/resources"},
+	{permission: TextToSpeech, kind: probeKindOptions, path: "/v1/text-to-speech/%s"},
+	{permission: SpeechToSpeech, kind: probeKindOptions, path: "/v1/speech-to-speech/%s"},
+	{permission: SoundGeneration, kind: probeKindOptions, path: "/v1/sound-generation"},
+	{permission: AudioIsolation, kind: probeKindOptions, path: "/v1/audio-isolation"},
+}
+
+// maxConcurrentProbes bounds how many probe requests run at once, so scanning
+// a single key never opens more than a handful of connections to ElevenLabs.
+const maxConcurrentProbes = 5
+
+// probeResult is the outcome of running a single probe.
+type probeResult struct {
+	permission Permission
+	granted    bool
+	resources  []Resource
+}
+
+// runProbeMatrix executes every probe in probeMatrix concurrently, bounded by
+// maxConcurrentProbes, and folds the confirmed permissions/resources into
+// secretInfo.
+func runProbeMatrix(client *http.Client, key string, secretInfo *SecretInfo) {
+	sem := make(chan struct{}, maxConcurrentProbes)
+	resultsCh := make(chan probeResult, len(probeMatrix))
+
+	var wg sync.WaitGroup
+	for _, p := range probeMatrix {
+		wg.Add(1)
+		go func(p probe) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			resultsCh <- runProbe(client, key, p)
+		}(p)
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	for res := range resultsCh {
+		if !res.granted {
+			continue
+		}
+		secretInfo.Permissions = append(secretInfo.Permissions, PermissionStrings[res.permission])
+		secretInfo.Resources = append(secretInfo.Resources, res.resources...)
+	}
+}
+
+// runProbe executes a single probe and reports whether the key was granted
+// that permission.
+func runProbe(client *http.Client, key string, p probe) probeResult {
+	path := p.path
+	if strings.Contains(path, "%s") {
+		path = fmt.Sprintf(path, fakeID)
+	}
+	url := "https://api.elevenlabs.io" + path
+
+	switch p.kind {
+	case probeKindRead:
+		body, status, err := makeRequest(client, http.MethodGet, url, key)
+		if err != nil || status != http.StatusOK {
+			return probeResult{permission: p.permission}
+		}
+		result := probeResult{permission: p.permission, granted: true}
+		if p.parseResources != nil {
+			result.resources = p.parseResources(body)
+		}
+		return result
+
+	case probeKindOptions:
+		_, status, err := makeRequest(client, http.MethodOptions, url, key)
+		if err != nil {
+			return probeResult{permission: p.permission}
+		}
+		return probeResult{permission: p.permission, granted: status >= 200 && status < 300}
+
+	case probeKindWriteDryRun:
+		body, status, err := makeRequest(client, http.MethodDelete, url, key)
+		if err != nil {
+			return probeResult{permission: p.permission}
+		}
+		switch status {
+		case http.StatusNotFound:
+			// Authorized to attempt the delete, but the synthetic resource
+			// doesn't exist -> granted.
+			return probeResult{permission: p.permission, granted: true}
+		case http.StatusForbidden:
+			// Confirm this 403 is actually a missing-scope denial (as
+			// opposed to some other API-level rejection) before concluding
+			// the permission was denied, same as validateKey does for the
+			// initial key check.
+			if ok, _ := checkErrorStatus(body, MissingPermissions); !ok {
+				return probeResult{permission: p.permission}
+			}
+			return probeResult{permission: p.permission, granted: false}
+		default:
+			return probeResult{permission: p.permission}
+		}
+
+	default:
+		return probeResult{permission: p.permission}
+	}
+}
+
+func parseHistoryResources(body []byte) []Resource {
+	var history HistoryResponse
+	if err := json.Unmarshal(body, &history); err != nil {
+		return nil
+	}
+	resources := make([]Resource, 0, len(history.History))
+	for _, item := range history.History {
+		resources = append(resources, Resource{ID: item.HistoryItemID, Type: "History", Permission: PermissionStrings[SpeechHistoryRead]})
+	}
+	return resources
+}
+
+func parseVoiceResources(body []byte) []Resource {
+	var voices struct {
+		Voices []struct {
+			VoiceID string `json:"voice_id"`
+			Name    string `json:"name"`
+		} `json:"voices"`
+	}
+	if err := json.Unmarshal(body, &voices); err != nil {
+		return nil
+	}
+	resources := make([]Resource, 0, len(voices.Voices))
+	for _, v := range voices.Voices {
+		resources = append(resources, Resource{ID: v.VoiceID, Name: v.Name, Type: "Voice", Permission: PermissionStrings[VoicesRead]})
+	}
+	return resources
+}
+
+func parseProjectResources(body []byte) []Resource {
+	var projects struct {
+		Projects []struct {
+			ProjectID string `json:"project_id"`
+			Name      string `json:"name"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(body, &projects); err != nil {
+		return nil
+	}
+	resources := make([]Resource, 0, len(projects.Projects))
+	for _, p := range projects.Projects {
+		resources = append(resources, Resource{ID: p.ProjectID, Name: p.Name, Type: "Project", Permission: PermissionStrings[ProjectsRead]})
+	}
+	return resources
+}
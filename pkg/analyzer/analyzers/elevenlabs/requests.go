@@ -2,35 +2,16 @@ package elevenlabs
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"slices"
-	"strings"
 )
 
-// permissionToAPIMap contain the API endpoints for each scope/permission
+// permissionToAPIMap contains the API endpoint used to validate the key itself.
+// Every other scope is covered by the probe matrix in probes.go.
 // api docs: https://elevenlabs.io/docs/api-reference/introduction
 var permissionToAPIMap = map[Permission]string{
-	TextToSpeech:                   "https://api.elevenlabs.io/v1/text-to-speech/%s", // require voice id
-	SpeechToSpeech:                 "",
-	SoundGeneration:                "",
-	AudioIsolation:                 "",
-	DubbingRead:                    "",
-	DubbingWrite:                   "",
-	ProjectsRead:                   "",
-	ProjectsWrite:                  "",
-	AudioNativeRead:                "",
-	AudioNativeWrite:               "",
-	PronunciationDictionariesRead:  "",
-	PronunciationDictionariesWrite: "",
-	VoicesRead:                     "",
-	VoicesWrite:                    "",
-	ModelsRead:                     "",
-	SpeechHistoryRead:              "https://api.elevenlabs.io/v1/history",
-	SpeechHistoryWrite:             "https://api.elevenlabs.io/v1/history/%s", // require history item id
-	UserRead:                       "https://api.elevenlabs.io/v1/user",
-	WorkspaceWrite:                 "",
+	UserRead: "https://api.elevenlabs.io/v1/user",
 }
 
 var (
@@ -68,20 +49,11 @@ type HistoryResponse struct {
 	} `json:"history"`
 }
 
-// getAPIUrl return the API Url mapped to the permission
-func getAPIUrl(permission Permission) string {
-	apiUrl := permissionToAPIMap[permission]
-	if strings.Contains(apiUrl, "%s") {
-		return fmt.Sprintf(apiUrl, fakeID)
-	}
-
-	return apiUrl
-}
-
-// makeGetRequest send the GET request to passed url with passed key as API Key and return response body and status code
-func makeGetRequest(client *http.Client, url, key string) ([]byte, int, error) {
-	// create request
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// makeRequest sends a request of the given method to url, authenticated with
+// key, and returns the response body and status code. It's shared by the read,
+// OPTIONS, and write-dry-run probes in probes.go.
+func makeRequest(client *http.Client, method, url, key string) ([]byte, int, error) {
+	req, err := http.NewRequest(method, url, nil)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -112,62 +84,6 @@ func makeGetRequest(client *http.Client, url, key string) ([]byte, int, error) {
 	return responseBodyByte, resp.StatusCode, nil
 }
 
-// getHistory get history item using the key passed and add them to secret info
-func getHistory(client *http.Client, key string, secretInfo *SecretInfo) (*SecretInfo, error) {
-	response, statusCode, err := makeGetRequest(client, getAPIUrl(SpeechHistoryRead), key)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode == http.StatusOK {
-		var history HistoryResponse
-
-		if err := json.Unmarshal(response, &history); err != nil {
-			return nil, err
-		}
-
-		// add history read scope to secret info
-		secretInfo.Permissions = append(secretInfo.Permissions, PermissionStrings[SpeechHistoryRead])
-		// map resource to secret info
-		for _, historyItem := range history.History {
-			secretInfo.Resources = append(secretInfo.Resources, Resource{
-				ID:         historyItem.HistoryItemID,
-				Name:       "", // no name
-				Type:       "History",
-				Permission: PermissionStrings[SpeechHistoryRead],
-			})
-		}
-	}
-
-	return secretInfo, nil
-}
-
-// deleteHistory try to delete a history item. The item must not exist.
-func deleteHistory(client *http.Client, key string, secretInfo *SecretInfo) (*SecretInfo, error) {
-	response, statusCode, err := makeGetRequest(client, getAPIUrl(SpeechHistoryWrite), key)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode >= http.StatusBadRequest && statusCode <= 499 {
-		// check if status in response is not missing permissions
-		ok, err := checkErrorStatus(response, MissingPermissions)
-		if err != nil {
-			return nil, err
-		}
-
-		// if it's missing permissions return
-		if ok {
-			return secretInfo, nil
-		}
-	}
-
-	// add history write scope to secret info
-	secretInfo.Permissions = append(secretInfo.Permissions, PermissionStrings[SpeechHistoryWrite])
-
-	return secretInfo, nil
-}
-
 // checkErrorStatus check if any of expected error status exist in actual API error response
 func checkErrorStatus(response []byte, expectedStatuses ...string) (bool, error) {
 	var errorResp ErrorResponse
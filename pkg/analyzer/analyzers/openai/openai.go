@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/jedib0t/go-pretty/table"
@@ -42,27 +43,114 @@ func secretInfoToAnalyzerResult(info *AnalyzerJSON) *analyzers.AnalyzerResult {
 			"mfa":           strconv.FormatBool(info.me.MfaEnabled),
 			"is_admin":      strconv.FormatBool(info.isAdmin),
 			"is_restricted": strconv.FormatBool(info.isRestricted),
+			"key_class":     string(info.keyClass),
 		},
 	}
 
-	perms := convertPermissions(info.isAdmin, info.perms)
-	for _, org := range info.me.Orgs.Data {
-		resource := analyzers.Resource{
-			Name:               org.Title,
-			FullyQualifiedName: org.ID,
-			Type:               "organization",
-			Metadata: map[string]any{
-				"description": org.Description,
-				"user":        org.User,
-			},
+	switch info.keyClass {
+	case keyClassAdmin:
+		bindAdminKeyResult(&result, info)
+	case keyClassProject:
+		bindProjectKeyResult(&result, info)
+	default:
+		perms := convertPermissions(info.isAdmin, info.perms)
+		for _, org := range info.me.Orgs.Data {
+			resource := analyzers.Resource{
+				Name:               org.Title,
+				FullyQualifiedName: org.ID,
+				Type:               "organization",
+				Metadata: map[string]any{
+					"description": org.Description,
+					"user":        org.User,
+				},
+			}
+			// Copy each permission into this resource.
+			result.Bindings = append(result.Bindings, analyzers.BindAllPermissions(resource, perms...)...)
 		}
-		// Copy each permission into this resource.
-		result.Bindings = append(result.Bindings, analyzers.BindAllPermissions(resource, perms...)...)
 	}
 
 	return &result
 }
 
+// bindAdminKeyResult binds each project the admin key's organization owns,
+// and each organization user alongside their org-level role.
+func bindAdminKeyResult(result *analyzers.AnalyzerResult, info *AnalyzerJSON) {
+	for _, project := range info.projects {
+		resource := analyzers.Resource{
+			Name:               project.Name,
+			FullyQualifiedName: project.ID,
+			Type:               "project",
+			Metadata:           map[string]any{"status": project.Status},
+		}
+		result.Bindings = append(result.Bindings, analyzers.Binding{
+			Resource:   resource,
+			Permission: analyzers.Permission{Value: analyzers.FullAccess},
+		})
+	}
+
+	for _, user := range info.orgUsers {
+		resource := analyzers.Resource{
+			Name:               user.Name,
+			FullyQualifiedName: user.ID,
+			Type:               "organization_user",
+			Metadata:           map[string]any{"email": user.Email},
+		}
+		result.Bindings = append(result.Bindings, analyzers.Binding{
+			Resource:   resource,
+			Permission: analyzers.Permission{Value: user.Role},
+		})
+	}
+}
+
+// bindProjectKeyResult binds the project a project-scoped key belongs to,
+// along with each API key and service account it could enumerate within
+// that project as a member, carrying that member's role.
+func bindProjectKeyResult(result *analyzers.AnalyzerResult, info *AnalyzerJSON) {
+	project := analyzers.Resource{
+		Name:               info.projectID,
+		FullyQualifiedName: info.projectID,
+		Type:               "project",
+		Metadata:           map[string]any{},
+	}
+
+	for _, apiKey := range info.projectAPIKeys {
+		member := analyzers.Resource{
+			Name:               apiKey.Name,
+			FullyQualifiedName: info.projectID + "/" + apiKey.ID,
+			Type:               "project_api_key",
+			Metadata:           map[string]any{"project_id": info.projectID},
+		}
+		result.Bindings = append(result.Bindings, analyzers.Binding{
+			Resource:   member,
+			Permission: analyzers.Permission{Value: "member"},
+		})
+	}
+
+	for _, svcAcct := range info.projectServiceAccounts {
+		member := analyzers.Resource{
+			Name:               svcAcct.Name,
+			FullyQualifiedName: info.projectID + "/" + svcAcct.ID,
+			Type:               "project_service_account",
+			Metadata:           map[string]any{"project_id": info.projectID},
+		}
+		result.Bindings = append(result.Bindings, analyzers.Binding{
+			Resource:   member,
+			Permission: analyzers.Permission{Value: svcAcct.Role},
+		})
+	}
+
+	// A project key's own org-level endpoints commonly return 403 (listing a
+	// project's API keys/service accounts is an admin-key operation in
+	// OpenAI's API), leaving no members to bind. Bind the project itself so
+	// the key is still attributable to it.
+	if len(info.projectAPIKeys) == 0 && len(info.projectServiceAccounts) == 0 {
+		result.Bindings = append(result.Bindings, analyzers.Binding{
+			Resource:   project,
+			Permission: analyzers.Permission{Value: analyzers.NONE},
+		})
+	}
+}
+
 func convertPermissions(isAdmin bool, perms []permissionData) []analyzers.Permission {
 	var permissions []analyzers.Permission
 
@@ -79,11 +167,45 @@ func convertPermissions(isAdmin bool, perms []permissionData) []analyzers.Permis
 }
 
 const (
-	BASE_URL      = "https://api.openai.com"
-	ORGS_ENDPOINT = "/v1/organizations"
-	ME_ENDPOINT   = "/v1/me"
+	BASE_URL        = "https://api.openai.com"
+	ORGS_ENDPOINT   = "/v1/organizations"
+	ME_ENDPOINT     = "/v1/me"
+	MODELS_ENDPOINT = "/v1/models"
+
+	PROJECTS_ENDPOINT  = "/v1/organization/projects"
+	ORG_USERS_ENDPOINT = "/v1/organization/users"
+	// %s is the project ID. These endpoints are themselves admin-key
+	// operations in OpenAI's API, so a project key commonly gets a 403
+	// probing them; analyzeProjectKey treats that as "no members found"
+	// rather than an error.
+	PROJECT_API_KEYS_ENDPOINT_FMT     = "/v1/organization/projects/%s/api_keys"
+	PROJECT_SVC_ACCOUNTS_ENDPOINT_FMT = "/v1/organization/projects/%s/service_accounts"
 )
 
+// keyClass distinguishes the three prefixes OpenAI issues API keys under,
+// each with a different scope of access.
+type keyClass string
+
+const (
+	keyClassUser    keyClass = "user"
+	keyClassProject keyClass = "project"
+	keyClassAdmin   keyClass = "admin"
+)
+
+// classifyKey determines a key's class from its prefix: sk-admin- keys have
+// full organization access, sk-proj- keys are scoped to a single project,
+// and everything else is a legacy user key.
+func classifyKey(key string) keyClass {
+	switch {
+	case strings.HasPrefix(key, "sk-admin-"):
+		return keyClassAdmin
+	case strings.HasPrefix(key, "sk-proj-"):
+		return keyClassProject
+	default:
+		return keyClassUser
+	}
+}
+
 type MeJSON struct {
 	ID         string `json:"id"`
 	Name       string `json:"name"`
@@ -109,11 +231,68 @@ type permissionData struct {
 	status    analyzers.PermissionType
 }
 
+// ProjectJSON is one entry of the admin-key /v1/organization/projects response.
+type ProjectJSON struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type projectsResponse struct {
+	Data []ProjectJSON `json:"data"`
+}
+
+// OrgUserJSON is one entry of the admin-key /v1/organization/users response.
+type OrgUserJSON struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type orgUsersResponse struct {
+	Data []OrgUserJSON `json:"data"`
+}
+
+// ProjectAPIKeyJSON is one entry of a project key's
+// /v1/organization/projects/{id}/api_keys response.
+type ProjectAPIKeyJSON struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type projectAPIKeysResponse struct {
+	Data []ProjectAPIKeyJSON `json:"data"`
+}
+
+// ServiceAccountJSON is one entry of a project key's
+// /v1/organization/projects/{id}/service_accounts response.
+type ServiceAccountJSON struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type serviceAccountsResponse struct {
+	Data []ServiceAccountJSON `json:"data"`
+}
+
 type AnalyzerJSON struct {
 	me           MeJSON
 	isAdmin      bool
 	isRestricted bool
 	perms        []permissionData
+
+	keyClass keyClass
+
+	// Populated for an admin key (keyClassAdmin).
+	projects []ProjectJSON
+	orgUsers []OrgUserJSON
+
+	// Populated for a project key (keyClassProject).
+	projectID              string
+	projectAPIKeys         []ProjectAPIKeyJSON
+	projectServiceAccounts []ServiceAccountJSON
 }
 
 var POST_PAYLOAD = map[string]interface{}{"speed": 1}
@@ -140,6 +319,7 @@ func AnalyzePermissions(cfg *config.Config, key string) (*AnalyzerJSON, error) {
 	data := AnalyzerJSON{
 		isAdmin:      false,
 		isRestricted: false,
+		keyClass:     classifyKey(key),
 	}
 
 	meJSON, err := getUserData(cfg, key)
@@ -148,6 +328,28 @@ func AnalyzePermissions(cfg *config.Config, key string) (*AnalyzerJSON, error) {
 	}
 	data.me = meJSON
 
+	switch data.keyClass {
+	case keyClassAdmin:
+		data.isAdmin = true
+		projects, orgUsers, err := analyzeAdminKey(cfg, key)
+		if err != nil {
+			return nil, fmt.Errorf(err.Error())
+		}
+		data.projects = projects
+		data.orgUsers = orgUsers
+		return &data, nil
+	case keyClassProject:
+		projectID, apiKeys, serviceAccounts, err := analyzeProjectKey(cfg, key)
+		if err != nil {
+			return nil, fmt.Errorf(err.Error())
+		}
+		data.projectID = projectID
+		data.projectAPIKeys = apiKeys
+		data.projectServiceAccounts = serviceAccounts
+		return &data, nil
+	}
+
+	// Legacy user key: keep the existing admin-probe + scope table path.
 	isAdmin, err := checkAdminKey(cfg, key)
 	if err != nil {
 		return nil, fmt.Errorf(err.Error())
@@ -166,6 +368,94 @@ func AnalyzePermissions(cfg *config.Config, key string) (*AnalyzerJSON, error) {
 	return &data, nil
 }
 
+// analyzeAdminKey enumerates an organization's projects and its users'
+// org-level roles using an admin key's elevated access.
+func analyzeAdminKey(cfg *config.Config, key string) ([]ProjectJSON, []OrgUserJSON, error) {
+	//nolint:bodyclose
+	body, resp, err := openAIRequest(cfg, "GET", BASE_URL+PROJECTS_ENDPOINT, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, nil, fmt.Errorf("failed to list organization projects: status %d", resp.StatusCode)
+	}
+	var projects projectsResponse
+	if err := json.Unmarshal(body, &projects); err != nil {
+		return nil, nil, err
+	}
+
+	//nolint:bodyclose
+	body, resp, err = openAIRequest(cfg, "GET", BASE_URL+ORG_USERS_ENDPOINT, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, nil, fmt.Errorf("failed to list organization users: status %d", resp.StatusCode)
+	}
+	var users orgUsersResponse
+	if err := json.Unmarshal(body, &users); err != nil {
+		return nil, nil, err
+	}
+
+	return projects.Data, users.Data, nil
+}
+
+// analyzeProjectKey resolves the project a project-scoped key belongs to,
+// then probes that project's api_keys and service_accounts endpoints. Those
+// endpoints are admin-key operations in OpenAI's API, so a plain project
+// key getting a 403 from either is expected; that's reported as zero
+// members rather than an error.
+func analyzeProjectKey(cfg *config.Config, key string) (string, []ProjectAPIKeyJSON, []ServiceAccountJSON, error) {
+	projectID, err := getProjectID(cfg, key)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if projectID == "" {
+		return "", nil, nil, fmt.Errorf("could not determine project id for project-scoped key")
+	}
+
+	//nolint:bodyclose
+	body, resp, err := openAIRequest(cfg, "GET", BASE_URL+fmt.Sprintf(PROJECT_API_KEYS_ENDPOINT_FMT, projectID), key, nil)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var apiKeys projectAPIKeysResponse
+	if resp.StatusCode == 200 {
+		if err := json.Unmarshal(body, &apiKeys); err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	//nolint:bodyclose
+	body, resp, err = openAIRequest(cfg, "GET", BASE_URL+fmt.Sprintf(PROJECT_SVC_ACCOUNTS_ENDPOINT_FMT, projectID), key, nil)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var serviceAccounts serviceAccountsResponse
+	if resp.StatusCode == 200 {
+		if err := json.Unmarshal(body, &serviceAccounts); err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	return projectID, apiKeys.Data, serviceAccounts.Data, nil
+}
+
+// getProjectID resolves a project-scoped key's own project ID via the
+// OpenAI-Project response header, which OpenAI's API populates on every
+// authenticated response for a project-scoped key.
+func getProjectID(cfg *config.Config, key string) (string, error) {
+	//nolint:bodyclose
+	_, resp, err := openAIRequest(cfg, "GET", BASE_URL+MODELS_ENDPOINT, key, nil)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to resolve project id: status %d", resp.StatusCode)
+	}
+	return resp.Header.Get("OpenAI-Project"), nil
+}
+
 func analyzeScopes(key string) error {
 	for _, scope := range SCOPES {
 		if err := scope.RunTests(key); err != nil {
@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/pb/analyzerpb"
+)
+
+// Client wraps a gRPC connection to an out-of-process analyzer service,
+// letting callers run analyzers without linking pkg/analyzer/analyzers
+// directly into their process.
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  analyzerpb.AnalyzerClient
+}
+
+// NewClient dials target (host:port of a Server) and returns a Client ready
+// to issue Analyze/AnalyzeBatch calls. Callers that need TLS or other
+// transport credentials should pass the matching grpc.DialOption; by default
+// the connection is insecure, matching target being a trusted sidecar.
+func NewClient(target string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{conn: conn, rpc: analyzerpb.NewAnalyzerClient(conn)}, nil
+}
+
+// Close releases the underlying gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Analyze sends a single AnalyzeRequest to the remote analyzer service.
+func (c *Client) Analyze(ctx context.Context, req *analyzerpb.AnalyzeRequest) (*analyzerpb.AnalyzeResponse, error) {
+	return c.rpc.Analyze(ctx, req)
+}
+
+// AnalyzeBatch opens a streaming session that can send many AnalyzeRequests
+// and receive their AnalyzeResponses without a new RPC per credential.
+func (c *Client) AnalyzeBatch(ctx context.Context) (analyzerpb.Analyzer_AnalyzeBatchClient, error) {
+	return c.rpc.AnalyzeBatch(ctx)
+}
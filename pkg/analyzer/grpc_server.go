@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/analyzers"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/analyzers/asana"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/analyzers/auth0"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/analyzers/bitbucket"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/analyzers/elevenlabs"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/analyzers/openai"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/config"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/analyzer/pb/analyzerpb"
+	pbcontext "github.com/trufflesecurity/trufflehog/v3/pkg/context"
+)
+
+// Server implements analyzerpb.AnalyzerServer by dispatching each request to
+// the in-process analyzer registered for its SecretType. This lets the same
+// analyzers run out-of-process or as a sidecar, for language-agnostic
+// consumers or to keep credentials being probed off of the primary scan host.
+type Server struct {
+	analyzerpb.UnimplementedAnalyzerServer
+
+	Cfg *config.Config
+}
+
+// NewServer returns a Server that dispatches requests using cfg, the same
+// config.Config every in-process analyzer already accepts (proxy, HTTP client
+// settings, etc.).
+func NewServer(cfg *config.Config) *Server {
+	return &Server{Cfg: cfg}
+}
+
+// Analyze implements the unary Analyze RPC.
+func (s *Server) Analyze(ctx context.Context, req *analyzerpb.AnalyzeRequest) (*analyzerpb.AnalyzeResponse, error) {
+	a, err := s.analyzerFor(req.GetSecretType())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.Analyze(pbcontext.Context{Context: ctx}, req.GetCredential())
+	if err != nil {
+		return nil, err
+	}
+
+	return analyzerResultToProto(req.GetSecretType(), result), nil
+}
+
+// AnalyzeBatch implements the streaming AnalyzeBatch RPC: each request
+// received on the stream is analyzed and its response sent back in turn.
+func (s *Server) AnalyzeBatch(stream analyzerpb.Analyzer_AnalyzeBatchServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.Analyze(stream.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// analyzerFor returns the in-process analyzer registered for secretType.
+func (s *Server) analyzerFor(secretType analyzerpb.SecretType) (analyzers.Analyzer, error) {
+	switch secretType {
+	case analyzerpb.SecretType_ASANA:
+		return asana.Analyzer{Cfg: s.Cfg}, nil
+	case analyzerpb.SecretType_BITBUCKET:
+		return bitbucket.Analyzer{Cfg: s.Cfg}, nil
+	case analyzerpb.SecretType_OPENAI:
+		return openai.Analyzer{Cfg: s.Cfg}, nil
+	case analyzerpb.SecretType_ELEVENLABS:
+		return elevenlabs.Analyzer{Cfg: s.Cfg}, nil
+	case analyzerpb.SecretType_AUTH0:
+		return auth0.Analyzer{Cfg: s.Cfg}, nil
+	default:
+		return nil, fmt.Errorf("no analyzer registered for secret type %s", secretType)
+	}
+}
+
+// analyzerResultToProto translates an in-process analyzers.AnalyzerResult into
+// the wire format returned by the gRPC service.
+func analyzerResultToProto(secretType analyzerpb.SecretType, result *analyzers.AnalyzerResult) *analyzerpb.AnalyzeResponse {
+	resp := &analyzerpb.AnalyzeResponse{
+		SecretType: secretType,
+		Metadata:   map[string]string{},
+	}
+	if result == nil {
+		return resp
+	}
+
+	for key, value := range result.Metadata {
+		resp.Metadata[key] = fmt.Sprintf("%v", value)
+	}
+
+	for _, binding := range result.Bindings {
+		resp.Bindings = append(resp.Bindings, &analyzerpb.Binding{
+			Resource: &analyzerpb.Resource{
+				Name:               binding.Resource.Name,
+				FullyQualifiedName: binding.Resource.FullyQualifiedName,
+				Type:               binding.Resource.Type,
+				Metadata:           stringifyMetadata(binding.Resource.Metadata),
+			},
+			Permission: &analyzerpb.Permission{Value: binding.Permission.Value},
+		})
+	}
+
+	return resp
+}
+
+func stringifyMetadata(metadata map[string]any) map[string]string {
+	out := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		out[k] = fmt.Sprintf("%v", v)
+	}
+	return out
+}
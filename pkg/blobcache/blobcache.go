@@ -0,0 +1,213 @@
+// Package blobcache implements an on-disk, size-bounded LRU cache keyed by
+// git blob hash. Git objects are content-addressed, so the same binary blob
+// (a vendored .jar, a checked-in .png, ...) often recurs unchanged across
+// thousands of commits in a long-history repository. Rather than re-reading
+// and re-scanning that blob's content at every commit it appears in, callers
+// can memoize the chunks produced the first time a blob is scanned and
+// replay them on every later hit.
+package blobcache
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// entry tracks bookkeeping for one cached blob, enough to support LRU
+// eviction without needing to stat every file in dir on every Put.
+type entry struct {
+	Size       int64
+	LastAccess time.Time
+}
+
+type manifest struct {
+	Entries map[string]entry
+}
+
+// Cache is an on-disk LRU cache of scanned-blob chunks, keyed by the blob's
+// plumbing.Hash (as a hex string, so this package doesn't need to depend on
+// go-git). It is safe for concurrent use.
+type Cache struct {
+	dir          string
+	maxSize      int64
+	manifestPath string
+
+	mu sync.Mutex
+	m  manifest
+}
+
+// New opens (creating if necessary) an on-disk blob cache rooted at dir,
+// bounded to maxSize total bytes of cached chunk data. A maxSize of 0 means
+// unbounded.
+func New(dir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating blob cache dir %s: %w", dir, err)
+	}
+
+	c := &Cache{
+		dir:          dir,
+		maxSize:      maxSize,
+		manifestPath: filepath.Join(dir, "manifest.gob"),
+		m:            manifest{Entries: make(map[string]entry)},
+	}
+	if err := c.loadManifest(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get returns the chunks previously cached for hash, if present, and
+// refreshes its LRU recency.
+func (c *Cache) Get(hash string) (chunks [][]byte, ok bool, err error) {
+	c.mu.Lock()
+	_, tracked := c.m.Entries[hash]
+	c.mu.Unlock()
+	if !tracked {
+		return nil, false, nil
+	}
+
+	f, err := os.Open(c.blobPath(hash))
+	if os.IsNotExist(err) {
+		// Manifest and on-disk state disagree - treat as a miss rather
+		// than failing the whole scan over a cache inconsistency.
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	if err := gob.NewDecoder(f).Decode(&chunks); err != nil {
+		return nil, false, fmt.Errorf("decoding cached blob %s: %w", hash, err)
+	}
+
+	c.mu.Lock()
+	e := c.m.Entries[hash]
+	e.LastAccess = time.Now()
+	c.m.Entries[hash] = e
+	err = c.saveManifestLocked()
+	c.mu.Unlock()
+
+	return chunks, true, err
+}
+
+// Put stores chunks as the cached scan result for hash, evicting the
+// least-recently-used entries afterward if the cache now exceeds maxSize.
+func (c *Cache) Put(hash string, chunks [][]byte) error {
+	path := c.blobPath(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(chunks); err != nil {
+		f.Close()
+		return fmt.Errorf("encoding blob %s for cache: %w", hash, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.m.Entries[hash] = entry{Size: info.Size(), LastAccess: time.Now()}
+	if err := c.saveManifestLocked(); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.mu.Unlock()
+
+	return c.Prune()
+}
+
+// Prune evicts least-recently-used entries until the cache's total size is
+// at or under maxSize. It is safe to call at any time, including from the
+// `trufflehog cache prune` subcommand to force eviction ahead of the next
+// Put.
+func (c *Cache) Prune() error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.totalSizeLocked() > c.maxSize {
+		oldestHash, found := c.oldestLocked()
+		if !found {
+			break
+		}
+		if err := os.Remove(c.blobPath(oldestHash)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("evicting cached blob %s: %w", oldestHash, err)
+		}
+		delete(c.m.Entries, oldestHash)
+	}
+
+	return c.saveManifestLocked()
+}
+
+// TotalSize returns the cache's current total size in bytes.
+func (c *Cache) TotalSize() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.totalSizeLocked()
+}
+
+func (c *Cache) totalSizeLocked() int64 {
+	var total int64
+	for _, e := range c.m.Entries {
+		total += e.Size
+	}
+	return total
+}
+
+func (c *Cache) oldestLocked() (hash string, found bool) {
+	var oldest time.Time
+	for h, e := range c.m.Entries {
+		if !found || e.LastAccess.Before(oldest) {
+			hash, oldest, found = h, e.LastAccess, true
+		}
+	}
+	return hash, found
+}
+
+// blobPath returns where hash's cached chunks are stored on disk, sharded by
+// the first two hex characters so a long-lived cache doesn't end up with an
+// unwieldy number of files in a single directory.
+func (c *Cache) blobPath(hash string) string {
+	prefix := hash
+	if len(prefix) > 2 {
+		prefix = hash[:2]
+	}
+	return filepath.Join(c.dir, prefix, hash+".chunks")
+}
+
+func (c *Cache) loadManifest() error {
+	f, err := os.Open(c.manifestPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewDecoder(f).Decode(&c.m)
+}
+
+func (c *Cache) saveManifestLocked() error {
+	f, err := os.Create(c.manifestPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gob.NewEncoder(f).Encode(c.m)
+}
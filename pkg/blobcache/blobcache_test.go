@@ -0,0 +1,65 @@
+package blobcache
+
+import (
+	"testing"
+)
+
+func TestPutGetRoundTrip(t *testing.T) {
+	c, err := New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	want := [][]byte{[]byte("chunk one"), []byte("chunk two")}
+	if err := c.Put("deadbeef", want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, ok, err := c.Get("deadbeef")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d chunks, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if string(got[i]) != string(want[i]) {
+			t.Fatalf("chunk %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGetMiss(t *testing.T) {
+	c, err := New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, ok, err := c.Get("doesnotexist"); err != nil || ok {
+		t.Fatalf("expected miss, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestPruneEvictsLeastRecentlyUsed(t *testing.T) {
+	c, err := New(t.TempDir(), 600)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := c.Put("first", [][]byte{make([]byte, 512)}); err != nil {
+		t.Fatalf("Put first: %v", err)
+	}
+	if err := c.Put("second", [][]byte{make([]byte, 512)}); err != nil {
+		t.Fatalf("Put second: %v", err)
+	}
+
+	if _, ok, _ := c.Get("first"); ok {
+		t.Fatal("expected first to have been evicted once cache exceeded maxSize")
+	}
+	if _, ok, _ := c.Get("second"); !ok {
+		t.Fatal("expected second, the most recently written entry, to survive")
+	}
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+)
+
+// DetectorID identifies a single configured detector. Version distinguishes
+// multiple configurations of the same detector type (e.g. a detector with
+// several versioned implementations, or multiple custom detectors of the
+// same type), and is zero for detectors addressed purely by type.
+type DetectorID struct {
+	ID      detectorspb.DetectorType
+	Version int
+}
+
+// Tag groups related detector types so they can be selected together with
+// the `@tag` syntax in ParseDetectors, e.g. `@cloud` or `@database`.
+type Tag string
+
+const (
+	TagCloud     Tag = "cloud"
+	TagDatabase  Tag = "database"
+	TagMessaging Tag = "messaging"
+	TagCI        Tag = "ci"
+)
+
+// tagMembers maps each supported tag to the detector types it contains. A
+// detector type may appear under more than one tag.
+var tagMembers = map[Tag][]detectorspb.DetectorType{
+	TagCloud: {
+		detectorspb.DetectorType_AWS,
+		detectorspb.DetectorType_GCP,
+		detectorspb.DetectorType_Azure,
+	},
+	TagDatabase: {
+		detectorspb.DetectorType_Postgres,
+		detectorspb.DetectorType_MySQL,
+		detectorspb.DetectorType_MongoDB,
+	},
+	TagMessaging: {
+		detectorspb.DetectorType_Slack,
+		detectorspb.DetectorType_Twilio,
+	},
+	TagCI: {
+		detectorspb.DetectorType_CircleCI,
+		detectorspb.DetectorType_TravisCI,
+	},
+}
+
+// Tags returns, in a stable order, every tag a detector type belongs to.
+// Used by callers like `--list-detectors --tags` to print the groupings
+// alongside each detector.
+func Tags(id detectorspb.DetectorType) []Tag {
+	var tags []Tag
+	for _, tag := range []Tag{TagCloud, TagDatabase, TagMessaging, TagCI} {
+		for _, member := range tagMembers[tag] {
+			if member == id {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// nameToType resolves a detector name to its type case-insensitively, built
+// once from the generated detectorspb.DetectorType_value map.
+var nameToType = func() map[string]detectorspb.DetectorType {
+	m := make(map[string]detectorspb.DetectorType, len(detectorspb.DetectorType_value))
+	for name, value := range detectorspb.DetectorType_value {
+		m[strings.ToLower(name)] = detectorspb.DetectorType(value)
+	}
+	return m
+}()
+
+// allDetectors returns a DetectorID for every detector type known to
+// detectorspb, in ascending numeric order.
+func allDetectors() []DetectorID {
+	ids := make([]DetectorID, 0, len(detectorspb.DetectorType_name))
+	for i := 0; i < len(detectorspb.DetectorType_name); i++ {
+		ids = append(ids, DetectorID{ID: detectorspb.DetectorType(i)})
+	}
+	return ids
+}
+
+// ParseDetectors parses a comma-separated detector selector into an ordered,
+// deduplicated list of DetectorIDs. Each comma-separated item may be:
+//
+//   - "all" (case-insensitive): every known detector
+//   - a detector name (e.g. "aws") or numeric ID (e.g. "8")
+//   - a range of names or numeric IDs (e.g. "8-9", "github-gitlab"), with an
+//     empty upper bound meaning "through the last detector" (e.g. "10-")
+//   - a category tag (e.g. "@cloud", "@database"), selecting every detector
+//     in that tag's membership
+//
+// The returned order matches first occurrence across the input, and later
+// duplicates (whether from an explicit ID, a range, a tag, or "all") are
+// dropped rather than reordering or repeating an already-seen detector.
+func ParseDetectors(input string) ([]DetectorID, error) {
+	var output []DetectorID
+	seen := make(map[DetectorID]struct{})
+
+	add := func(id DetectorID) {
+		if _, ok := seen[id]; ok {
+			return
+		}
+		seen[id] = struct{}{}
+		output = append(output, id)
+	}
+
+	for _, item := range strings.Split(input, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if strings.EqualFold(item, "all") {
+			for _, id := range allDetectors() {
+				add(id)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(item, "@") {
+			tag := Tag(strings.ToLower(strings.TrimPrefix(item, "@")))
+			members, ok := tagMembers[tag]
+			if !ok {
+				return nil, fmt.Errorf("unknown detector tag: %q", item)
+			}
+			for _, member := range members {
+				add(DetectorID{ID: member})
+			}
+			continue
+		}
+
+		if strings.Contains(item, "-") {
+			parts := strings.SplitN(item, "-", 2)
+			start, err := parseDetectorToken(parts[0])
+			if err != nil {
+				return nil, err
+			}
+
+			end := len(detectorspb.DetectorType_name) - 1
+			if endStr := strings.TrimSpace(parts[1]); endStr != "" {
+				end, err = parseDetectorToken(endStr)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if start <= end {
+				for i := start; i <= end; i++ {
+					add(DetectorID{ID: detectorspb.DetectorType(i)})
+				}
+			} else {
+				for i := start; i >= end; i-- {
+					add(DetectorID{ID: detectorspb.DetectorType(i)})
+				}
+			}
+			continue
+		}
+
+		id, err := parseDetectorToken(item)
+		if err != nil {
+			return nil, err
+		}
+		add(DetectorID{ID: detectorspb.DetectorType(id)})
+	}
+
+	return output, nil
+}
+
+// parseDetectorToken resolves a single token (trimmed of whitespace) to a
+// detector type's numeric ID, either by parsing it as an integer or by
+// looking it up case-insensitively by name. It errors on empty input, a
+// negative or out-of-range integer, or an unrecognized name.
+func parseDetectorToken(token string) (int, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return 0, fmt.Errorf("empty detector token")
+	}
+
+	if n, err := strconv.Atoi(token); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("invalid detector id: %d", n)
+		}
+		if _, ok := detectorspb.DetectorType_name[int32(n)]; !ok {
+			return 0, fmt.Errorf("invalid detector id: %d", n)
+		}
+		return n, nil
+	}
+
+	if id, ok := nameToType[strings.ToLower(token)]; ok {
+		return int(id), nil
+	}
+
+	return 0, fmt.Errorf("invalid detector name: %q", token)
+}
@@ -25,6 +25,10 @@ func TestDetectorParsing(t *testing.T) {
 		"invalid end range":         {"0-1337", nil},
 		"invalid name":              {"foo", nil},
 		"negative":                  {"-1", nil},
+		"cloud tag":                 {"@cloud", []DetectorID{{ID: dpb.DetectorType_AWS}, {ID: dpb.DetectorType_GCP}, {ID: dpb.DetectorType_Azure}}},
+		"tag composes with range":   {"@cloud,8-9", []DetectorID{{ID: dpb.DetectorType_AWS}, {ID: dpb.DetectorType_GCP}, {ID: dpb.DetectorType_Azure}, {ID: dpb.DetectorType_Github}, {ID: dpb.DetectorType_Gitlab}}},
+		"tag dedups with name":      {"aws,@cloud", []DetectorID{{ID: dpb.DetectorType_AWS}, {ID: dpb.DetectorType_GCP}, {ID: dpb.DetectorType_Azure}}},
+		"unknown tag":               {"@nope", nil},
 	}
 
 	for name, tt := range tests {
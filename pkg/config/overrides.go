@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+)
+
+// overrideScope ranks how specific a DetectorOverrides entry is; the
+// lowest-valued matching scope wins when more than one entry matches the
+// same detector.
+type overrideScope int
+
+const (
+	scopeExactVersion overrideScope = iota
+	scopeAnyVersion
+	scopeGroup
+	scopeGlobal
+)
+
+type overrideEntry struct {
+	scope      overrideScope
+	detector   detectorspb.DetectorType
+	version    int
+	minVersion int
+	hasMin     bool
+	group      Tag
+	verify     bool
+}
+
+func (e overrideEntry) matches(id DetectorID) bool {
+	switch e.scope {
+	case scopeExactVersion:
+		return id.ID == e.detector && id.Version == e.version
+	case scopeAnyVersion:
+		if id.ID != e.detector {
+			return false
+		}
+		if e.hasMin {
+			return id.Version >= e.minVersion
+		}
+		return true
+	case scopeGroup:
+		return tagContains(e.group, id.ID)
+	default: // scopeGlobal
+		return true
+	}
+}
+
+func tagContains(tag Tag, id detectorspb.DetectorType) bool {
+	for _, member := range tagMembers[tag] {
+		if member == id {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectorOverrides replaces a plain map[DetectorID]bool of per-detector
+// verify overrides with one that also understands "any version of this
+// detector type", "every detector in this group" (see Tag), and a global
+// catch-all, resolved by precedence: exact version > any-version > group >
+// global.
+type DetectorOverrides struct {
+	entries []overrideEntry
+}
+
+// Exact overrides verify for one exact (type, version) pair.
+func (o *DetectorOverrides) Exact(id DetectorID, verify bool) {
+	o.entries = append(o.entries, overrideEntry{scope: scopeExactVersion, detector: id.ID, version: id.Version, verify: verify})
+}
+
+// AnyVersion overrides verify for every version of detector, optionally
+// restricted to versions >= minVersion (0 for no minimum, i.e. a "*"
+// wildcard matching every version).
+func (o *DetectorOverrides) AnyVersion(detector detectorspb.DetectorType, minVersion int, verify bool) {
+	o.entries = append(o.entries, overrideEntry{
+		scope:      scopeAnyVersion,
+		detector:   detector,
+		minVersion: minVersion,
+		hasMin:     minVersion > 0,
+		verify:     verify,
+	})
+}
+
+// Group overrides verify for every detector belonging to tag.
+func (o *DetectorOverrides) Group(tag Tag, verify bool) {
+	o.entries = append(o.entries, overrideEntry{scope: scopeGroup, group: tag, verify: verify})
+}
+
+// Global overrides verify for every detector with no more specific match.
+func (o *DetectorOverrides) Global(verify bool) {
+	o.entries = append(o.entries, overrideEntry{scope: scopeGlobal, verify: verify})
+}
+
+// Lookup returns the verify override for id and whether one matched at all.
+// When several entries match, the most specific scope wins (exact version >
+// any-version > group > global); a tie between entries of the same scope is
+// broken by registration order, the later entry winning.
+func (o *DetectorOverrides) Lookup(id DetectorID) (verify bool, ok bool) {
+	best := scopeGlobal + 1
+	for _, e := range o.entries {
+		if !e.matches(id) {
+			continue
+		}
+		if e.scope <= best {
+			best = e.scope
+			verify = e.verify
+			ok = true
+		}
+	}
+	return verify, ok
+}
+
+// OverrideEntry is the YAML shape of one entry in a scan config's
+// detector_overrides list.
+type OverrideEntry struct {
+	// Detector is a detector name, optionally suffixed with
+	// ":<version selector>" (e.g. "gitlab", "gitlab:2", "gitlab:*",
+	// "gitlab:>=2"). A selector of "*" or ">=N" overrides every matching
+	// version; an exact or omitted version (default 0) overrides only
+	// that one. Mutually exclusive with Group.
+	Detector string `yaml:"detector,omitempty"`
+	// Group selects every detector in a category tag (e.g. "cloud"),
+	// mutually exclusive with Detector. If both are empty, the entry is a
+	// global catch-all.
+	Group string `yaml:"group,omitempty"`
+	// Verify is the verify decision this entry forces for whatever it
+	// matches.
+	Verify bool `yaml:"verify"`
+}
+
+// BuildDetectorOverrides resolves a scan config's detector_overrides list
+// into a DetectorOverrides, preserving declaration order so later entries
+// can override earlier ones of equal precedence.
+func BuildDetectorOverrides(entries []OverrideEntry) (*DetectorOverrides, error) {
+	overrides := &DetectorOverrides{}
+	for _, entry := range entries {
+		switch {
+		case entry.Detector != "":
+			if err := addDetectorOverride(overrides, entry); err != nil {
+				return nil, err
+			}
+		case entry.Group != "":
+			overrides.Group(Tag(entry.Group), entry.Verify)
+		default:
+			overrides.Global(entry.Verify)
+		}
+	}
+	return overrides, nil
+}
+
+func addDetectorOverride(overrides *DetectorOverrides, entry OverrideEntry) error {
+	name, selector, hasSelector := strings.Cut(entry.Detector, ":")
+
+	id, err := parseDetectorToken(name)
+	if err != nil {
+		return fmt.Errorf("invalid detector_overrides entry %q: %w", entry.Detector, err)
+	}
+	detector := detectorspb.DetectorType(id)
+
+	if !hasSelector {
+		overrides.Exact(DetectorID{ID: detector}, entry.Verify)
+		return nil
+	}
+
+	version, any, minVersion, hasMin, err := ParseVersionSelector(selector)
+	if err != nil {
+		return fmt.Errorf("invalid detector_overrides entry %q: %w", entry.Detector, err)
+	}
+	switch {
+	case any:
+		overrides.AnyVersion(detector, 0, entry.Verify)
+	case hasMin:
+		overrides.AnyVersion(detector, minVersion, entry.Verify)
+	default:
+		overrides.Exact(DetectorID{ID: detector, Version: version}, entry.Verify)
+	}
+	return nil
+}
+
+// ParseVersionSelector parses the version selector suffix of a
+// detector_overrides entry: an exact non-negative integer, "*" (every
+// version), or ">=N" (every version N and above).
+func ParseVersionSelector(s string) (version int, any bool, minVersion int, hasMin bool, err error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case s == "*":
+		return 0, true, 0, false, nil
+	case strings.HasPrefix(s, ">="):
+		n, convErr := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(s, ">=")))
+		if convErr != nil || n < 0 {
+			return 0, false, 0, false, fmt.Errorf("invalid version selector %q", s)
+		}
+		return 0, false, n, true, nil
+	default:
+		n, convErr := strconv.Atoi(s)
+		if convErr != nil || n < 0 {
+			return 0, false, 0, false, fmt.Errorf("invalid version selector %q", s)
+		}
+		return n, false, 0, false, nil
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dpb "github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+)
+
+func TestDetectorOverridesPrecedence(t *testing.T) {
+	overrides := &DetectorOverrides{}
+	overrides.Global(false)
+	overrides.Group(TagCloud, true)
+	overrides.AnyVersion(dpb.DetectorType_Gitlab, 0, false)
+	overrides.AnyVersion(dpb.DetectorType_Gitlab, 2, true) // ">=2"
+	overrides.Exact(DetectorID{ID: dpb.DetectorType_Gitlab, Version: 3}, false)
+
+	tests := map[string]struct {
+		id         DetectorID
+		wantVerify bool
+		wantOK     bool
+	}{
+		"exact version beats every other scope": {
+			id: DetectorID{ID: dpb.DetectorType_Gitlab, Version: 3}, wantVerify: false, wantOK: true,
+		},
+		"any-version >=2 beats the broader any-version entry": {
+			id: DetectorID{ID: dpb.DetectorType_Gitlab, Version: 2}, wantVerify: true, wantOK: true,
+		},
+		"broader any-version entry applies below the >=2 floor": {
+			id: DetectorID{ID: dpb.DetectorType_Gitlab, Version: 1}, wantVerify: false, wantOK: true,
+		},
+		"group applies to an unrelated detector with no detector-specific entry": {
+			id: DetectorID{ID: dpb.DetectorType_AWS}, wantVerify: true, wantOK: true,
+		},
+		"global catch-all applies with no other match": {
+			id: DetectorID{ID: dpb.DetectorType_NpmToken}, wantVerify: false, wantOK: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			verify, ok := overrides.Lookup(tc.id)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantVerify, verify)
+		})
+	}
+}
+
+func TestDetectorOverridesSameScopeLastWins(t *testing.T) {
+	overrides := &DetectorOverrides{}
+	overrides.Exact(DetectorID{ID: dpb.DetectorType_Gitlab}, true)
+	overrides.Exact(DetectorID{ID: dpb.DetectorType_Gitlab}, false)
+
+	verify, ok := overrides.Lookup(DetectorID{ID: dpb.DetectorType_Gitlab})
+	assert.True(t, ok)
+	assert.False(t, verify)
+}
+
+func TestDetectorOverridesNoMatch(t *testing.T) {
+	overrides := &DetectorOverrides{}
+	overrides.Exact(DetectorID{ID: dpb.DetectorType_Gitlab}, true)
+
+	_, ok := overrides.Lookup(DetectorID{ID: dpb.DetectorType_AWS})
+	assert.False(t, ok)
+}
+
+func TestParseVersionSelector(t *testing.T) {
+	tests := map[string]struct {
+		input          string
+		wantVersion    int
+		wantAny        bool
+		wantMinVersion int
+		wantHasMin     bool
+		wantErr        bool
+	}{
+		"exact version":  {input: "2", wantVersion: 2},
+		"wildcard":       {input: "*", wantAny: true},
+		"minimum":        {input: ">=2", wantMinVersion: 2, wantHasMin: true},
+		"minimum spaced": {input: ">= 3", wantMinVersion: 3, wantHasMin: true},
+		"negative":       {input: "-1", wantErr: true},
+		"garbage":        {input: "latest", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, any, minVersion, hasMin, err := ParseVersionSelector(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantVersion, version)
+			assert.Equal(t, tc.wantAny, any)
+			assert.Equal(t, tc.wantMinVersion, minVersion)
+			assert.Equal(t, tc.wantHasMin, hasMin)
+		})
+	}
+}
+
+func TestBuildDetectorOverrides(t *testing.T) {
+	entries := []OverrideEntry{
+		{Detector: "gitlab:>=2", Verify: true},
+		{Detector: "aws", Verify: false},
+		{Group: "database", Verify: true},
+		{Verify: false},
+	}
+
+	overrides, err := BuildDetectorOverrides(entries)
+	assert.NoError(t, err)
+
+	verify, ok := overrides.Lookup(DetectorID{ID: dpb.DetectorType_Gitlab, Version: 2})
+	assert.True(t, ok)
+	assert.True(t, verify)
+
+	verify, ok = overrides.Lookup(DetectorID{ID: dpb.DetectorType_AWS})
+	assert.True(t, ok)
+	assert.False(t, verify)
+
+	_, err = BuildDetectorOverrides([]OverrideEntry{{Detector: "not-a-real-detector"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+)
+
+// VerificationDecision is a per-detector override of whether a scan
+// verifies a detector's findings, independent of the run's global verify
+// flag.
+type VerificationDecision int
+
+const (
+	// VerifyInherit leaves the decision to the run's global verify flag.
+	VerifyInherit VerificationDecision = iota
+	// VerifyForceOn always verifies this detector's findings, even if the
+	// run as a whole has verification disabled.
+	VerifyForceOn
+	// VerifyForceOff never verifies this detector's findings, even if the
+	// run as a whole has verification enabled.
+	VerifyForceOff
+)
+
+// UnmarshalYAML parses the "inherit"/"force_on"/"force_off" strings a scan
+// config uses for a VerificationDecision.
+func (d *VerificationDecision) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+	switch s {
+	case "", "inherit":
+		*d = VerifyInherit
+	case "force_on":
+		*d = VerifyForceOn
+	case "force_off":
+		*d = VerifyForceOff
+	default:
+		return fmt.Errorf("invalid verification decision %q: want \"inherit\", \"force_on\", or \"force_off\"", s)
+	}
+	return nil
+}
+
+// VerificationPolicy overrides how a single detector is allowed to verify
+// its findings: whether it verifies at all, how hard it's allowed to hit
+// the network, and where it's allowed to send requests.
+type VerificationPolicy struct {
+	// Decision is the tri-state verify override for this detector.
+	Decision VerificationDecision `yaml:"decision,omitempty"`
+
+	// QPS is the maximum sustained rate (requests/second) this detector is
+	// allowed to issue verification requests at. Zero means unlimited.
+	QPS float64 `yaml:"qps,omitempty"`
+	// Burst is the token bucket's burst size. Zero falls back to 1 when QPS
+	// is set.
+	Burst int `yaml:"burst,omitempty"`
+	// Concurrency caps how many verification calls for this detector may be
+	// in flight at once. Zero means unlimited.
+	Concurrency int `yaml:"concurrency,omitempty"`
+
+	// AllowedHosts, if non-empty, is the only set of hostnames this
+	// detector's verification requests may reach. An empty set means any
+	// host not explicitly denied is allowed.
+	AllowedHosts []string `yaml:"allowed_hosts,omitempty"`
+	// DeniedHosts is checked before AllowedHosts and always wins: a host
+	// listed here is never reachable regardless of AllowedHosts.
+	DeniedHosts []string `yaml:"denied_hosts,omitempty"`
+	// AllowedCIDRs and DeniedCIDRs apply the same allow/deny logic to the IP
+	// address a verification request would connect to.
+	AllowedCIDRs []string `yaml:"allowed_cidrs,omitempty"`
+	DeniedCIDRs  []string `yaml:"denied_cidrs,omitempty"`
+
+	// Timeout bounds a single verification call for this detector. Zero
+	// falls back to the engine's default verification timeout.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// DetectorPolicyEntry is the YAML shape of one entry in a scan config's
+// verify_policies list: a detector selector paired with the
+// VerificationPolicy that governs it.
+type DetectorPolicyEntry struct {
+	// Detector selects which detector this policy applies to, in the
+	// "name" or "name:version" form accepted by ParseDetectorID (e.g. "aws"
+	// or "gitlab:2" for version 2 of a detector with multiple versioned
+	// implementations).
+	Detector           string `yaml:"detector"`
+	VerificationPolicy `yaml:",inline"`
+}
+
+// VerificationPolicies maps a configured detector to the VerificationPolicy
+// that governs it. A detector with no entry behaves exactly as it did
+// before VerificationPolicy existed: it inherits the run's global verify
+// flag and is subject to no extra rate limit, concurrency cap, or network
+// scoping.
+type VerificationPolicies map[DetectorID]VerificationPolicy
+
+// BuildVerificationPolicies resolves a scan config's verify_policies list
+// into a VerificationPolicies lookup, keyed by the DetectorID each entry's
+// Detector selector resolves to.
+func BuildVerificationPolicies(entries []DetectorPolicyEntry) (VerificationPolicies, error) {
+	policies := make(VerificationPolicies, len(entries))
+	for _, entry := range entries {
+		id, err := ParseDetectorID(entry.Detector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verify_policies entry %q: %w", entry.Detector, err)
+		}
+		policies[id] = entry.VerificationPolicy
+	}
+	return policies, nil
+}
+
+// Decision returns the tri-state verify override configured for id,
+// defaulting to VerifyInherit if id has no policy.
+func (p VerificationPolicies) Decision(id DetectorID) VerificationDecision {
+	return p[id].Decision
+}
+
+// AllowsHost reports whether id's policy permits a verification request to
+// reach host. A detector with no policy, or a policy with an empty
+// AllowedHosts and AllowedCIDRs, permits any host not explicitly denied.
+func (p VerificationPolicies) AllowsHost(id DetectorID, host string) bool {
+	policy, ok := p[id]
+	if !ok {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	for _, denied := range policy.DeniedHosts {
+		if denied == host {
+			return false
+		}
+	}
+	if ip != nil {
+		for _, cidr := range policy.DeniedCIDRs {
+			if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(ip) {
+				return false
+			}
+		}
+	}
+
+	if len(policy.AllowedHosts) == 0 && len(policy.AllowedCIDRs) == 0 {
+		return true
+	}
+
+	for _, allowed := range policy.AllowedHosts {
+		if allowed == host {
+			return true
+		}
+	}
+	if ip != nil {
+		for _, cidr := range policy.AllowedCIDRs {
+			if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseDetectorID resolves a single "name" or "name:version" selector (e.g.
+// "aws" or "custom_regex:2") to a DetectorID. Version defaults to 0 if
+// omitted.
+func ParseDetectorID(selector string) (DetectorID, error) {
+	name := selector
+	version := 0
+
+	if i := strings.LastIndex(selector, ":"); i != -1 {
+		name = selector[:i]
+		n, err := strconv.Atoi(selector[i+1:])
+		if err != nil || n < 0 {
+			return DetectorID{}, fmt.Errorf("invalid detector version %q", selector[i+1:])
+		}
+		version = n
+	}
+
+	id, err := parseDetectorToken(name)
+	if err != nil {
+		return DetectorID{}, err
+	}
+
+	return DetectorID{ID: detectorspb.DetectorType(id), Version: version}, nil
+}
@@ -0,0 +1,177 @@
+package decoders
+
+import (
+	"bytes"
+	"encoding/ascii85"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+type Ascii85 struct{}
+
+func (d *Ascii85) Type() detectorspb.DecoderType {
+	return detectorspb.DecoderType_ASCII85
+}
+
+// ascii85Threshold is the minimum run length of bare (undelimited)
+// ascii85-alphabet characters that's treated as a candidate encoded
+// substring.
+const ascii85Threshold = 20
+
+var (
+	ascii85Open  = []byte("<~")
+	ascii85Close = []byte("~>")
+
+	// ascii85BareCharsetMapping covers the standard '!'-'u' alphabet plus
+	// 'z', the shorthand for a run of four zero bytes.
+	ascii85BareCharsetMapping = buildAscii85BareCharsetMapping()
+)
+
+func buildAscii85BareCharsetMapping() [128]bool {
+	var m [128]bool
+	for c := byte('!'); c <= 'u'; c++ {
+		m[c] = true
+	}
+	m['z'] = true
+	return m
+}
+
+func (d *Ascii85) FromChunk(ctx context.Context, chunk *sources.Chunk) *DecodableChunk {
+	out, _ := d.FromChunkWithRegions(ctx, chunk)
+	return out
+}
+
+// FromChunkWithRegions decodes <~ ~>-delimited ascii85 blocks, which are
+// unambiguous, and only falls back to scanning for bare (undelimited) runs
+// when chunk contains no delimited blocks at all - bare ascii85's alphabet
+// overlaps almost all of printable ASCII, so scanning for it alongside
+// delimited blocks would make plain delimited secrets indistinguishable
+// from the surrounding prose most chunks also contain.
+func (d *Ascii85) FromChunkWithRegions(ctx context.Context, chunk *sources.Chunk) (*DecodableChunk, []DecodedRegion) {
+	if chunk == nil {
+		return nil, nil
+	}
+	if out, regions := decodeAscii85Delimited(chunk); out != nil {
+		return out, regions
+	}
+	return decodeAscii85Bare(chunk)
+}
+
+func decodeAscii85Delimited(chunk *sources.Chunk) (*DecodableChunk, []DecodedRegion) {
+	data := chunk.Data
+
+	var result bytes.Buffer
+	result.Grow(len(data))
+	var regions []DecodedRegion
+	decodedAny := false
+
+	pos := 0
+	for pos < len(data) {
+		openIdx := bytes.Index(data[pos:], ascii85Open)
+		if openIdx == -1 {
+			break
+		}
+		openIdx += pos
+		encodedStart := openIdx + len(ascii85Open)
+
+		closeIdx := bytes.Index(data[encodedStart:], ascii85Close)
+		if closeIdx == -1 {
+			break
+		}
+		closeIdx += encodedStart
+		runEnd := closeIdx + len(ascii85Close)
+
+		encoded := data[encodedStart:closeIdx]
+		dst := make([]byte, len(encoded))
+		n, _, err := ascii85.Decode(dst, encoded, true)
+		if err != nil || n == 0 {
+			pos = runEnd
+			continue
+		}
+
+		result.Write(data[pos:openIdx])
+		decodedStart := result.Len()
+		result.Write(dst[:n])
+		regions = append(regions, DecodedRegion{
+			OriginalStart: openIdx,
+			OriginalEnd:   runEnd,
+			DecodedStart:  decodedStart,
+			DecodedEnd:    result.Len(),
+			Decoder:       decoderName(detectorspb.DecoderType_ASCII85),
+		})
+		pos = runEnd
+		decodedAny = true
+	}
+
+	if !decodedAny {
+		return nil, nil
+	}
+	result.Write(data[pos:])
+
+	chunkCopy := *chunk
+	chunkCopy.Data = result.Bytes()
+	return &DecodableChunk{Chunk: &chunkCopy, DecoderType: detectorspb.DecoderType_ASCII85}, regions
+}
+
+func decodeAscii85Bare(chunk *sources.Chunk) (*DecodableChunk, []DecodedRegion) {
+	data := chunk.Data
+
+	var result bytes.Buffer
+	result.Grow(len(data))
+	var regions []DecodedRegion
+	decodedAny := false
+
+	pos := 0
+	count, start := 0, 0
+	flush := func() {
+		if count <= ascii85Threshold {
+			count = 0
+			return
+		}
+		runStart := start
+		src := data[runStart : runStart+count]
+		dst := make([]byte, len(src))
+		ndst, nsrc, _ := ascii85.Decode(dst, src, true)
+		if ndst == 0 || nsrc == 0 {
+			count = 0
+			return
+		}
+
+		result.Write(data[pos:runStart])
+		decodedStart := result.Len()
+		result.Write(dst[:ndst])
+		regions = append(regions, DecodedRegion{
+			OriginalStart: runStart,
+			OriginalEnd:   runStart + nsrc,
+			DecodedStart:  decodedStart,
+			DecodedEnd:    result.Len(),
+			Decoder:       decoderName(detectorspb.DecoderType_ASCII85),
+		})
+		pos = runStart + nsrc
+		decodedAny = true
+		count = 0
+	}
+
+	for i, b := range data {
+		if b < 128 && ascii85BareCharsetMapping[b] {
+			if count == 0 {
+				start = i
+			}
+			count++
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	if !decodedAny {
+		return nil, nil
+	}
+	result.Write(data[pos:])
+
+	chunkCopy := *chunk
+	chunkCopy.Data = result.Bytes()
+	return &DecodableChunk{Chunk: &chunkCopy, DecoderType: detectorspb.DecoderType_ASCII85}, regions
+}
@@ -0,0 +1,117 @@
+package decoders
+
+import (
+	"bytes"
+	"encoding/base32"
+	"strings"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+type Base32 struct{}
+
+func (d *Base32) Type() detectorspb.DecoderType {
+	return detectorspb.DecoderType_BASE32
+}
+
+// base32Threshold is the minimum run length of base32-alphabet characters
+// that's treated as a candidate encoded substring.
+const base32Threshold = 20
+
+var base32CharsetMapping = buildCharsetMapping([]byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz234567="))
+
+func (d *Base32) FromChunk(ctx context.Context, chunk *sources.Chunk) *DecodableChunk {
+	out, _ := d.FromChunkWithRegions(ctx, chunk)
+	return out
+}
+
+func (d *Base32) FromChunkWithRegions(ctx context.Context, chunk *sources.Chunk) (*DecodableChunk, []DecodedRegion) {
+	if chunk == nil {
+		return nil, nil
+	}
+	data := chunk.Data
+
+	var result bytes.Buffer
+	result.Grow(len(data))
+	var regions []DecodedRegion
+	decodedAny := false
+
+	pos := 0
+	count, start := 0, 0
+	flush := func() {
+		if count <= base32Threshold {
+			count = 0
+			return
+		}
+		runStart, runLen := start, count
+		str := string(data[runStart : runStart+runLen])
+
+		dec, ok := decodeBase32String(str)
+		if !ok || len(dec) == 0 {
+			count = 0
+			return
+		}
+
+		result.Write(data[pos:runStart])
+		decodedStart := result.Len()
+		result.Write(dec)
+		regions = append(regions, DecodedRegion{
+			OriginalStart: runStart,
+			OriginalEnd:   runStart + runLen,
+			DecodedStart:  decodedStart,
+			DecodedEnd:    result.Len(),
+			Decoder:       decoderName(d.Type()),
+		})
+		pos = runStart + runLen
+		decodedAny = true
+		count = 0
+	}
+
+	for i, b := range data {
+		if b < 128 && base32CharsetMapping[b] {
+			if count == 0 {
+				start = i
+			}
+			count++
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	if !decodedAny {
+		return nil, nil
+	}
+	result.Write(data[pos:])
+
+	chunkCopy := *chunk
+	chunkCopy.Data = result.Bytes()
+	return &DecodableChunk{Chunk: &chunkCopy, DecoderType: detectorspb.DecoderType_BASE32}, regions
+}
+
+// decodeBase32String decodes str as RFC 4648 base32, tolerating lowercase
+// letters and missing padding.
+func decodeBase32String(str string) ([]byte, bool) {
+	upper := strings.ToUpper(str)
+	if dec, err := base32.StdEncoding.DecodeString(upper); err == nil {
+		return dec, true
+	}
+	if padded := padBase32(upper); padded != upper {
+		if dec, err := base32.StdEncoding.DecodeString(padded); err == nil {
+			return dec, true
+		}
+	}
+	return nil, false
+}
+
+// padBase32 re-pads s, which may be missing its trailing `=` characters, to
+// a multiple of 8 base32 digits.
+func padBase32(s string) string {
+	trimmed := strings.TrimRight(s, "=")
+	if m := len(trimmed) % 8; m != 0 {
+		return trimmed + strings.Repeat("=", 8-m)
+	}
+	return trimmed
+}
@@ -2,51 +2,201 @@ package decoders
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/base64"
+	"io"
+	"strings"
 
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
 )
 
 type Base64 struct{}
 
+func (d *Base64) Type() detectorspb.DecoderType {
+	return detectorspb.DecoderType_BASE64
+}
+
+const (
+	// base64Threshold is the minimum run length of base64-alphabet
+	// characters that's treated as a candidate encoded substring.
+	base64Threshold = 20
+
+	// maxBase64RecursionDepth bounds how many times a decoded region is
+	// fed back into the decoder, so a maliciously layered chunk can't
+	// recurse forever.
+	maxBase64RecursionDepth = 3
+
+	// maxBase64InflatedSize caps how much a single gzip/zlib payload found
+	// inside decoded base64 is allowed to expand to, so a crafted stream
+	// can't be used as a zip bomb.
+	maxBase64InflatedSize = 50 * 1024 * 1024 // 50 MiB
+)
+
 var (
-	b64Charset  = []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=")
-	b64EndChars = "+/="
+	b64Charset    = []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=")
+	b64URLCharset = []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_=")
+	b64EndChars   = "+/=-_"
+
+	gzipMagic = []byte{0x1f, 0x8b}
+	zlibMagic = []byte{0x78, 0x9c}
 )
 
-func (d *Base64) FromChunk(chunk *sources.Chunk) *sources.Chunk {
+func (d *Base64) FromChunk(ctx context.Context, chunk *sources.Chunk) *DecodableChunk {
+	out, _ := d.FromChunkWithRegions(ctx, chunk)
+	return out
+}
+
+func (d *Base64) FromChunkWithRegions(ctx context.Context, chunk *sources.Chunk) (*DecodableChunk, []DecodedRegion) {
+	if chunk == nil {
+		return nil, nil
+	}
+
+	decoded, regions := decodeBase64LayerWithRegions(chunk.Data, 0)
+	if decoded == nil {
+		return nil, nil
+	}
+
+	chunkCopy := *chunk
+	chunkCopy.Data = decoded
+	return &DecodableChunk{Chunk: &chunkCopy, DecoderType: detectorspb.DecoderType_BASE64}, regions
+}
 
-	encodedSubstrings := getSubstringsOfCharacterSet(chunk.Data, 20)
-	decodedSubstrings := make(map[string][]byte)
+// decodeBase64Layer finds base64 (standard or URL-safe) substrings in data
+// and decodes each one, transparently inflating any gzip/zlib-compressed
+// payload it finds, and recursing into the decoded bytes - up to
+// maxBase64RecursionDepth times - to peel layered encodings. It returns nil
+// if nothing in data decoded.
+func decodeBase64Layer(data []byte, depth int) []byte {
+	decoded, _ := decodeBase64LayerWithRegions(data, depth)
+	return decoded
+}
+
+// decodeBase64LayerWithRegions does the work of decodeBase64Layer, and
+// additionally reports the provenance of each decoded substring relative
+// to data. Regions only cover this call's own substitutions - recursion
+// into a decoded substring's own nested encodings (also bounded by
+// maxBase64RecursionDepth) is folded into that substring's bytes, not
+// reported as further regions of its own, since a nested region's offsets
+// would be relative to the decoded substring rather than data.
+func decodeBase64LayerWithRegions(data []byte, depth int) ([]byte, []DecodedRegion) {
+	encodedSubstrings := getSubstringsOfCharacterSet(data, base64Threshold)
+	decodedSubstrings := make(map[string][]byte, len(encodedSubstrings))
+	decoderBySubstring := make(map[string]string, len(encodedSubstrings))
 
 	for _, str := range encodedSubstrings {
-		dec, err := base64.StdEncoding.DecodeString(str)
-		if err == nil && len(dec) > 0 {
-			decodedSubstrings[str] = dec
+		dec, ok := decodeBase64String(str)
+		if !ok || len(dec) == 0 {
+			continue
 		}
+
+		name := "base64"
+		if inflated := inflateKnownCompression(dec); !bytes.Equal(inflated, dec) {
+			switch {
+			case bytes.HasPrefix(dec, gzipMagic):
+				name = "gzip+base64"
+			case bytes.HasPrefix(dec, zlibMagic):
+				name = "zlib+base64"
+			}
+			dec = inflated
+		}
+		if depth < maxBase64RecursionDepth {
+			if nested := decodeBase64Layer(dec, depth+1); nested != nil {
+				dec = nested
+			}
+		}
+		decodedSubstrings[str] = dec
+		decoderBySubstring[str] = name
 	}
 
-	if len(decodedSubstrings) > 0 {
-		var result bytes.Buffer
-		result.Grow(len(chunk.Data))
+	if len(decodedSubstrings) == 0 {
+		return nil, nil
+	}
 
-		start := 0
-		for _, encoded := range encodedSubstrings {
-			if decoded, ok := decodedSubstrings[encoded]; ok {
-				end := bytes.Index(chunk.Data[start:], []byte(encoded))
-				if end != -1 {
-					result.Write(chunk.Data[start : start+end])
-					result.Write(decoded)
-					start += end + len(encoded)
-				}
-			}
+	var result bytes.Buffer
+	result.Grow(len(data))
+	var regions []DecodedRegion
+
+	start := 0
+	for _, encoded := range encodedSubstrings {
+		decoded, ok := decodedSubstrings[encoded]
+		if !ok {
+			continue
 		}
-		result.Write(chunk.Data[start:])
-		chunk.Data = result.Bytes()
-		return chunk
+		end := bytes.Index(data[start:], []byte(encoded))
+		if end == -1 {
+			continue
+		}
+
+		result.Write(data[start : start+end])
+		runStart := start + end
+		decodedStart := result.Len()
+		result.Write(decoded)
+		regions = append(regions, DecodedRegion{
+			OriginalStart: runStart,
+			OriginalEnd:   runStart + len(encoded),
+			DecodedStart:  decodedStart,
+			DecodedEnd:    result.Len(),
+			Decoder:       decoderBySubstring[encoded],
+		})
+		start = runStart + len(encoded)
 	}
+	result.Write(data[start:])
+	return result.Bytes(), regions
+}
 
-	return nil
+// decodeBase64String decodes str as standard base64. If that fails and str
+// looks URL-safe (it contains `-` or `_`), it falls back to the URL-safe
+// alphabet, trying the raw (unpadded) variant when str has no `=` and the
+// padded variant otherwise.
+func decodeBase64String(str string) ([]byte, bool) {
+	if dec, err := base64.StdEncoding.DecodeString(str); err == nil {
+		return dec, true
+	}
+
+	if !strings.ContainsAny(str, "-_") {
+		return nil, false
+	}
+	if strings.Contains(str, "=") {
+		if dec, err := base64.URLEncoding.DecodeString(str); err == nil {
+			return dec, true
+		}
+		return nil, false
+	}
+	if dec, err := base64.RawURLEncoding.DecodeString(str); err == nil {
+		return dec, true
+	}
+	return nil, false
+}
+
+// inflateKnownCompression returns data inflated if it starts with a gzip or
+// zlib magic number, and data unchanged otherwise (including when
+// decompression fails, since a magic-number match on arbitrary decoded
+// bytes is only a hint, not a guarantee).
+func inflateKnownCompression(data []byte) []byte {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return data
+		}
+		defer gr.Close()
+		if inflated, err := io.ReadAll(io.LimitReader(gr, maxBase64InflatedSize)); err == nil && len(inflated) > 0 {
+			return inflated
+		}
+	case bytes.HasPrefix(data, zlibMagic):
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return data
+		}
+		defer zr.Close()
+		if inflated, err := io.ReadAll(io.LimitReader(zr, maxBase64InflatedSize)); err == nil && len(inflated) > 0 {
+			return inflated
+		}
+	}
+	return data
 }
 
 func getSubstringsOfCharacterSet(data []byte, threshold int) []string {
@@ -56,10 +206,14 @@ func getSubstringsOfCharacterSet(data []byte, threshold int) []string {
 
 	// Given characters are mostly ASCII, we can use a simple array to map.
 	var b64CharsetMapping [128]bool
-	// Build an array of all the characters in the base64 charset.
+	// Build an array of all the characters in the standard and URL-safe
+	// base64 charsets.
 	for _, char := range b64Charset {
 		b64CharsetMapping[char] = true
 	}
+	for _, char := range b64URLCharset {
+		b64CharsetMapping[char] = true
+	}
 
 	count := 0
 	substringsCount := 0
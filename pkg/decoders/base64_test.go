@@ -0,0 +1,135 @@
+package decoders
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+func TestBase64FromChunk(t *testing.T) {
+	secret := "this-is-a-fairly-long-secret-value-1234567890"
+
+	gzipSecret := func(s string) []byte {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		_, _ = gw.Write([]byte(s))
+		_ = gw.Close()
+		return buf.Bytes()
+	}
+
+	zlibSecret := func(s string) []byte {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		_, _ = zw.Write([]byte(s))
+		_ = zw.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "standard base64",
+			input: "prefix " + base64.StdEncoding.EncodeToString([]byte(secret)) + " suffix",
+			want:  "prefix " + secret + " suffix",
+		},
+		{
+			name:  "raw url-safe base64 (no padding)",
+			input: "prefix " + base64.RawURLEncoding.EncodeToString([]byte(secret)) + " suffix",
+			want:  "prefix " + secret + " suffix",
+		},
+		{
+			name:  "padded url-safe base64",
+			input: "prefix " + base64.URLEncoding.EncodeToString([]byte(secret)) + " suffix",
+			want:  "prefix " + secret + " suffix",
+		},
+		{
+			name:  "gzip wrapped in base64",
+			input: "prefix " + base64.StdEncoding.EncodeToString(gzipSecret(secret)) + " suffix",
+			want:  "prefix " + secret + " suffix",
+		},
+		{
+			name:  "zlib wrapped in base64",
+			input: "prefix " + base64.StdEncoding.EncodeToString(zlibSecret(secret)) + " suffix",
+			want:  "prefix " + secret + " suffix",
+		},
+		{
+			name:  "double base64 encoded",
+			input: "prefix " + base64.StdEncoding.EncodeToString([]byte(base64.StdEncoding.EncodeToString([]byte(secret)))) + " suffix",
+			want:  "prefix " + secret + " suffix",
+		},
+		{
+			name:  "no base64 present",
+			input: "just some plain text without anything encoded",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunk := &sources.Chunk{Data: []byte(tt.input)}
+			d := &Base64{}
+			got := d.FromChunk(context.Background(), chunk)
+
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("expected no decoded chunk, got %q", string(got.Data))
+				}
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("expected decoded chunk containing %q, got nil", tt.want)
+			}
+			if !strings.Contains(string(got.Data), tt.want) {
+				t.Fatalf("decoded chunk = %q, want it to contain %q", string(got.Data), tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBase64Layer_BoundsRecursion(t *testing.T) {
+	secret := "bounded-recursion-secret-value-0987654321"
+	data := []byte(secret)
+	for i := 0; i < maxBase64RecursionDepth+2; i++ {
+		data = []byte(base64.StdEncoding.EncodeToString(data))
+	}
+
+	decoded := decodeBase64Layer(data, 0)
+	if decoded == nil {
+		t.Fatal("expected at least one layer to decode")
+	}
+	if strings.Contains(string(decoded), secret) {
+		t.Fatalf("expected recursion to stop before fully unwrapping %d layers at depth %d", maxBase64RecursionDepth+2, maxBase64RecursionDepth)
+	}
+}
+
+func BenchmarkBase64FromChunk(b *testing.B) {
+	secret := base64.StdEncoding.EncodeToString([]byte("a reasonably long secret-ish value used for benchmarking"))
+	data := []byte(strings.Repeat("x", 50) + secret + strings.Repeat("y", 50))
+	chunk := &sources.Chunk{Data: data}
+	d := &Base64{}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.FromChunk(ctx, &sources.Chunk{Data: chunk.Data})
+	}
+}
+
+func BenchmarkGetSubstringsOfCharacterSet(b *testing.B) {
+	data := []byte(strings.Repeat("not-base64 ", 20) + base64.StdEncoding.EncodeToString([]byte("some secret data to encode for the benchmark")))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		getSubstringsOfCharacterSet(data, base64Threshold)
+	}
+}
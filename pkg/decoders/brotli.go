@@ -0,0 +1,46 @@
+package decoders
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// maxBrotliDecompressedSize caps how much a single chunk is allowed to
+// decompress to, so a crafted Brotli stream can't be used as a zip bomb.
+const maxBrotliDecompressedSize = 50 * 1024 * 1024 // 50 MiB
+
+type Brotli struct{}
+
+func (d *Brotli) Type() detectorspb.DecoderType {
+	return detectorspb.DecoderType_BROTLI
+}
+
+// FromChunk attempts to decompress chunk.Data as a Brotli stream. Brotli has
+// no magic number, so invalid input is detected by the decoder itself
+// bailing out on the first read rather than by inspecting the header.
+func (d *Brotli) FromChunk(ctx context.Context, chunk *sources.Chunk) *DecodableChunk {
+	if chunk == nil || len(chunk.Data) == 0 {
+		return nil
+	}
+
+	br := brotli.NewReader(bytes.NewReader(chunk.Data))
+
+	decoded, err := io.ReadAll(io.LimitReader(br, maxBrotliDecompressedSize+1))
+	if err != nil || len(decoded) == 0 {
+		return nil
+	}
+	if len(decoded) > maxBrotliDecompressedSize {
+		ctx.Logger().V(2).Info("brotli chunk exceeded max decompressed size, truncating", "limit", maxBrotliDecompressedSize)
+		decoded = decoded[:maxBrotliDecompressedSize]
+	}
+
+	chunkCopy := *chunk
+	chunkCopy.Data = decoded
+	return &DecodableChunk{Chunk: &chunkCopy, DecoderType: detectorspb.DecoderType_BROTLI}
+}
@@ -14,9 +14,20 @@ func DefaultDecoders() []Decoder {
 		&UTF16{},
 		&EscapedUnicode{},
 		&HtmlEntity{},
+		&Zstd{},
+		&Brotli{},
+		&Hex{},
+		&Base32{},
+		&Ascii85{},
 	}
 }
 
+// NewDefaultPipeline builds a Pipeline over DefaultDecoders, recursing into
+// decoded regions to peel layered encodings.
+func NewDefaultPipeline() *Pipeline {
+	return NewPipeline(DefaultDecoders()...)
+}
+
 // DecodableChunk is a chunk that includes the type of decoder used.
 // This allows us to avoid a type assertion on each decoder.
 type DecodableChunk struct {
@@ -32,20 +43,12 @@ type Decoder interface {
 // Fuzz is an entrypoint for go-fuzz, which is an AFL-style fuzzing tool.
 // This one attempts to uncover any panics during decoding.
 func Fuzz(data []byte) int {
-	decoded := false
 	ctx := context.Background()
-	for i, decoder := range DefaultDecoders() {
-		// Skip the first decoder (plain), because it will always decode and give
-		// priority to the input (return 1).
-		if i == 0 {
-			continue
-		}
-		chunk := decoder.FromChunk(ctx, &sources.Chunk{Data: data})
-		if chunk != nil {
-			decoded = true
-		}
-	}
-	if decoded {
+	// Skip UTF8 (the first default decoder), because it will always decode
+	// and give priority to the input (return 1).
+	pipeline := NewPipeline(DefaultDecoders()[1:]...)
+	chunk, _ := pipeline.Decode(ctx, &sources.Chunk{Data: data})
+	if chunk != nil {
 		return 1 // prioritize the input
 	}
 	return -1 // Don't add input to the corpus.
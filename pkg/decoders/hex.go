@@ -0,0 +1,139 @@
+package decoders
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+type Hex struct{}
+
+func (d *Hex) Type() detectorspb.DecoderType {
+	return detectorspb.DecoderType_HEX
+}
+
+const (
+	// hexThreshold is the minimum run length of hex-alphabet characters
+	// that's treated as a candidate encoded substring.
+	hexThreshold = 20
+
+	// hexMinEntropy is the minimum Shannon entropy, in bits per decoded
+	// byte, a candidate run must have. It filters out long runs of
+	// incidental repetition (commit SHAs padded with zeroes, "aaaa...",
+	// etc.) that happen to be valid hex but aren't encoded data.
+	hexMinEntropy = 3.0
+)
+
+var hexCharsetMapping = buildCharsetMapping([]byte("0123456789abcdefABCDEF"))
+
+func (d *Hex) FromChunk(ctx context.Context, chunk *sources.Chunk) *DecodableChunk {
+	out, _ := d.FromChunkWithRegions(ctx, chunk)
+	return out
+}
+
+func (d *Hex) FromChunkWithRegions(ctx context.Context, chunk *sources.Chunk) (*DecodableChunk, []DecodedRegion) {
+	if chunk == nil {
+		return nil, nil
+	}
+	data := chunk.Data
+
+	var result bytes.Buffer
+	result.Grow(len(data))
+	var regions []DecodedRegion
+	decodedAny := false
+
+	pos := 0
+	count, start := 0, 0
+	flush := func() {
+		if count <= hexThreshold {
+			count = 0
+			return
+		}
+		runStart, runLen := start, count
+		str := string(data[runStart : runStart+runLen])
+		// hex.DecodeString requires an even number of digits; drop the
+		// trailing one rather than discard the whole run.
+		if len(str)%2 != 0 {
+			str = str[:len(str)-1]
+			runLen--
+		}
+
+		dec, err := hex.DecodeString(str)
+		if err != nil || len(dec) == 0 || shannonEntropy(dec) < hexMinEntropy {
+			count = 0
+			return
+		}
+
+		result.Write(data[pos:runStart])
+		decodedStart := result.Len()
+		result.Write(dec)
+		regions = append(regions, DecodedRegion{
+			OriginalStart: runStart,
+			OriginalEnd:   runStart + runLen,
+			DecodedStart:  decodedStart,
+			DecodedEnd:    result.Len(),
+			Decoder:       decoderName(d.Type()),
+		})
+		pos = runStart + runLen
+		decodedAny = true
+		count = 0
+	}
+
+	for i, b := range data {
+		if b < 128 && hexCharsetMapping[b] {
+			if count == 0 {
+				start = i
+			}
+			count++
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	if !decodedAny {
+		return nil, nil
+	}
+	result.Write(data[pos:])
+
+	chunkCopy := *chunk
+	chunkCopy.Data = result.Bytes()
+	return &DecodableChunk{Chunk: &chunkCopy, DecoderType: detectorspb.DecoderType_HEX}, regions
+}
+
+// buildCharsetMapping returns a 128-entry ASCII membership table for
+// charset, shared by the hex/base32/ascii85 decoders' run-scanning loops.
+func buildCharsetMapping(charset []byte) [128]bool {
+	var m [128]bool
+	for _, c := range charset {
+		m[c] = true
+	}
+	return m
+}
+
+// shannonEntropy returns the Shannon entropy of data, in bits per byte.
+func shannonEntropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+
+	entropy := 0.0
+	total := float64(len(data))
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
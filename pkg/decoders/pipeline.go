@@ -0,0 +1,154 @@
+package decoders
+
+import (
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// DecodedRegion is an alias for sources.DecodedRegion, kept so decoder
+// implementations in this package can refer to it without an explicit
+// sources. qualifier. It lives on sources.Chunk (rather than being defined
+// here) so a chunk can carry its decode provenance alongside its Data
+// wherever it travels, without pkg/sources importing pkg/decoders.
+type DecodedRegion = sources.DecodedRegion
+
+// decoderNames maps a Decoder's Type to the short, lowercase name recorded
+// on the DecodedRegion(s) it produces.
+var decoderNames = map[detectorspb.DecoderType]string{
+	detectorspb.DecoderType_BASE64:  "base64",
+	detectorspb.DecoderType_HEX:     "hex",
+	detectorspb.DecoderType_BASE32:  "base32",
+	detectorspb.DecoderType_ASCII85: "ascii85",
+	detectorspb.DecoderType_ZSTD:    "zstd",
+	detectorspb.DecoderType_BROTLI:  "brotli",
+}
+
+// decoderName returns the short, lowercase name recorded on the
+// DecodedRegion(s) a decoder of type t produces, falling back to t's own
+// string representation for any decoder not in decoderNames.
+func decoderName(t detectorspb.DecoderType) string {
+	if name, ok := decoderNames[t]; ok {
+		return name
+	}
+	return t.String()
+}
+
+// RegionDecoder is a Decoder that can additionally report the provenance of
+// each region it decoded. Decoders with no positional structure of their
+// own - e.g. Zstd, which transforms a chunk as a single whole-chunk
+// payload - only need to implement Decoder; Pipeline treats those as
+// decoding the entire chunk with no region-level provenance.
+type RegionDecoder interface {
+	Decoder
+	FromChunkWithRegions(ctx context.Context, chunk *sources.Chunk) (*DecodableChunk, []DecodedRegion)
+}
+
+// defaultPipelineMaxDepth bounds how many times a Pipeline re-scans its own
+// output for further encodings, so a maliciously layered chunk can't
+// recurse forever.
+const defaultPipelineMaxDepth = 3
+
+// Pipeline runs an ordered list of Decoders over a chunk, re-scanning the
+// decoded output for further encodings - e.g. a hex string that itself
+// decodes to base64 - up to maxDepth times.
+type Pipeline struct {
+	decoders []Decoder
+	maxDepth int
+}
+
+// NewPipeline builds a Pipeline that scans with decoders, in order,
+// recursing into decoded output up to defaultPipelineMaxDepth times.
+func NewPipeline(decoders ...Decoder) *Pipeline {
+	return &Pipeline{decoders: decoders, maxDepth: defaultPipelineMaxDepth}
+}
+
+// WithMaxDepth overrides how many times p re-scans decoded output for
+// further encodings.
+func (p *Pipeline) WithMaxDepth(maxDepth int) *Pipeline {
+	p.maxDepth = maxDepth
+	return p
+}
+
+// Decode runs p's decoders over chunk, feeding each one's output back
+// through every decoder again (up to p.maxDepth rounds) so layered
+// encodings get fully peeled. It returns nil if nothing decoded.
+func (p *Pipeline) Decode(ctx context.Context, chunk *sources.Chunk) (*DecodableChunk, []DecodedRegion) {
+	if chunk == nil {
+		return nil, nil
+	}
+
+	current := chunk
+	var lastOut *DecodableChunk
+	var allRegions []DecodedRegion
+
+	for depth := 0; depth < p.maxDepth; depth++ {
+		decodedThisRound := false
+
+		for _, d := range p.decoders {
+			var out *DecodableChunk
+			var regions []DecodedRegion
+			if rd, ok := d.(RegionDecoder); ok {
+				out, regions = rd.FromChunkWithRegions(ctx, current)
+			} else {
+				out = d.FromChunk(ctx, current)
+				if out != nil {
+					// No positional structure of its own - record the
+					// whole output as having come from the whole input,
+					// so the decoder at least shows up in a finding's
+					// transformation chain.
+					regions = []DecodedRegion{{
+						OriginalStart: 0,
+						OriginalEnd:   len(current.Data),
+						DecodedStart:  0,
+						DecodedEnd:    len(out.Chunk.Data),
+						Decoder:       decoderName(d.Type()),
+					}}
+				}
+			}
+			if out == nil {
+				continue
+			}
+
+			current = out.Chunk
+			lastOut = out
+			allRegions = append(allRegions, regions...)
+			decodedThisRound = true
+		}
+
+		if !decodedThisRound {
+			break
+		}
+	}
+
+	if lastOut == nil {
+		return nil, nil
+	}
+
+	result := &DecodableChunk{Chunk: current, DecoderType: lastOut.DecoderType}
+	result.Chunk.DecodedRegions = allRegions
+	return result, allRegions
+}
+
+// ResolveOriginalOffset walks regions - the DecodedRegion slice a Pipeline
+// attaches to its output chunk - to translate decodedOffset, an offset
+// into that chunk's Data, back to the offset in the data the matching
+// decode pass was given, along with the name of the decoder that produced
+// it. It returns ok=false if decodedOffset doesn't fall within any
+// recorded region, e.g. it's in surrounding context the decoders left
+// untouched.
+//
+// For a chunk that went through more than one decode round, the returned
+// offset is relative to that round's input, not necessarily the original
+// top-level chunk (see DecodedRegion) - resolving a multi-round chain back
+// to the very first chunk requires calling ResolveOriginalOffset again
+// against the earlier round's regions.
+func ResolveOriginalOffset(regions []DecodedRegion, decodedOffset int) (originalOffset int, decoder string, ok bool) {
+	for _, r := range regions {
+		if decodedOffset < r.DecodedStart || decodedOffset >= r.DecodedEnd {
+			continue
+		}
+		return r.OriginalStart + (decodedOffset - r.DecodedStart), r.Decoder, true
+	}
+	return 0, "", false
+}
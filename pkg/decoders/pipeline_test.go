@@ -0,0 +1,160 @@
+package decoders
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+func TestHexFromChunk(t *testing.T) {
+	secret := "this-is-a-fairly-long-hex-encoded-secret-0987"
+	input := "prefix " + hex.EncodeToString([]byte(secret)) + " suffix"
+
+	d := &Hex{}
+	got := d.FromChunk(context.Background(), &sources.Chunk{Data: []byte(input)})
+	if got == nil {
+		t.Fatalf("expected decoded chunk containing %q, got nil", secret)
+	}
+	if !strings.Contains(string(got.Data), secret) {
+		t.Fatalf("decoded chunk = %q, want it to contain %q", string(got.Data), secret)
+	}
+}
+
+func TestHexFromChunk_LowEntropyRejected(t *testing.T) {
+	// A long run of a repeated hex digit decodes cleanly but has ~zero
+	// entropy, so it shouldn't be treated as encoded data.
+	input := "prefix " + strings.Repeat("00", 30) + " suffix"
+
+	d := &Hex{}
+	got := d.FromChunk(context.Background(), &sources.Chunk{Data: []byte(input)})
+	if got != nil {
+		t.Fatalf("expected low-entropy hex run to be rejected, got %q", string(got.Data))
+	}
+}
+
+func TestBase32FromChunk(t *testing.T) {
+	secret := "this-is-a-fairly-long-base32-encoded-secret"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"padded upper", base32.StdEncoding.EncodeToString([]byte(secret))},
+		{"padded lower", strings.ToLower(base32.StdEncoding.EncodeToString([]byte(secret)))},
+		{"unpadded", strings.TrimRight(base32.StdEncoding.EncodeToString([]byte(secret)), "=")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "prefix " + tt.input + " suffix"
+			d := &Base32{}
+			got := d.FromChunk(context.Background(), &sources.Chunk{Data: []byte(input)})
+			if got == nil {
+				t.Fatalf("expected decoded chunk containing %q, got nil", secret)
+			}
+			if !strings.Contains(string(got.Data), secret) {
+				t.Fatalf("decoded chunk = %q, want it to contain %q", string(got.Data), secret)
+			}
+		})
+	}
+}
+
+func TestAscii85FromChunk(t *testing.T) {
+	secret := "this-is-a-fairly-long-ascii85-encoded-secret"
+
+	var buf bytes.Buffer
+	w := ascii85.NewEncoder(&buf)
+	_, _ = w.Write([]byte(secret))
+	_ = w.Close()
+
+	input := "prefix <~" + buf.String() + "~> suffix"
+
+	d := &Ascii85{}
+	got := d.FromChunk(context.Background(), &sources.Chunk{Data: []byte(input)})
+	if got == nil {
+		t.Fatalf("expected decoded chunk containing %q, got nil", secret)
+	}
+	if !strings.Contains(string(got.Data), secret) {
+		t.Fatalf("decoded chunk = %q, want it to contain %q", string(got.Data), secret)
+	}
+}
+
+func TestPipeline_LayeredEncodings(t *testing.T) {
+	secret := "this-is-a-pipeline-layered-secret-value-123"
+	hexEncoded := hex.EncodeToString([]byte(secret))
+	b64OfHex := base64.StdEncoding.EncodeToString([]byte(hexEncoded))
+	input := "prefix " + b64OfHex + " suffix"
+
+	pipeline := NewPipeline(&Base64{}, &Hex{})
+	got, _ := pipeline.Decode(context.Background(), &sources.Chunk{Data: []byte(input)})
+	if got == nil {
+		t.Fatalf("expected pipeline to peel both layers down to %q, got nil", secret)
+	}
+	if !strings.Contains(string(got.Data), secret) {
+		t.Fatalf("decoded chunk = %q, want it to contain %q", string(got.Data), secret)
+	}
+}
+
+func TestPipeline_DecodeAttachesRegionsAndResolvesOffsets(t *testing.T) {
+	secret := "this-is-a-region-provenance-secret-value"
+	hexEncoded := hex.EncodeToString([]byte(secret))
+	input := "prefix " + hexEncoded + " suffix"
+
+	pipeline := NewPipeline(&Hex{})
+	got, regions := pipeline.Decode(context.Background(), &sources.Chunk{Data: []byte(input)})
+	if got == nil {
+		t.Fatalf("expected decoded chunk containing %q, got nil", secret)
+	}
+	if len(regions) != 1 {
+		t.Fatalf("expected exactly 1 region, got %d: %+v", len(regions), regions)
+	}
+	if len(got.Chunk.DecodedRegions) != 1 {
+		t.Fatalf("expected Decode to attach regions to the output chunk, got %+v", got.Chunk.DecodedRegions)
+	}
+	if regions[0].Decoder != "hex" {
+		t.Errorf("region Decoder = %q, want %q", regions[0].Decoder, "hex")
+	}
+
+	secretOffset := strings.Index(string(got.Data), secret)
+	if secretOffset == -1 {
+		t.Fatalf("decoded chunk %q doesn't contain %q", string(got.Data), secret)
+	}
+
+	originalOffset, decoder, ok := ResolveOriginalOffset(regions, secretOffset)
+	if !ok {
+		t.Fatalf("ResolveOriginalOffset(%d) = not ok, want ok", secretOffset)
+	}
+	if decoder != "hex" {
+		t.Errorf("ResolveOriginalOffset decoder = %q, want %q", decoder, "hex")
+	}
+	wantOriginalOffset := strings.Index(input, hexEncoded)
+	if originalOffset != wantOriginalOffset {
+		t.Errorf("ResolveOriginalOffset offset = %d, want %d", originalOffset, wantOriginalOffset)
+	}
+}
+
+func TestResolveOriginalOffset_NoMatchingRegion(t *testing.T) {
+	regions := []DecodedRegion{{OriginalStart: 10, OriginalEnd: 20, DecodedStart: 0, DecodedEnd: 5, Decoder: "hex"}}
+	if _, _, ok := ResolveOriginalOffset(regions, 100); ok {
+		t.Fatalf("expected ResolveOriginalOffset to report no match for an offset outside every region")
+	}
+}
+
+func BenchmarkPipelineDecode(b *testing.B) {
+	secret := hex.EncodeToString([]byte("a reasonably long secret-ish value for benchmarking"))
+	data := []byte(strings.Repeat("x", 50) + secret + strings.Repeat("y", 50))
+	pipeline := NewDefaultPipeline()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pipeline.Decode(ctx, &sources.Chunk{Data: data})
+	}
+}
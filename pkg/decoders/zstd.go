@@ -0,0 +1,51 @@
+package decoders
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// maxZstdDecompressedSize caps how much a single chunk is allowed to
+// decompress to, so a crafted Zstandard frame can't be used as a zip bomb.
+const maxZstdDecompressedSize = 50 * 1024 * 1024 // 50 MiB
+
+// zstdMagic is the 4-byte Zstandard frame magic number, least-significant
+// byte first.
+var zstdMagic = []byte{0x28, 0xB5, 0x2F, 0xFD}
+
+type Zstd struct{}
+
+func (d *Zstd) Type() detectorspb.DecoderType {
+	return detectorspb.DecoderType_ZSTD
+}
+
+func (d *Zstd) FromChunk(ctx context.Context, chunk *sources.Chunk) *DecodableChunk {
+	if chunk == nil || !bytes.HasPrefix(chunk.Data, zstdMagic) {
+		return nil
+	}
+
+	zr, err := zstd.NewReader(bytes.NewReader(chunk.Data))
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+
+	decoded, err := io.ReadAll(io.LimitReader(zr, maxZstdDecompressedSize+1))
+	if err != nil || len(decoded) == 0 {
+		return nil
+	}
+	if len(decoded) > maxZstdDecompressedSize {
+		ctx.Logger().V(2).Info("zstd chunk exceeded max decompressed size, truncating", "limit", maxZstdDecompressedSize)
+		decoded = decoded[:maxZstdDecompressedSize]
+	}
+
+	chunkCopy := *chunk
+	chunkCopy.Data = decoded
+	return &DecodableChunk{Chunk: &chunkCopy, DecoderType: detectorspb.DecoderType_ZSTD}
+}
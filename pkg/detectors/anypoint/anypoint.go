@@ -2,8 +2,11 @@ package anypoint
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	regexp "github.com/wasilibs/go-re2"
@@ -26,8 +29,25 @@ var (
 	// Make sure that your group is surrounded in boundary characters such as below to reduce false positives.
 	keyPat = regexp.MustCompile(`\b([0-9a-z]{8}-[0-9a-z]{4}-[0-9a-z]{4}-[0-9a-z]{4}-[0-9a-z]{12})\b`)
 	orgPat = regexp.MustCompile(detectors.PrefixRegex([]string{"org"}) + `\b([0-9a-z]{8}-[0-9a-z]{4}-[0-9a-z]{4}-[0-9a-z]{4}-[0-9a-z]{12})\b`)
+
+	// Connected App (OAuth2 client credentials) pattern. client_id and
+	// client_secret are both 32-hex-char strings, so both patterns are kept
+	// distinct via their respective keyword prefixes to avoid pairing a
+	// client_id with an unrelated client_secret elsewhere in the chunk.
+	clientIDPat     = regexp.MustCompile(detectors.PrefixRegex([]string{"client_id", "client id", "connected app"}) + `\b([0-9a-f]{32})\b`)
+	clientSecretPat = regexp.MustCompile(detectors.PrefixRegex([]string{"client_secret", "client secret", "connected app"}) + `\b([0-9a-f]{32})\b`)
 )
 
+// AnypointHosts are the Anypoint control-plane hosts tried during
+// verification, in order, stopping at the first 2xx response. Downstream
+// users can append private-cloud/on-prem Anypoint hosts here without
+// forking the detector.
+var AnypointHosts = []string{
+	"anypoint.mulesoft.com",
+	"eu1.anypoint.mulesoft.com",
+	"gov.anypoint.mulesoft.com",
+}
+
 // Keywords are used for efficiently pre-filtering chunks.
 // Use identifiers in the secret preferably, or the provider name.
 func (s Scanner) Keywords() []string {
@@ -64,18 +84,51 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 			}
 
 			if verify {
-				req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://anypoint.mulesoft.com/apiplatform/repository/v2/organizations/%s/apis/by-name?apiName=%s", orgRes, ""), nil)
-				if err != nil {
-					continue
+				region, verificationErr := verifyOrgToken(ctx, resMatch, orgRes)
+				if verificationErr != nil {
+					s1.SetVerificationError(verificationErr, resMatch)
+				} else if region != "" {
+					s1.Verified = true
+					s1.ExtraData = map[string]string{"region": region}
 				}
-				req.Header.Add("Content-Type", "application/json")
-				req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", resMatch))
-				res, err := client.Do(req)
-				if err == nil {
-					defer res.Body.Close()
-					if res.StatusCode >= 200 && res.StatusCode < 300 {
-						s1.Verified = true
-					}
+			}
+
+			results = append(results, s1)
+		}
+	}
+
+	clientIDMatches := clientIDPat.FindAllStringSubmatch(dataStr, -1)
+	clientSecretMatches := clientSecretPat.FindAllStringSubmatch(dataStr, -1)
+
+	for _, clientIDMatch := range clientIDMatches {
+		if len(clientIDMatch) != 2 {
+			continue
+		}
+		clientID := strings.TrimSpace(clientIDMatch[1])
+
+		for _, clientSecretMatch := range clientSecretMatches {
+			if len(clientSecretMatch) != 2 {
+				continue
+			}
+			clientSecret := strings.TrimSpace(clientSecretMatch[1])
+
+			if clientID == clientSecret {
+				continue
+			}
+
+			s1 := detectors.Result{
+				DetectorType: detectorspb.DetectorType_Anypoint,
+				Raw:          []byte(clientID),
+				RawV2:        []byte(clientID + ":" + clientSecret),
+			}
+
+			if verify {
+				token, region, scopes, verificationErr := verifyConnectedApp(ctx, clientID, clientSecret)
+				if verificationErr != nil {
+					s1.SetVerificationError(verificationErr, clientSecret)
+				} else if token != "" {
+					s1.Verified = true
+					s1.ExtraData = map[string]string{"region": region, "scopes": scopes}
 				}
 			}
 
@@ -86,6 +139,160 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 	return results, nil
 }
 
+// verifyOrgToken tries key/org against each of AnypointHosts in turn,
+// returning the host that first answered with a 2xx response.
+func verifyOrgToken(ctx context.Context, key, org string) (string, error) {
+	var lastErr error
+	for _, host := range AnypointHosts {
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://%s/apiplatform/repository/v2/organizations/%s/apis/by-name?apiName=%s", host, org, ""), nil)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		req.Header.Add("Content-Type", "application/json")
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", key))
+
+		res, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		res.Body.Close()
+
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			return host, nil
+		}
+	}
+	return "", lastErr
+}
+
+// connectedAppTokenResponse is the relevant subset of the response from
+// POSTing client credentials to the Connected App OAuth2 token endpoint.
+type connectedAppTokenResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// connectedAppMeResponse is the relevant subset of the response from
+// /accounts/api/me, used to distinguish a valid-but-scopeless Connected App
+// token from a fully privileged one.
+type connectedAppMeResponse struct {
+	User struct {
+		MemberOfOrgs []struct {
+			Name string `json:"name"`
+		} `json:"memberOfOrganizations"`
+	} `json:"user"`
+}
+
+// verifyConnectedApp exchanges clientID/clientSecret for an OAuth2 access
+// token against each of AnypointHosts in turn and, for the host that
+// accepts it, calls /accounts/api/me to determine what scope the token
+// actually grants. It returns the acquired access token (empty if the
+// credential was rejected everywhere), the host that accepted it, and a
+// human-readable summary of the resulting scope.
+func verifyConnectedApp(ctx context.Context, clientID, clientSecret string) (string, string, string, error) {
+	var lastErr error
+	for _, host := range AnypointHosts {
+		token, err := connectedAppToken(ctx, host, clientID, clientSecret)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if token == "" {
+			continue
+		}
+
+		scopes, err := connectedAppScopes(ctx, host, token)
+		if err != nil {
+			// The credential is still valid even if this follow-up call
+			// fails; just leave the scope summary blank.
+			return token, host, "", nil
+		}
+		return token, host, scopes, nil
+	}
+	return "", "", "", lastErr
+}
+
+// connectedAppToken exchanges clientID/clientSecret for an OAuth2 access
+// token on host, returning an empty token (and nil error) if host
+// determinately rejects the credential.
+func connectedAppToken(ctx context.Context, host, clientID, clientSecret string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("https://%s/accounts/api/v2/oauth2/token", host), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return "", nil
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return "", fmt.Errorf("unexpected HTTP response status %d from token endpoint", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var tokenRes connectedAppTokenResponse
+	if err := json.Unmarshal(body, &tokenRes); err != nil {
+		return "", err
+	}
+	return tokenRes.AccessToken, nil
+}
+
+// connectedAppScopes calls host's /accounts/api/me with accessToken and
+// summarizes the organizations it grants access to, as a rough proxy for
+// how privileged the token is.
+func connectedAppScopes(ctx context.Context, host, accessToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://%s/accounts/api/me", host), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return "scopeless", nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var me connectedAppMeResponse
+	if err := json.Unmarshal(body, &me); err != nil {
+		return "", err
+	}
+	if len(me.User.MemberOfOrgs) == 0 {
+		return "scopeless", nil
+	}
+
+	names := make([]string, 0, len(me.User.MemberOfOrgs))
+	for _, org := range me.User.MemberOfOrgs {
+		names = append(names, org.Name)
+	}
+	return strings.Join(names, ","), nil
+}
+
 func (s Scanner) Type() detectorspb.DetectorType {
 	return detectorspb.DetectorType_Anypoint
 }
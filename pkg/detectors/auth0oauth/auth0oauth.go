@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 
 	regexp "github.com/wasilibs/go-re2"
@@ -20,12 +21,28 @@ type Scanner struct {
 // Ensure the Scanner satisfies the interface at compile time.
 var _ detectors.Detector = (*Scanner)(nil)
 
+// These limits bound the candidate-pair scoring below: a handful of
+// clientIDs and secrets considered per domain, and a hard cap on outbound
+// verification requests per domain, so that a file full of unrelated
+// high-entropy strings can no longer blow up into thousands of HTTP calls.
+const (
+	maxClientIDsPerDomain     = 3
+	maxSecretsPerClientID     = 3
+	maxVerificationsPerDomain = 9
+)
+
 var (
 	client = detectors.DetectorHttpClientWithLocalAddresses
 
-	clientIdPat     = regexp.MustCompile(detectors.PrefixRegex([]string{"auth0"}) + `\b([a-zA-Z0-9_-]{32,60})\b`)
-	clientSecretPat = regexp.MustCompile(`\b([a-zA-Z0-9_-]{64,})\b`)
+	clientIdPat = regexp.MustCompile(detectors.PrefixRegex([]string{"auth0"}) + `\b([a-zA-Z0-9_-]{32,60})\b`)
+	// Auth0 client secrets are base64url-ish strings of a bounded length; the
+	// upper bound keeps this from matching arbitrarily long high-entropy blobs.
+	clientSecretPat = regexp.MustCompile(`\b([a-zA-Z0-9_-]{64,72})\b`)
 	domainPat       = regexp.MustCompile(`\b([a-zA-Z0-9][a-zA-Z0-9._-]*auth0\.com)\b`) // could be part of url
+	// keywordPat marks positions in the chunk that look like an Auth0 client
+	// secret declaration, used to bias candidate scoring toward secrets that
+	// actually sit next to a relevant keyword rather than just any 64+ char token.
+	keywordPat = regexp.MustCompile(`(?i)(auth0|client_secret)`)
 )
 
 // Keywords are used for efficiently pre-filtering chunks.
@@ -34,73 +51,196 @@ func (s Scanner) Keywords() []string {
 	return []string{"auth0"}
 }
 
+// positionsByValue groups occurrences of the same matched string by their byte
+// offsets in the chunk, since a clientID/secret/domain may appear more than once.
+func positionsByValue(matches [][]int, dataStr string) map[string][]int {
+	positions := make(map[string][]int)
+	for _, m := range matches {
+		if len(m) < 4 {
+			continue
+		}
+		value := strings.TrimSpace(dataStr[m[2]:m[3]])
+		positions[value] = append(positions[value], m[2])
+	}
+	return positions
+}
+
+// minCrossDistance returns the smallest byte distance between any position in a
+// and any position in b. It returns -1 if either slice is empty.
+func minCrossDistance(a, b []int) int {
+	best := -1
+	for _, pa := range a {
+		for _, pb := range b {
+			d := pa - pb
+			if d < 0 {
+				d = -d
+			}
+			if best == -1 || d < best {
+				best = d
+			}
+		}
+	}
+	return best
+}
+
+// candidate is a scored (clientID, secret) pair for a single domain. Lower
+// score means the clientID/secret/domain are more likely to belong together
+// (closer together in the chunk, and closer to an auth0/client_secret keyword).
+type candidate struct {
+	clientID string
+	secret   string
+	score    int
+}
+
+// topClientIDs ranks clientIDs by proximity to the domain's occurrences and
+// returns at most maxClientIDsPerDomain of them.
+func topClientIDs(domainPositions []int, clientIDPositions map[string][]int) []string {
+	type scored struct {
+		value string
+		score int
+	}
+	ranked := make([]scored, 0, len(clientIDPositions))
+	for value, positions := range clientIDPositions {
+		ranked = append(ranked, scored{value: value, score: minCrossDistance(domainPositions, positions)})
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].score < ranked[j].score })
+
+	if len(ranked) > maxClientIDsPerDomain {
+		ranked = ranked[:maxClientIDsPerDomain]
+	}
+	values := make([]string, len(ranked))
+	for i, r := range ranked {
+		values[i] = r.value
+	}
+	return values
+}
+
+// topSecrets ranks secrets by proximity to the clientID's occurrences, biased
+// toward secrets that also sit near an auth0/client_secret keyword, and
+// returns at most maxSecretsPerClientID of them.
+func topSecrets(clientIDPositions []int, secretPositions map[string][]int, keywordPositions []int) []string {
+	type scored struct {
+		value string
+		score int
+	}
+	ranked := make([]scored, 0, len(secretPositions))
+	for value, positions := range secretPositions {
+		score := minCrossDistance(clientIDPositions, positions)
+		if keywordDist := minCrossDistance(keywordPositions, positions); keywordDist != -1 {
+			score += keywordDist
+		}
+		ranked = append(ranked, scored{value: value, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].score < ranked[j].score })
+
+	if len(ranked) > maxSecretsPerClientID {
+		ranked = ranked[:maxSecretsPerClientID]
+	}
+	values := make([]string, len(ranked))
+	for i, r := range ranked {
+		values[i] = r.value
+	}
+	return values
+}
+
+// candidatesForDomain builds the scored, capped set of (clientID, secret) pairs
+// worth considering for a single domain.
+func candidatesForDomain(domainPositions []int, clientIDPositions, secretPositions map[string][]int, keywordPositions []int) []candidate {
+	var candidates []candidate
+	for _, clientID := range topClientIDs(domainPositions, clientIDPositions) {
+		for _, secret := range topSecrets(clientIDPositions[clientID], secretPositions, keywordPositions) {
+			candidates = append(candidates, candidate{
+				clientID: clientID,
+				secret:   secret,
+				score:    minCrossDistance(clientIDPositions[clientID], secretPositions[secret]),
+			})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score < candidates[j].score })
+	return candidates
+}
+
 // FromData will find and optionally verify Auth0oauth secrets in a given set of bytes.
 func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (results []detectors.Result, err error) {
 	dataStr := string(data)
-	uniqueDomainMatches := make(map[string]struct{})
-	uniqueClientIDs := make(map[string]struct{})
-	uniqueSecrets := make(map[string]struct{})
-	for _, m := range domainPat.FindAllStringSubmatch(dataStr, -1) {
-		uniqueDomainMatches[strings.TrimSpace(m[1])] = struct{}{}
-	}
-	for _, m := range clientIdPat.FindAllStringSubmatch(dataStr, -1) {
-		uniqueClientIDs[strings.TrimSpace(m[1])] = struct{}{}
+
+	domainPositions := positionsByValue(domainPat.FindAllStringSubmatchIndex(dataStr, -1), dataStr)
+	clientIDPositions := positionsByValue(clientIdPat.FindAllStringSubmatchIndex(dataStr, -1), dataStr)
+	secretPositions := positionsByValue(clientSecretPat.FindAllStringSubmatchIndex(dataStr, -1), dataStr)
+
+	if len(domainPositions) == 0 || len(clientIDPositions) == 0 || len(secretPositions) == 0 {
+		return nil, nil
 	}
-	for _, m := range clientSecretPat.FindAllStringSubmatch(dataStr, -1) {
-		uniqueSecrets[strings.TrimSpace(m[1])] = struct{}{}
+
+	var keywordPositions []int
+	for _, m := range keywordPat.FindAllStringIndex(dataStr, -1) {
+		keywordPositions = append(keywordPositions, m[0])
 	}
 
-	for clientIdRes := range uniqueClientIDs {
-		for clientSecretRes := range uniqueSecrets {
-			for domainRes := range uniqueDomainMatches {
-				s1 := detectors.Result{
-					DetectorType: detectorspb.DetectorType_Auth0oauth,
-					Redacted:     clientIdRes,
-					Raw:          []byte(clientSecretRes),
-					RawV2:        []byte(clientIdRes + clientSecretRes),
-				}
+	for domainRes, domainPos := range domainPositions {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
 
-				if verify {
-					/*
-					   curl --request POST \
-					     --url 'https://YOUR_DOMAIN/oauth/token' \
-					     --header 'content-type: application/x-www-form-urlencoded' \
-					     --data 'grant_type=authorization_code&client_id=W44JmL3qD6LxHeEJyKe9lMuhcwvPOaOq&client_secret=YOUR_CLIENT_SECRET&code=AUTHORIZATION_CODE&redirect_uri=undefined'
-					*/
-
-					data := url.Values{}
-					data.Set("grant_type", "authorization_code")
-					data.Set("client_id", clientIdRes)
-					data.Set("client_secret", clientSecretRes)
-					data.Set("code", "AUTHORIZATION_CODE")
-					data.Set("redirect_uri", "undefined")
-
-					req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+domainRes+"/oauth/token", strings.NewReader(data.Encode())) // URL-encoded payload
-					if err != nil {
+		candidates := candidatesForDomain(domainPos, clientIDPositions, secretPositions, keywordPositions)
+
+		verificationsUsed := 0
+		for _, c := range candidates {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+
+			s1 := detectors.Result{
+				DetectorType: detectorspb.DetectorType_Auth0oauth,
+				Redacted:     c.clientID,
+				Raw:          []byte(c.secret),
+				RawV2:        []byte(c.clientID + c.secret),
+			}
+
+			if verify && verificationsUsed < maxVerificationsPerDomain {
+				verificationsUsed++
+
+				/*
+				   curl --request POST \
+				     --url 'https://YOUR_DOMAIN/oauth/token' \
+				     --header 'content-type: application/x-www-form-urlencoded' \
+				     --data 'grant_type=authorization_code&client_id=W44JmL3qD6LxHeEJyKe9lMuhcwvPOaOq&client_secret=YOUR_CLIENT_SECRET&code=AUTHORIZATION_CODE&redirect_uri=undefined'
+				*/
+
+				data := url.Values{}
+				data.Set("grant_type", "authorization_code")
+				data.Set("client_id", c.clientID)
+				data.Set("client_secret", c.secret)
+				data.Set("code", "AUTHORIZATION_CODE")
+				data.Set("redirect_uri", "undefined")
+
+				req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+domainRes+"/oauth/token", strings.NewReader(data.Encode())) // URL-encoded payload
+				if reqErr != nil {
+					results = append(results, s1)
+					continue
+				}
+				req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+				res, doErr := client.Do(req)
+				if doErr == nil {
+					defer res.Body.Close()
+					bodyBytes, readErr := io.ReadAll(res.Body)
+					if readErr != nil {
+						results = append(results, s1)
 						continue
 					}
-					req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-					res, err := client.Do(req)
-					if err == nil {
-						defer res.Body.Close()
-						bodyBytes, err := io.ReadAll(res.Body)
-						if err != nil {
-							continue
-						}
-						body := string(bodyBytes)
-
-						// if client_id and client_secret is valid -> 403 {"error":"invalid_grant","error_description":"Invalid authorization code"}
-						// if invalid -> 401 {"error":"access_denied","error_description":"Unauthorized"}
-						// ingenious!
-
-						if !strings.Contains(body, "access_denied") {
-							s1.Verified = true
-						}
+					body := string(bodyBytes)
+
+					// if client_id and client_secret is valid -> 403 {"error":"invalid_grant","error_description":"Invalid authorization code"}
+					// if invalid -> 401 {"error":"access_denied","error_description":"Unauthorized"}
+					// ingenious!
+
+					if !strings.Contains(body, "access_denied") {
+						s1.Verified = true
 					}
 				}
-
-				results = append(results, s1)
 			}
+
+			results = append(results, s1)
 		}
 	}
 
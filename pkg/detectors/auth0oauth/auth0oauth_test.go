@@ -0,0 +1,40 @@
+//go:build detectors
+// +build detectors
+
+package auth0oauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestAuth0Oauth_FromData_NoExplosion demonstrates that a chunk containing many
+// unrelated high-entropy strings alongside a real-looking Auth0 credential no
+// longer produces a combinatorial explosion of candidate pairs.
+func TestAuth0Oauth_FromData_NoExplosion(t *testing.T) {
+	var noise strings.Builder
+	for i := 0; i < 50; i++ {
+		// 64 character, non-auth0-flavored hex strings far away from any
+		// auth0/client_secret keyword.
+		noise.WriteString(fmt.Sprintf("token%d = \"%s\"\n", i, strings.Repeat(fmt.Sprintf("%02x", i), 32)))
+	}
+
+	input := noise.String() + fmt.Sprintf(`
+		auth0_client_id = "abcd1234efgh5678ijkl1234mnop5678"
+		auth0_client_secret = "%s"
+		auth0_domain = "example.auth0.com"
+	`, strings.Repeat("z", 66))
+
+	s := Scanner{}
+	results, err := s.FromData(context.Background(), false, []byte(input))
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	maxExpected := maxClientIDsPerDomain * maxSecretsPerClientID
+	if len(results) > maxExpected {
+		t.Errorf("got %d candidate results, want at most %d (scoring should have capped the candidate set)", len(results), maxExpected)
+	}
+}
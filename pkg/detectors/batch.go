@@ -0,0 +1,32 @@
+package detectors
+
+import "github.com/trufflesecurity/trufflehog/v3/pkg/context"
+
+// Candidate is one credential to verify in a single VerifyBatch call.
+type Candidate struct {
+	// Raw is the candidate secret exactly as extracted from scanned data -
+	// the same value that ends up in Result.Raw.
+	Raw string
+	// Context carries any non-secret material (email, org, cluster host,
+	// etc.) a detector's ordinary per-candidate verification call also
+	// needs alongside Raw.
+	Context map[string]string
+}
+
+// BatchResult is the verification outcome for one Candidate, returned by
+// VerifyBatch at the same index the Candidate was passed in at.
+type BatchResult struct {
+	Verified  bool
+	ExtraData map[string]string
+	Err       error
+}
+
+// BatchVerifier is implemented by a detector (or a type it embeds, like
+// npm/token.BaseScanner) whose provider can confirm many candidate
+// credentials together instead of one call per candidate. When a scan
+// surfaces many related tokens against the same provider, preferring
+// VerifyBatch over repeated per-candidate verification cuts outbound
+// requests and rate-limit pressure.
+type BatchVerifier interface {
+	VerifyBatch(ctx context.Context, candidates []Candidate) ([]BatchResult, error)
+}
@@ -0,0 +1,163 @@
+package databricksoauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	regexp "github.com/wasilibs/go-re2"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+)
+
+type Scanner struct {
+	client *http.Client
+	detectors.DefaultMultiPartCredentialProvider
+}
+
+// Ensure the Scanner satisfies the interface at compile time.
+var _ detectors.Detector = (*Scanner)(nil)
+
+var (
+	defaultClient = detectors.DetectorHttpClientWithNoLocalAddresses
+
+	// Make sure that your group is surrounded in boundary characters such as below to reduce false positives.
+	domainPat       = regexp.MustCompile(`\b([a-z0-9-]+(?:\.[a-z0-9-]+)*\.(cloud\.databricks\.com|gcp\.databricks\.com|azuredatabricks\.net))\b`)
+	clientIDPat     = regexp.MustCompile(detectors.PrefixRegex([]string{"databricks"}) + common.UUIDPattern)
+	clientSecretPat = regexp.MustCompile(`\b(dose[0-9a-f]{32,40})\b`)
+)
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Scope       string `json:"scope"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Keywords are used for efficiently pre-filtering chunks.
+// Use identifiers in the secret preferably, or the provider name.
+func (s Scanner) Keywords() []string {
+	return []string{"databricks", "dose"}
+}
+
+// FromData will find and optionally verify Databricksoauth secrets in a given set of bytes.
+func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (results []detectors.Result, err error) {
+	dataStr := string(data)
+
+	clientIDMatches := clientIDPat.FindAllStringSubmatch(dataStr, -1)
+	secretMatches := clientSecretPat.FindAllStringSubmatch(dataStr, -1)
+	domainMatches := domainPat.FindAllStringSubmatch(dataStr, -1)
+
+	for _, secretMatch := range secretMatches {
+		resSecretMatch := strings.TrimSpace(secretMatch[1])
+
+		for _, clientIDMatch := range clientIDMatches {
+			resClientIDMatch := strings.TrimSpace(clientIDMatch[1])
+
+			for _, domainMatch := range domainMatches {
+				resDomainMatch := strings.TrimSpace(domainMatch[1])
+
+				s1 := detectors.Result{
+					DetectorType: detectorspb.DetectorType_DatabricksOauth,
+					Raw:          []byte(resSecretMatch),
+					RawV2:        []byte(resClientIDMatch + resSecretMatch + resDomainMatch),
+				}
+
+				if verify {
+					client := s.client
+					if client == nil {
+						client = defaultClient
+					}
+
+					isVerified, extraData, verificationErr := verifyMachineToMachine(ctx, client, resDomainMatch, resClientIDMatch, resSecretMatch)
+					s1.Verified = isVerified
+					if extraData != nil {
+						s1.ExtraData = extraData
+					}
+					if verificationErr != nil {
+						s1.SetVerificationError(verificationErr, resSecretMatch)
+					}
+				}
+
+				results = append(results, s1)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// verifyMachineToMachine exchanges a Databricks service-principal client_id/client_secret pair
+// for an OAuth access token via the client_credentials grant, then confirms the token is usable
+// by calling a lightweight, read-only endpoint on the same host.
+func verifyMachineToMachine(ctx context.Context, client *http.Client, domain, clientID, clientSecret string) (bool, map[string]string, error) {
+	tokenURL := fmt.Sprintf("https://%s/oidc/v1/token", domain)
+	body := strings.NewReader("grant_type=client_credentials&scope=all-apis")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, body)
+	if err != nil {
+		return false, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	encodedCredentials := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", clientID, clientSecret)))
+	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", encodedCredentials))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return false, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusBadRequest {
+			return false, nil, nil
+		}
+		return false, nil, fmt.Errorf("unexpected HTTP response status %d", res.StatusCode)
+	}
+
+	respBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var tokenResp tokenResponse
+	if err := json.Unmarshal(respBody, &tokenResp); err != nil {
+		return false, nil, err
+	}
+	if tokenResp.AccessToken == "" {
+		return false, nil, nil
+	}
+
+	extraData := map[string]string{
+		"scope":      tokenResp.Scope,
+		"expires_in": fmt.Sprintf("%d", tokenResp.ExpiresIn),
+	}
+
+	clustersURL := fmt.Sprintf("https://%s/api/2.0/clusters/list", domain)
+	clustersReq, err := http.NewRequestWithContext(ctx, "GET", clustersURL, nil)
+	if err != nil {
+		return true, extraData, nil
+	}
+	clustersReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokenResp.AccessToken))
+	clustersRes, err := client.Do(clustersReq)
+	if err != nil {
+		return true, extraData, nil
+	}
+	defer clustersRes.Body.Close()
+
+	return clustersRes.StatusCode >= 200 && clustersRes.StatusCode < 300, extraData, nil
+}
+
+func (s Scanner) Type() detectorspb.DetectorType {
+	return detectorspb.DetectorType_DatabricksOauth
+}
+
+func (s Scanner) Description() string {
+	return "Databricks OAuth 2.0 service principals use a client_id/client_secret pair to authenticate via the client_credentials grant, returning a short-lived access token that can be used to call Databricks workspace and account APIs."
+}
@@ -0,0 +1,217 @@
+// Package dbtest provides shared testcontainers-go helpers for detector
+// tests that need a real database to verify against. Each Start* function
+// starts its container using testcontainers-go's wait strategies (log
+// predicates, not log scraping with a fixed timeout) and registers a
+// t.Cleanup to terminate it, so callers don't need their own startX/stopX
+// plumbing or a working `docker` CLI on $PATH - any container runtime
+// testcontainers-go supports (including podman and containerd) works.
+package dbtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"github.com/testcontainers/testcontainers-go/modules/mssql"
+	"github.com/testcontainers/testcontainers-go/modules/mysql"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const startupTimeout = 60 * time.Second
+
+// PostgresConn holds the connection details for a container started by
+// StartPostgres.
+type PostgresConn struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+}
+
+// StartPostgres starts a postgres container and returns its connection
+// details. The container is terminated automatically via t.Cleanup.
+func StartPostgres(t *testing.T) PostgresConn {
+	t.Helper()
+	ctx := context.Background()
+
+	const (
+		user     = "postgres"
+		password = "23201dabb56ca236f3dc6736c0f9afad"
+		database = "postgres"
+	)
+
+	container, err := postgres.Run(ctx, "postgres:16-alpine",
+		postgres.WithUsername(user),
+		postgres.WithPassword(password),
+		postgres.WithDatabase(database),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(startupTimeout),
+		),
+	)
+	if err != nil {
+		t.Fatalf("dbtest: could not start postgres container: %v", err)
+	}
+	t.Cleanup(func() { terminate(t, container) })
+
+	host, port := hostAndPort(t, container, "5432/tcp")
+	return PostgresConn{Host: host, Port: port, User: user, Password: password, Database: database}
+}
+
+// MySQLConn holds the connection details for a container started by
+// StartMySQL.
+type MySQLConn struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+}
+
+// StartMySQL starts a mysql container and returns its connection details.
+// The container is terminated automatically via t.Cleanup.
+func StartMySQL(t *testing.T) MySQLConn {
+	t.Helper()
+	ctx := context.Background()
+
+	const (
+		user     = "trufflehog"
+		password = "4a9ae57d4e719ed68c5f953019e8f953"
+		database = "trufflehog"
+	)
+
+	container, err := mysql.Run(ctx, "mysql:8",
+		mysql.WithUsername(user),
+		mysql.WithPassword(password),
+		mysql.WithDatabase(database),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("port: 3306  MySQL Community Server").WithStartupTimeout(startupTimeout),
+		),
+	)
+	if err != nil {
+		t.Fatalf("dbtest: could not start mysql container: %v", err)
+	}
+	t.Cleanup(func() { terminate(t, container) })
+
+	host, port := hostAndPort(t, container, "3306/tcp")
+	return MySQLConn{Host: host, Port: port, User: user, Password: password, Database: database}
+}
+
+// MongoConn holds the connection details for a container started by
+// StartMongo.
+type MongoConn struct {
+	Host string
+	Port string
+}
+
+// StartMongo starts a mongodb container and returns its connection details.
+// The container is terminated automatically via t.Cleanup.
+func StartMongo(t *testing.T) MongoConn {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := mongodb.Run(ctx, "mongo:7",
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("Waiting for connections").WithStartupTimeout(startupTimeout),
+		),
+	)
+	if err != nil {
+		t.Fatalf("dbtest: could not start mongodb container: %v", err)
+	}
+	t.Cleanup(func() { terminate(t, container) })
+
+	host, port := hostAndPort(t, container, "27017/tcp")
+	return MongoConn{Host: host, Port: port}
+}
+
+// RedisConn holds the connection details for a container started by
+// StartRedis.
+type RedisConn struct {
+	Host string
+	Port string
+}
+
+// StartRedis starts a redis container and returns its connection details.
+// The container is terminated automatically via t.Cleanup.
+func StartRedis(t *testing.T) RedisConn {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := redis.Run(ctx, "redis:7-alpine",
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("Ready to accept connections").WithStartupTimeout(startupTimeout),
+		),
+	)
+	if err != nil {
+		t.Fatalf("dbtest: could not start redis container: %v", err)
+	}
+	t.Cleanup(func() { terminate(t, container) })
+
+	host, port := hostAndPort(t, container, "6379/tcp")
+	return RedisConn{Host: host, Port: port}
+}
+
+// MSSQLConn holds the connection details for a container started by
+// StartMSSQL.
+type MSSQLConn struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+}
+
+// StartMSSQL starts a mssql container and returns its connection details.
+// The container is terminated automatically via t.Cleanup.
+func StartMSSQL(t *testing.T) MSSQLConn {
+	t.Helper()
+	ctx := context.Background()
+
+	const (
+		user     = "sa"
+		password = "Tru££leHog_2a27c5!"
+	)
+
+	container, err := mssql.Run(ctx, "mcr.microsoft.com/mssql/server:2022-latest",
+		mssql.WithAcceptEULA(),
+		mssql.WithPassword(password),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("Recovery is complete").WithStartupTimeout(startupTimeout),
+		),
+	)
+	if err != nil {
+		t.Fatalf("dbtest: could not start mssql container: %v", err)
+	}
+	t.Cleanup(func() { terminate(t, container) })
+
+	host, port := hostAndPort(t, container, "1433/tcp")
+	return MSSQLConn{Host: host, Port: port, User: user, Password: password}
+}
+
+// hostAndPort resolves the host and mapped port for a running container.
+func hostAndPort(t *testing.T, container testcontainers.Container, natPort string) (string, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("dbtest: could not get container host: %v", err)
+	}
+	mapped, err := container.MappedPort(ctx, testcontainers.ContainerPort(natPort))
+	if err != nil {
+		t.Fatalf("dbtest: could not get container port %s: %v", natPort, err)
+	}
+	return host, mapped.Port()
+}
+
+func terminate(t *testing.T, container testcontainers.Container) {
+	t.Helper()
+	if err := container.Terminate(context.Background()); err != nil {
+		t.Logf("dbtest: could not terminate container: %v", err)
+	}
+}
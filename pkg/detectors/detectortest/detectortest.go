@@ -0,0 +1,90 @@
+// Package detectortest provides an in-process HTTP test harness for
+// detector verification tests. Many detectors verify a credential by
+// calling a live third-party API, which makes their verification tests
+// either gated behind real secrets (commonly pulled from GCP Secret
+// Manager behind a `//go:build detectors` tag) or flaky when the upstream
+// API changes shape. Server lets a detector's Scanner.client be pointed at
+// a local stand-in instead, so verification behavior - valid, invalid,
+// timed-out, or an unexpected response surface - can be asserted
+// deterministically and without network access.
+package detectortest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Response is the literal status code and body Server returns for a
+// registered request.
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+// Server is a small httptest.Server-backed stand-in for one or more of a
+// detector's verification endpoints. Register the method+path combinations
+// a test case expects to see with HandleFunc or Handle, then point the
+// detector under test at Client (or URL, if the detector takes a base URL
+// rather than an *http.Client).
+type Server struct {
+	mu       sync.Mutex
+	handlers map[string]http.HandlerFunc
+	srv      *httptest.Server
+}
+
+// NewServer starts a Server. Callers must Close it when done, typically
+// via defer immediately after construction.
+func NewServer() *Server {
+	s := &Server{handlers: make(map[string]http.HandlerFunc)}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.route))
+	return s
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	h, ok := s.handlers[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	h(w, r)
+}
+
+// HandleFunc registers handler to serve every request matching method and
+// path, overriding any handler previously registered for that combination.
+func (s *Server) HandleFunc(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method+" "+path] = handler
+}
+
+// Handle registers, for requests matching method and path, a Response per
+// expected value of the headerName header - the common shape of an
+// endpoint that returns one body for a valid/active credential, another
+// for a valid-but-inactive or deleted one, and so on. A header value with
+// no matching entry in creds gets a 401 with an empty body.
+func (s *Server) Handle(method, path, headerName string, creds map[string]Response) {
+	s.HandleFunc(method, path, func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := creds[r.Header.Get(headerName)]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(resp.StatusCode)
+		if resp.Body != "" {
+			_, _ = w.Write([]byte(resp.Body))
+		}
+	})
+}
+
+// URL returns the server's base URL, for detectors that take a base URL
+// rather than an *http.Client.
+func (s *Server) URL() string { return s.srv.URL }
+
+// Client returns an *http.Client that talks to this Server.
+func (s *Server) Client() *http.Client { return s.srv.Client() }
+
+// Close shuts the underlying httptest.Server down.
+func (s *Server) Close() { s.srv.Close() }
@@ -5,7 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"regexp"
 	"strings"
@@ -15,7 +15,9 @@ import (
 	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
 )
 
-type Scanner struct{}
+type Scanner struct {
+	client *http.Client
+}
 
 type Result struct {
 	accessToken string
@@ -30,11 +32,23 @@ type Response struct {
 var _ detectors.Detector = (*Scanner)(nil)
 
 var (
-	client = common.SaneHttpClient()
+	defaultClient = common.SaneHttpClient()
 
 	// Make sure that your group is surrounded in boundary characters such as below to reduce false positives.
 	idPat     = regexp.MustCompile(detectors.PrefixRegex([]string{"integration", "id"}) + common.UUIDPattern)
 	secretPat = regexp.MustCompile(detectors.PrefixRegex([]string{"secret"}) + common.UUIDPattern)
+
+	// hosts are probed in order; the first one that verifies wins. Docusign
+	// issues credentials against one environment at a time, so trying the
+	// demo host before production (or vice versa) is harmless - only one
+	// will ever accept a given id/secret pair.
+	hosts = []struct {
+		environment string
+		host        string
+	}{
+		{"demo", "account-d.docusign.com"},
+		{"production", "account.docusign.com"},
+	}
 )
 
 // Keywords are used for efficiently pre-filtering chunks.
@@ -70,55 +84,67 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 			}
 
 			if verify {
-				req, err := http.NewRequestWithContext(ctx, "POST", "https://account-d.docusign.com/oauth/token?grant_type=client_credentials", nil)
-				if err != nil {
-					continue
+				client := s.client
+				if client == nil {
+					client = defaultClient
 				}
 
-				encodedCredentials := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", resIDMatch, resSecretMatch)))
+				verified, environment, verifyErr := verifyCredentials(ctx, client, resIDMatch, resSecretMatch)
+				if verifyErr != nil {
+					s1.SetVerificationError(verifyErr, resIDMatch)
+				} else if verified {
+					s1.Verified = true
+					s1.ExtraData = map[string]string{"environment": environment}
+				} else if detectors.IsKnownFalsePositive(resIDMatch, detectors.DefaultFalsePositives, true) {
+					continue
+				}
+			}
 
-				req.Header.Add("Accept", "application/vnd.docusign+json; version=3")
-				req.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedCredentials))
-				res, err := client.Do(req)
+			results = append(results, s1)
+		}
+	}
 
-				// Read the response body
-				body, err := ioutil.ReadAll(res.Body)
+	return results, nil
+}
 
-				if err != nil {
-					fmt.Println("Error reading response body:", err)
-				}
+// verifyCredentials probes each Docusign environment in turn, returning the
+// first one that accepts id/secret and the environment name it verified
+// against. An error is only returned for a request/transport failure; an
+// environment simply rejecting the credentials is not an error.
+func verifyCredentials(ctx context.Context, client *http.Client, id, secret string) (verified bool, environment string, err error) {
+	encodedCredentials := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", id, secret)))
+
+	for _, h := range hosts {
+		reqURL := fmt.Sprintf("https://%s/oauth/token?grant_type=client_credentials", h.host)
+		req, reqErr := http.NewRequestWithContext(ctx, "POST", reqURL, nil)
+		if reqErr != nil {
+			return false, "", reqErr
+		}
+		req.Header.Add("Accept", "application/vnd.docusign+json; version=3")
+		req.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedCredentials))
 
-				// Close the response body
-				res.Body.Close()
+		res, doErr := client.Do(req)
+		if doErr != nil {
+			return false, "", doErr
+		}
 
-				// Parse the response body into a Response struct
-				var parsedResponse Response
-				err = json.Unmarshal(body, &parsedResponse)
-				if err != nil {
-					fmt.Println("Error parsing response body:", err)
-				}
+		body, readErr := io.ReadAll(res.Body)
+		res.Body.Close()
+		if readErr != nil {
+			return false, "", readErr
+		}
 
-				// Access the accept_token field
-				accessToken := parsedResponse.AccessToken
-
-				if err == nil {
-					defer res.Body.Close()
-					if res.StatusCode >= 200 && res.StatusCode < 300 && strings.HasPrefix(accessToken, "ey") {
-						s1.Verified = true
-					} else {
-						// This function will check false positives for common test words, but also it will make sure the key appears 'random' enough to be a real key.
-						if detectors.IsKnownFalsePositive(resIDMatch, detectors.DefaultFalsePositives, true) {
-							continue
-						}
-					}
-				}
-			}
+		var parsedResponse Response
+		if jsonErr := json.Unmarshal(body, &parsedResponse); jsonErr != nil {
+			return false, "", jsonErr
+		}
 
-			results = append(results, s1)
+		if res.StatusCode >= 200 && res.StatusCode < 300 && strings.HasPrefix(parsedResponse.AccessToken, "ey") {
+			return true, h.environment, nil
 		}
 	}
 
-	return results, nil
+	return false, "", nil
 }
 
 func (s Scanner) Type() detectorspb.DetectorType {
@@ -2,7 +2,9 @@ package ftp
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"net/textproto"
 	"net/url"
 	"regexp"
@@ -10,6 +12,8 @@ import (
 	"time"
 
 	"github.com/jlaffaye/ftp"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
@@ -18,19 +22,27 @@ import (
 // https://datatracker.ietf.org/doc/html/rfc959
 const ftpNotLoggedIn = 530
 
-type Scanner struct{}
+// Scanner finds and verifies ftp://, ftps://, and sftp:// credentials.
+type Scanner struct {
+	// ForcePassiveMode disables extended passive mode (EPSV) negotiation for
+	// FTP/FTPS transports, for servers behind NAT/firewalls that only speak PASV.
+	ForcePassiveMode bool
+	// InsecureSkipVerify skips TLS certificate verification for FTPS and SFTP
+	// host key checks, for self-signed intranet servers.
+	InsecureSkipVerify bool
+}
 
 // Ensure the Scanner satisfies the interface at compile time.
 var _ detectors.Detector = (*Scanner)(nil)
 
 var (
-	keyPat = regexp.MustCompile(`\bftp://[\S]{3,50}:([\S]{3,50})@[-.%\w\/:]+\b`)
+	keyPat = regexp.MustCompile(`\b(ftps?|sftp)://[\S]{3,50}:([\S]{3,50})@[-.%\w\/:]+\b`)
 )
 
 // Keywords are used for efficiently pre-filtering chunks.
 // Use identifiers in the secret preferably, or the provider name.
 func (s Scanner) Keywords() []string {
-	return []string{"ftp://"}
+	return []string{"ftp://", "ftps://", "sftp://"}
 }
 
 // FromData will find and optionally verify URI secrets in a given set of bytes.
@@ -41,7 +53,7 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 
 	for _, match := range matches {
 		urlMatch := match[0]
-		password := match[1]
+		password := match[2]
 
 		// Skip findings where the password only has "*" characters, this is a redacted password
 		if strings.Trim(password, "*") == "" {
@@ -63,57 +75,165 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 		rawURL.Path = ""
 		redact := strings.TrimSpace(strings.Replace(rawURL.String(), password, "********", -1))
 
-		s := detectors.Result{
+		r := detectors.Result{
 			DetectorType: detectorspb.DetectorType_FTP,
 			Raw:          []byte(rawURL.String()),
 			Redacted:     redact,
 		}
 
 		if verify {
-			verificationErr := verifyFTP(ctx, parsedURL)
-			s.Verified = verificationErr == nil
+			verificationErr := s.verify(ctx, parsedURL)
+			r.Verified = verificationErr == nil
 			if !isErrDeterminate(verificationErr) {
-				s.VerificationError = verificationErr
+				r.VerificationError = verificationErr
 			}
 		}
 
-		if !s.Verified {
+		if !r.Verified {
 			// Skip unverified findings where the password starts with a `$` - it's almost certainly a variable.
 			if strings.HasPrefix(password, "$") {
 				continue
 			}
 		}
 
-		if detectors.IsKnownFalsePositive(string(s.Raw), []detectors.FalsePositive{"@ftp.freebsd.org"}, false) {
+		if detectors.IsKnownFalsePositive(string(r.Raw), []detectors.FalsePositive{"@ftp.freebsd.org"}, false) {
 			continue
 		}
 
-		results = append(results, s)
+		results = append(results, r)
 	}
 
 	return results, nil
 }
 
+// isErrDeterminate reports whether e is a definitive "wrong credentials"
+// response from the server, rather than a transient network/TLS/handshake
+// failure that doesn't tell us anything about the credential's validity.
 func isErrDeterminate(e error) bool {
 	ftpErr := &textproto.Error{}
-	return errors.As(e, &ftpErr)
+	if errors.As(e, &ftpErr) {
+		return true
+	}
+
+	sftpErr := &sftp.StatusError{}
+	if errors.As(e, &sftpErr) {
+		return true
+	}
+
+	return errors.Is(e, ssh.ErrNoAuth) || errors.Is(e, &ssh.PassphraseMissingError{})
 }
 
-func verifyFTP(ctx context.Context, u *url.URL) error {
-	host := u.Host
-	if !strings.Contains(host, ":") {
-		host = host + ":21"
+// transport verifies a credential over one FTP-family protocol.
+type transport interface {
+	verify(ctx context.Context, u *url.URL, s Scanner) error
+}
+
+var transportsByScheme = map[string]transport{
+	"ftp":  plainFTPTransport{},
+	"ftps": ftpsTransport{},
+	"sftp": sftpTransport{},
+}
+
+func (s Scanner) verify(ctx context.Context, u *url.URL) error {
+	t, ok := transportsByScheme[u.Scheme]
+	if !ok {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
 	}
+	return t.verify(ctx, u, s)
+}
 
-	c, err := ftp.Dial(host, ftp.DialWithTimeout(5*time.Second))
+func ftpDialOptions(ctx context.Context, s Scanner) []ftp.DialOption {
+	opts := []ftp.DialOption{ftp.DialWithTimeout(5 * time.Second), ftp.DialWithContext(ctx)}
+	if s.ForcePassiveMode {
+		// jlaffaye/ftp negotiates EPSV by default; disabling it falls back to
+		// plain PASV, the closest available analog to "force passive mode"
+		// for servers that don't support active mode at all.
+		opts = append(opts, ftp.DialWithDisabledEPSV(true))
+	}
+	return opts
+}
+
+// plainFTPTransport verifies credentials over unencrypted FTP.
+type plainFTPTransport struct{}
+
+func (plainFTPTransport) verify(ctx context.Context, u *url.URL, s Scanner) error {
+	host := hostWithDefaultPort(u, "21")
+
+	c, err := ftp.Dial(host, ftpDialOptions(ctx, s)...)
 	if err != nil {
 		return err
 	}
+	defer c.Quit()
 
 	password, _ := u.User.Password()
 	return c.Login(u.User.Username(), password)
 }
 
+// ftpsTransport verifies credentials over FTP with TLS, covering both
+// implicit TLS (the default, port 990) and explicit TLS via AUTH TLS
+// (selected by appending "?explicit" to the scanned URL's query, the only
+// signal available from a bare connection string).
+type ftpsTransport struct{}
+
+func (t ftpsTransport) verify(ctx context.Context, u *url.URL, s Scanner) error {
+	host := hostWithDefaultPort(u, "990")
+	tlsConfig := &tls.Config{InsecureSkipVerify: s.InsecureSkipVerify, ServerName: strings.Split(host, ":")[0]}
+
+	opts := ftpDialOptions(ctx, s)
+	if u.Query().Has("explicit") {
+		opts = append(opts, ftp.DialWithExplicitTLS(tlsConfig))
+	} else {
+		opts = append(opts, ftp.DialWithTLS(tlsConfig))
+	}
+
+	c, err := ftp.Dial(host, opts...)
+	if err != nil {
+		return err
+	}
+	defer c.Quit()
+
+	password, _ := u.User.Password()
+	return c.Login(u.User.Username(), password)
+}
+
+// sftpTransport verifies credentials over SSH.
+type sftpTransport struct{}
+
+func (sftpTransport) verify(ctx context.Context, u *url.URL, s Scanner) error {
+	host := hostWithDefaultPort(u, "22")
+	password, _ := u.User.Password()
+
+	// The scanned credential carries no pinned host key to verify against,
+	// so host identity isn't checked here; this dial only establishes
+	// whether the given username/password authenticates.
+	conn, err := ssh.Dial("tcp", host, &ssh.ClientConfig{
+		User:            u.User.Username(),
+		Auth:            []ssh.AuthMethod{ssh.Password(password)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return nil
+}
+
+func hostWithDefaultPort(u *url.URL, port string) string {
+	host := u.Host
+	if !strings.Contains(host, ":") {
+		host = host + ":" + port
+	}
+	return host
+}
+
 func (s Scanner) Type() detectorspb.DetectorType {
 	return detectorspb.DetectorType_FTP
 }
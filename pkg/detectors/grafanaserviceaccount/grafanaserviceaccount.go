@@ -2,7 +2,9 @@ package grafanaserviceaccount
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -14,6 +16,15 @@ import (
 
 type Scanner struct {
 	client *http.Client
+
+	// DiscoverStacks enables an additional verification path for a glsa_
+	// token found without an accompanying *.grafana.net domain in the same
+	// chunk (common in CI configs where the stack URL lives elsewhere): it
+	// calls grafana.com/api/instances with the token to enumerate the
+	// stacks it can access, then verifies against each one. Off by default
+	// to preserve existing behavior, since it costs an extra API call per
+	// domain-less token.
+	DiscoverStacks bool
 }
 
 // Ensure the Scanner satisfies the interface at compile time.
@@ -45,6 +56,18 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 		}
 		key := strings.TrimSpace(match[1])
 
+		client := s.client
+		if client == nil {
+			client = defaultClient
+		}
+
+		if len(domainMatches) == 0 {
+			if verify && s.DiscoverStacks {
+				results = append(results, s.verifyViaStackDiscovery(ctx, client, key)...)
+			}
+			continue
+		}
+
 		for _, domainMatch := range domainMatches {
 			if len(domainMatch) != 2 {
 				continue
@@ -58,28 +81,11 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 			}
 
 			if verify {
-				client := s.client
-				if client == nil {
-					client = defaultClient
-				}
-				req, err := http.NewRequestWithContext(ctx, "GET", "https://"+domainRes+"/api/access-control/user/permissions", nil)
-				if err != nil {
-					continue
-				}
-				req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", key))
-				res, err := client.Do(req)
-				if err == nil {
-					defer res.Body.Close()
-					if res.StatusCode >= 200 && res.StatusCode < 300 {
-						s1.Verified = true
-					} else if res.StatusCode == 401 {
-						// The secret is determinately not verified (nothing to do)
-					} else {
-						err = fmt.Errorf("unexpected HTTP response status %d", res.StatusCode)
-						s1.SetVerificationError(err, key)
-					}
+				verified, verifyErr := verifyAgainstStack(ctx, client, "https://"+domainRes, key)
+				if verifyErr != nil {
+					s1.SetVerificationError(verifyErr, key)
 				} else {
-					s1.SetVerificationError(err, key)
+					s1.Verified = verified
 				}
 			}
 
@@ -90,6 +96,131 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 	return results, nil
 }
 
+// verifyViaStackDiscovery handles a glsa_ token found with no accompanying
+// *.grafana.net domain in its chunk: it calls grafana.com/api/instances with
+// the token to enumerate the stacks it can access, then verifies against
+// each one in turn, returning one Result per stack.
+func (s Scanner) verifyViaStackDiscovery(ctx context.Context, client *http.Client, key string) []detectors.Result {
+	stacks, err := discoverStacks(ctx, client, key)
+	if err != nil {
+		s1 := detectors.Result{
+			DetectorType: detectorspb.DetectorType_GrafanaServiceAccount,
+			Raw:          []byte(key),
+			RawV2:        []byte(key),
+		}
+		s1.SetVerificationError(err, key)
+		return []detectors.Result{s1}
+	}
+
+	results := make([]detectors.Result, 0, len(stacks))
+	for _, stack := range stacks {
+		s1 := detectors.Result{
+			DetectorType: detectorspb.DetectorType_GrafanaServiceAccount,
+			Raw:          []byte(key),
+			RawV2:        []byte(fmt.Sprintf("%s:%s", stack.slug, key)),
+		}
+
+		verified, verifyErr := verifyAgainstStack(ctx, client, stack.url, key)
+		if verifyErr != nil {
+			s1.SetVerificationError(verifyErr, key)
+		} else if verified {
+			s1.Verified = true
+			s1.ExtraData = map[string]string{"stack_slug": stack.slug, "org_slug": stack.orgSlug}
+		}
+
+		results = append(results, s1)
+	}
+	return results
+}
+
+// verifyAgainstStack checks key against a single Grafana stack, identified
+// by its base URL (with or without a trailing slash, with or without a
+// scheme - "https://" is assumed if one isn't present).
+func verifyAgainstStack(ctx context.Context, client *http.Client, baseURL, key string) (bool, error) {
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/api/access-control/user/permissions", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", key))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return true, nil
+	case res.StatusCode == 401:
+		// The secret is determinately not verified against this stack.
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected HTTP response status %d", res.StatusCode)
+	}
+}
+
+// grafanaStack is one Grafana Cloud stack a service account token can reach,
+// as reported by grafana.com/api/instances.
+type grafanaStack struct {
+	slug    string
+	url     string
+	orgSlug string
+}
+
+// discoverStacks calls grafana.com/api/instances with key to enumerate the
+// stacks it can access. A non-2xx response (e.g. the token being invalid
+// outright) is reported as zero stacks rather than an error, since it isn't
+// a transport failure - FromData falls back to reporting the token
+// unverified, the same as it would with no domain at all.
+func discoverStacks(ctx context.Context, client *http.Client, key string) ([]grafanaStack, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://grafana.com/api/instances", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", key))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Items []struct {
+			Slug    string `json:"slug"`
+			URL     string `json:"url"`
+			OrgSlug string `json:"orgSlug"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+
+	stacks := make([]grafanaStack, 0, len(parsed.Items))
+	for _, item := range parsed.Items {
+		if item.URL == "" {
+			continue
+		}
+		stacks = append(stacks, grafanaStack{slug: item.Slug, url: item.URL, orgSlug: item.OrgSlug})
+	}
+	return stacks, nil
+}
+
 func (s Scanner) Type() detectorspb.DetectorType {
 	return detectorspb.DetectorType_GrafanaServiceAccount
 }
@@ -0,0 +1,200 @@
+package detectors
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BasicAuthCredential is a static HTTP Basic Auth username/password pair.
+type BasicAuthCredential struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// HostCredential describes outbound credentials that should be applied to a
+// verification request whose URL host matches HostPattern. HostPattern is
+// matched with path.Match, so globs like "*.databricks.internal" are allowed.
+//
+// This exists so a verifier (e.g. auth0oauth, databrickstoken) doesn't have to
+// know it's running behind an authenticating egress proxy, or that an internal
+// workspace is fronted by BasicAuth: the credentials are injected transparently
+// by HostCredentialTransport based on the request's destination host.
+type HostCredential struct {
+	HostPattern string `yaml:"host"`
+
+	BasicAuth *BasicAuthCredential `yaml:"basicAuth,omitempty"`
+	// BearerToken is sent as a static `Authorization: Bearer <token>` header.
+	BearerToken string `yaml:"bearerToken,omitempty"`
+	// ClientCertFile and ClientKeyFile configure mTLS for requests to this host.
+	ClientCertFile string `yaml:"clientCertFile,omitempty"`
+	ClientKeyFile  string `yaml:"clientKeyFile,omitempty"`
+}
+
+// HostCredentialConfig is the top-level YAML document for per-host
+// verification credentials.
+type HostCredentialConfig struct {
+	Hosts []HostCredential `yaml:"hosts"`
+}
+
+// Environment variables used to configure a single host credential without a
+// config file, e.g. for scans run from CI behind a corporate proxy.
+const (
+	envVerificationHost       = "TRUFFLEHOG_VERIFICATION_HOST"
+	envVerificationBasicUser  = "TRUFFLEHOG_VERIFICATION_BASIC_USER"
+	envVerificationBasicPass  = "TRUFFLEHOG_VERIFICATION_BASIC_PASS"
+	envVerificationBearer     = "TRUFFLEHOG_VERIFICATION_BEARER_TOKEN"
+	envVerificationClientCert = "TRUFFLEHOG_VERIFICATION_CLIENT_CERT"
+	envVerificationClientKey  = "TRUFFLEHOG_VERIFICATION_CLIENT_KEY"
+)
+
+// LoadHostCredentialConfig reads per-host verification credentials from a YAML
+// file at configPath (skipped if empty) and overlays a single host credential
+// built from TRUFFLEHOG_VERIFICATION_* environment variables, if set.
+func LoadHostCredentialConfig(configPath string) (*HostCredentialConfig, error) {
+	cfg := &HostCredentialConfig{}
+
+	if configPath != "" {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read host credential config: %w", err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("could not parse host credential config: %w", err)
+		}
+	}
+
+	if cred, ok := hostCredentialFromEnv(); ok {
+		cfg.Hosts = append(cfg.Hosts, cred)
+	}
+
+	return cfg, nil
+}
+
+func hostCredentialFromEnv() (HostCredential, bool) {
+	host := os.Getenv(envVerificationHost)
+	if host == "" {
+		return HostCredential{}, false
+	}
+
+	cred := HostCredential{
+		HostPattern:    host,
+		BearerToken:    os.Getenv(envVerificationBearer),
+		ClientCertFile: os.Getenv(envVerificationClientCert),
+		ClientKeyFile:  os.Getenv(envVerificationClientKey),
+	}
+
+	if user := os.Getenv(envVerificationBasicUser); user != "" {
+		cred.BasicAuth = &BasicAuthCredential{Username: user, Password: os.Getenv(envVerificationBasicPass)}
+	}
+
+	return cred, true
+}
+
+// HostCredentialTransport is an http.RoundTripper that injects configured
+// per-host credentials into outbound verification requests based on the
+// request's destination host, so verifiers can keep calling
+// DetectorHttpClientWith... without hard-coding proxy or internal-workspace
+// credentials themselves.
+type HostCredentialTransport struct {
+	Next  http.RoundTripper
+	Hosts []HostCredential
+
+	mu        sync.Mutex
+	tlsByHost map[string]*tls.Config
+}
+
+// NewHostCredentialTransport wraps next with per-host credential injection
+// based on cfg. If next is nil, http.DefaultTransport is used.
+func NewHostCredentialTransport(next http.RoundTripper, cfg *HostCredentialConfig) *HostCredentialTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &HostCredentialTransport{Next: next, tlsByHost: make(map[string]*tls.Config)}
+	if cfg != nil {
+		t.Hosts = cfg.Hosts
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *HostCredentialTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cred, ok := t.matchHost(req.URL.Hostname())
+	if !ok {
+		return t.Next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	if cred.BasicAuth != nil {
+		req.SetBasicAuth(cred.BasicAuth.Username, cred.BasicAuth.Password)
+	}
+	if cred.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cred.BearerToken)
+	}
+
+	transport := t.Next
+	if cred.ClientCertFile != "" && cred.ClientKeyFile != "" {
+		tlsCfg, err := t.tlsConfigFor(cred)
+		if err != nil {
+			return nil, err
+		}
+		if baseTransport, ok := t.Next.(*http.Transport); ok {
+			cloned := baseTransport.Clone()
+			cloned.TLSClientConfig = tlsCfg
+			transport = cloned
+		}
+	}
+
+	return transport.RoundTrip(req)
+}
+
+func (t *HostCredentialTransport) matchHost(host string) (HostCredential, bool) {
+	for _, cred := range t.Hosts {
+		if ok, _ := path.Match(cred.HostPattern, host); ok {
+			return cred, true
+		}
+	}
+	return HostCredential{}, false
+}
+
+func (t *HostCredentialTransport) tlsConfigFor(cred HostCredential) (*tls.Config, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cfg, ok := t.tlsByHost[cred.HostPattern]; ok {
+		return cfg, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cred.ClientCertFile, cred.ClientKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load client certificate for %q: %w", cred.HostPattern, err)
+	}
+
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+	t.tlsByHost[cred.HostPattern] = cfg
+	return cfg, nil
+}
+
+// RedactHostCredentials scrubs any configured per-host secret values out of s,
+// so that a host credential injected for verification can never leak into a
+// verification error surfaced on a Result.
+func RedactHostCredentials(s string, cfg *HostCredentialConfig) string {
+	if cfg == nil {
+		return s
+	}
+	for _, cred := range cfg.Hosts {
+		if cred.BasicAuth != nil && cred.BasicAuth.Password != "" {
+			s = strings.ReplaceAll(s, cred.BasicAuth.Password, "REDACTED")
+		}
+		if cred.BearerToken != "" {
+			s = strings.ReplaceAll(s, cred.BearerToken, "REDACTED")
+		}
+	}
+	return s
+}
@@ -3,9 +3,13 @@ package myfreshworks
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"regexp"
+	"sort"
 
 	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors"
@@ -23,59 +27,199 @@ var (
 	idPat  = regexp.MustCompile(detectors.PrefixRegex([]string{"freshworks"}) + `\b([a-zA-Z0-9-_]{2,20})\b`)
 )
 
+// maxIDCandidatesPerKey bounds how many account-id candidates are tried per
+// key, so verification stays close to the key's own occurrence in the chunk
+// instead of pairing every key with every id found anywhere in it.
+const maxIDCandidatesPerKey = 5
+
+// whoamiProducts are the Freshworks account-info endpoints checked for each
+// id candidate, one per product suite that shares the token/account-id auth
+// scheme.
+var whoamiProducts = []struct {
+	product string
+	domain  string
+}{
+	{"Freshdesk", "freshdesk.com"},
+	{"Freshsales/Freshservice", "myfreshworks.com"},
+}
+
 func (s Scanner) Keywords() [][]byte {
 	return [][]byte{[]byte("freshworks")}
 }
 
 func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (results []detectors.Result, err error) {
-
-	matches := keyPat.FindAllSubmatch(data, -1)
-	idmatches := idPat.FindAllSubmatch(data, -1)
+	matches := keyPat.FindAllSubmatchIndex(data, -1)
+	idMatches := idPat.FindAllSubmatchIndex(data, -1)
 
 	for _, match := range matches {
-		if len(match) != 2 {
+		if len(match) != 4 {
 			continue
 		}
-		resMatch := bytes.TrimSpace(match[1])
-		for _, idmatch := range idmatches {
-			if len(idmatch) != 2 {
-				continue
-			}
-			resIdMatch := bytes.TrimSpace(idmatch[1])
+		resMatch := bytes.TrimSpace(data[match[2]:match[3]])
 
-			s1 := detectors.Result{
-				DetectorType: detectorspb.DetectorType_Myfreshworks,
-				Raw:          resMatch,
-			}
+		s1 := detectors.Result{
+			DetectorType: detectorspb.DetectorType_Myfreshworks,
+			Raw:          resMatch,
+		}
 
-			if verify {
-				req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://%s.myfreshworks.com/crm/sales/api/sales_accounts/filters", string(resIdMatch)), nil)
-				if err != nil {
-					continue
+		if verify {
+			var lastErr error
+			for _, id := range nearbyIDCandidates(data, match, idMatches) {
+				verified, extraData, verificationErr := verifyAgainstProducts(ctx, resMatch, id)
+				if verified {
+					s1.Verified = true
+					s1.ExtraData = extraData
+					lastErr = nil
+					break
 				}
-				req.Header.Add("Authorization", fmt.Sprintf("Token token=%s", string(resMatch)))
-				res, err := client.Do(req)
-				if err == nil {
-					defer res.Body.Close()
-					if res.StatusCode >= 200 && res.StatusCode < 300 {
-						s1.Verified = true
-					} else {
-						if detectors.IsKnownFalsePositive(resMatch, detectors.DefaultFalsePositives, true) {
-							continue
-						}
-					}
+				if verificationErr != nil {
+					lastErr = verificationErr
 				}
 			}
+			if lastErr != nil {
+				s1.SetVerificationError(lastErr, string(resMatch))
+			}
+		}
 
-			results = append(results, s1)
-
+		if !s1.Verified && detectors.IsKnownFalsePositive(resMatch, detectors.DefaultFalsePositives, true) {
+			continue
 		}
 
+		results = append(results, s1)
 	}
 
 	return results, nil
 }
 
+// nearbyIDCandidates returns up to maxIDCandidatesPerKey id matches closest
+// to keyMatch's position in data, since the account id a key belongs to is
+// almost always declared near it rather than anywhere in the chunk.
+func nearbyIDCandidates(data []byte, keyMatch []int, idMatches [][]int) []string {
+	type candidate struct {
+		id       string
+		distance int
+	}
+
+	keyStart := keyMatch[2]
+	candidates := make([]candidate, 0, len(idMatches))
+	for _, idMatch := range idMatches {
+		id := string(bytes.TrimSpace(data[idMatch[2]:idMatch[3]]))
+		if id == "" {
+			continue
+		}
+		distance := idMatch[2] - keyStart
+		if distance < 0 {
+			distance = -distance
+		}
+		candidates = append(candidates, candidate{id, distance})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	if len(candidates) > maxIDCandidatesPerKey {
+		candidates = candidates[:maxIDCandidatesPerKey]
+	}
+
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+// verifyAgainstProducts tries key against every Freshworks product's
+// account-info endpoint for id, returning the first verified match's
+// resolved product, account id, and owner email.
+func verifyAgainstProducts(ctx context.Context, key []byte, id string) (bool, map[string]string, error) {
+	var lastErr error
+	for _, p := range whoamiProducts {
+		verified, extraData, err := verifyWhoami(ctx, key, id, p.product, p.domain)
+		if err != nil {
+			if !isErrDeterminate(err) {
+				lastErr = err
+			}
+			continue
+		}
+		if verified {
+			return true, extraData, nil
+		}
+	}
+	return false, nil, lastErr
+}
+
+// verifyWhoami calls the given product's /api/v2/whoami endpoint for
+// account id on domain, authenticating with key.
+func verifyWhoami(ctx context.Context, key []byte, id, product, domain string) (bool, map[string]string, error) {
+	url := fmt.Sprintf("https://%s.%s/api/v2/whoami", id, domain)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, nil, err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Token token=%s", string(key)))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return false, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return false, nil, &statusError{code: res.StatusCode}
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var whoami struct {
+		ID      int    `json:"id"`
+		Email   string `json:"email"`
+		Contact struct {
+			Email string `json:"email"`
+		} `json:"contact"`
+	}
+	if err := json.Unmarshal(body, &whoami); err != nil {
+		return false, nil, err
+	}
+
+	email := whoami.Email
+	if email == "" {
+		email = whoami.Contact.Email
+	}
+
+	extraData := map[string]string{
+		"product":    product,
+		"account_id": id,
+	}
+	if email != "" {
+		extraData["owner_email"] = email
+	}
+
+	return true, extraData, nil
+}
+
+// statusError carries an HTTP status code back from a whoami request so
+// isErrDeterminate can tell a conclusive "credential rejected" response from
+// a transient network/server failure.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP response status %d", e.code)
+}
+
+// isErrDeterminate reports whether err is a definitive "credential doesn't
+// grant access to this account" response (401/403), rather than a transient
+// failure that doesn't tell us anything about the credential's validity.
+func isErrDeterminate(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code == http.StatusUnauthorized || statusErr.code == http.StatusForbidden
+	}
+	return false
+}
+
 func (s Scanner) Type() detectorspb.DetectorType {
 	return detectorspb.DetectorType_Myfreshworks
 }
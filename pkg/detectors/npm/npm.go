@@ -0,0 +1,13 @@
+// Package npm holds shared constants for the npm token-format detectors
+// under pkg/detectors/npm/token.
+package npm
+
+// TokenVersion identifies which of npm's auth token formats a detector
+// matches, so a single provider can be represented by more than one
+// detectorspb entry as npm introduces new token shapes.
+type TokenVersion int
+
+const (
+	// TokenUuid is npm's legacy UUID-shaped auth token.
+	TokenUuid TokenVersion = iota
+)
@@ -0,0 +1,103 @@
+// Package token holds verification logic shared by every npm token-format
+// detector (see pkg/detectors/npm/token/uuid) - only the regex matching a
+// given format's shape differs between them.
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors"
+)
+
+// npmWhoamiURL is npm's "who does this token belong to" endpoint - the
+// standard way to confirm an npm auth token is live without requiring
+// access to any particular package. It's a var rather than a const so
+// tests can point it at a local fixture server.
+var npmWhoamiURL = "https://registry.npmjs.org/-/npm/v1/user"
+
+// batchVerifyConcurrency bounds how many whoami calls VerifyBatch has in
+// flight at once. npm has no actual bulk-verification endpoint, so
+// VerifyBatch parallelizes BaseScanner's ordinary per-token VerifyToken
+// rather than collapsing to a single request - it still cuts wall-clock
+// time and smooths rate-limit bursts compared to verifying a chunk's
+// tokens one at a time in sequence.
+const batchVerifyConcurrency = 8
+
+// BaseScanner is embedded by each npm token-format Scanner to share
+// verification logic.
+type BaseScanner struct {
+	client *http.Client
+}
+
+func (b BaseScanner) httpClient() *http.Client {
+	if b.client != nil {
+		return b.client
+	}
+	return common.SaneHttpClient()
+}
+
+// VerifyToken checks whether token is a live npm auth token by calling
+// npm's whoami endpoint. dataStr is accepted for parity with detectors
+// whose verification needs surrounding chunk context; npm's doesn't, so
+// it's unused here.
+func (b BaseScanner) VerifyToken(ctx context.Context, dataStr, token string) (bool, map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, npmWhoamiURL, nil)
+	if err != nil {
+		return false, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := b.httpClient().Do(req)
+	if err != nil {
+		return false, nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		var whoami struct {
+			Username string `json:"username"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&whoami); err != nil || whoami.Username == "" {
+			return true, nil, nil
+		}
+		return true, map[string]string{"username": whoami.Username}, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, nil, nil
+	default:
+		return false, nil, fmt.Errorf("unexpected status verifying npm token: %d", res.StatusCode)
+	}
+}
+
+// Ensure BaseScanner satisfies detectors.BatchVerifier at compile time.
+var _ detectors.BatchVerifier = (*BaseScanner)(nil)
+
+// VerifyBatch verifies every candidate concurrently, bounded by
+// batchVerifyConcurrency, returning one BatchResult per candidate at the
+// same index. It never itself returns an error - a single candidate's
+// verification failure is reported on that candidate's BatchResult.Err
+// rather than failing the whole batch.
+func (b BaseScanner) VerifyBatch(ctx context.Context, candidates []detectors.Candidate) ([]detectors.BatchResult, error) {
+	results := make([]detectors.BatchResult, len(candidates))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(batchVerifyConcurrency)
+
+	for i, c := range candidates {
+		i, c := i, c
+		g.Go(func() error {
+			verified, extraData, err := b.VerifyToken(gctx, "", c.Raw)
+			results[i] = detectors.BatchResult{Verified: verified, ExtraData: extraData, Err: err}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package token
+
+import (
+	"context"
+	"testing"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors/testserver"
+)
+
+func withFakeWhoami(t *testing.T, srv *testserver.Server) {
+	t.Helper()
+	orig := npmWhoamiURL
+	npmWhoamiURL = srv.URL()
+	t.Cleanup(func() { npmWhoamiURL = orig })
+}
+
+func TestBaseScanner_VerifyToken(t *testing.T) {
+	tests := []struct {
+		name         string
+		token        string
+		wantVerified bool
+		wantErr      bool
+	}{
+		{"verified", "a-real-looking-token", true, false},
+		{"forbidden is unverified, no error", "token-" + testserver.MagicForbidden, false, false},
+		{"unexpected status is an error", "token-" + testserver.MagicRateLimited, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := testserver.New(`{"username":"trufflehog-test"}`)
+			defer srv.Close()
+			withFakeWhoami(t, srv)
+
+			b := BaseScanner{client: srv.Client()}
+			verified, extraData, err := b.VerifyToken(context.Background(), "", tt.token)
+
+			if verified != tt.wantVerified {
+				t.Errorf("VerifyToken() verified = %v, want %v", verified, tt.wantVerified)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyToken() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantVerified && extraData["username"] != "trufflehog-test" {
+				t.Errorf("VerifyToken() extraData = %v, want username trufflehog-test", extraData)
+			}
+		})
+	}
+}
+
+func TestBaseScanner_VerifyBatch(t *testing.T) {
+	srv := testserver.New(`{"username":"trufflehog-test"}`)
+	defer srv.Close()
+	withFakeWhoami(t, srv)
+
+	b := BaseScanner{client: srv.Client()}
+	candidates := []detectors.Candidate{
+		{Raw: "good-token-1"},
+		{Raw: "token-" + testserver.MagicForbidden},
+		{Raw: "good-token-2"},
+	}
+
+	results, err := b.VerifyBatch(context.Background(), candidates)
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	if len(results) != len(candidates) {
+		t.Fatalf("VerifyBatch() returned %d results, want %d", len(results), len(candidates))
+	}
+	if !results[0].Verified || !results[2].Verified {
+		t.Errorf("expected good tokens verified, got %+v", results)
+	}
+	if results[1].Verified {
+		t.Errorf("expected forbidden token unverified, got %+v", results[1])
+	}
+}
@@ -46,27 +46,64 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 		tokens[m] = struct{}{}
 	}
 
-	// Handle results.
-	for t := range tokens {
-		r := detectors.Result{
-			DetectorType: s.Type(),
-			Raw:          []byte(t),
+	if !verify {
+		for t := range tokens {
+			results = append(results, detectors.Result{DetectorType: s.Type(), Raw: []byte(t)})
 		}
+		return results, nil
+	}
 
-		if verify {
-			verified, extraData, vErr := s.VerifyToken(logCtx, dataStr, t)
-			r.Verified = verified
-			r.ExtraData = extraData
-			if vErr != nil {
-				if errors.Is(vErr, detectors.ErrNoLocalIP) {
-					continue
+	ordered := make([]string, 0, len(tokens))
+	for t := range tokens {
+		ordered = append(ordered, t)
+	}
+
+	verified := make([]bool, len(ordered))
+	extraData := make([]map[string]string, len(ordered))
+	vErrs := make([]error, len(ordered))
+
+	// A chunk with more than one candidate can be verified in a single
+	// batched round trip instead of one call per token - see
+	// detectors.BatchVerifier.
+	usedBatch := false
+	if len(ordered) > 1 {
+		if bv, ok := any(s.BaseScanner).(detectors.BatchVerifier); ok {
+			candidates := make([]detectors.Candidate, len(ordered))
+			for i, t := range ordered {
+				candidates[i] = detectors.Candidate{Raw: t}
+			}
+			if batchResults, bErr := bv.VerifyBatch(logCtx, candidates); bErr == nil && len(batchResults) == len(ordered) {
+				for i, br := range batchResults {
+					verified[i], extraData[i], vErrs[i] = br.Verified, br.ExtraData, br.Err
 				}
-				r.SetVerificationError(vErr)
+				usedBatch = true
 			}
 		}
+	}
+
+	if !usedBatch {
+		for i, t := range ordered {
+			verified[i], extraData[i], vErrs[i] = s.VerifyToken(logCtx, dataStr, t)
+		}
+	}
+
+	// Handle results.
+	for i, t := range ordered {
+		if vErrs[i] != nil && errors.Is(vErrs[i], detectors.ErrNoLocalIP) {
+			continue
+		}
 
+		r := detectors.Result{
+			DetectorType: s.Type(),
+			Raw:          []byte(t),
+			Verified:     verified[i],
+			ExtraData:    extraData[i],
+		}
+		if vErrs[i] != nil {
+			r.SetVerificationError(vErrs[i])
+		}
 		results = append(results, r)
 	}
 
-	return
+	return results, nil
 }
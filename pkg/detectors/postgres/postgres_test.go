@@ -4,30 +4,21 @@
 package postgres
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os/exec"
-	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors/dbtest"
 
 	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
 )
 
-var postgresDockerHash string
-
 const (
-	postgresUser = "postgres"
-	postgresPass = "23201dabb56ca236f3dc6736c0f9afad"
-	postgresHost = "localhost"
-	postgresPort = "5434" // Do not use 5433, as local dev environments can use it for other things
-
 	inactiveUser = "inactive"
 	inactivePass = "inactive"
 	inactivePort = "61000"
@@ -35,14 +26,8 @@ const (
 )
 
 func TestPostgres_FromChunk(t *testing.T) {
-	if err := startPostgres(); err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			t.Fatalf("could not start local postgres: %v w/stderr:\n%s", err, string(exitErr.Stderr))
-		} else {
-			t.Fatalf("could not start local postgres: %v", err)
-		}
-	}
-	defer stopPostgres()
+	conn := dbtest.StartPostgres(t)
+	postgresUser, postgresPass, postgresHost, postgresPort := conn.User, conn.Password, conn.Host, conn.Port
 
 	type args struct {
 		ctx    context.Context
@@ -175,50 +160,6 @@ func TestPostgres_FromChunk(t *testing.T) {
 	}
 }
 
-func dockerLogLine(hash string, needle string) chan struct{} {
-	ch := make(chan struct{}, 1)
-	go func() {
-		for {
-			out, err := exec.Command("docker", "logs", hash).CombinedOutput()
-			if err != nil {
-				panic(err)
-			}
-			if strings.Contains(string(out), needle) {
-				ch <- struct{}{}
-				return
-			}
-			time.Sleep(1 * time.Second)
-		}
-	}()
-	return ch
-}
-
-func startPostgres() error {
-	cmd := exec.Command(
-		"docker", "run", "--rm", "-p", postgresPort+":"+defaultPort,
-		"-e", "POSTGRES_PASSWORD="+postgresPass,
-		"-e", "POSTGRES_USER="+postgresUser,
-		"-d", "postgres",
-	)
-	fmt.Println(cmd.String())
-	out, err := cmd.Output()
-	if err != nil {
-		return err
-	}
-	postgresDockerHash = string(bytes.TrimSpace(out))
-	select {
-	case <-dockerLogLine(postgresDockerHash, "PostgreSQL init process complete; ready for start up."):
-		return nil
-	case <-time.After(30 * time.Second):
-		stopPostgres()
-		return errors.New("timeout waiting for postgres database to be ready")
-	}
-}
-
-func stopPostgres() {
-	exec.Command("docker", "kill", postgresDockerHash).Run()
-}
-
 func BenchmarkFromData(benchmark *testing.B) {
 	ctx := context.Background()
 	s := Scanner{}
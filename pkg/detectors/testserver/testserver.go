@@ -0,0 +1,94 @@
+// Package testserver provides an httptest-backed verification endpoint for
+// detector tests whose behavior is selected by a magic substring embedded in
+// the candidate secret value itself, rather than by request method/path (for
+// that, see pkg/detectors/detectortest). This models providers like npm's
+// whoami endpoint, where every candidate hits the same URL and only the
+// bearer token distinguishes one request from another - a test can't key
+// behavior off the path, so it keys off the token instead.
+package testserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+const (
+	// MagicForbidden makes a candidate containing it receive a 403 response.
+	MagicForbidden = "status-verify-403"
+	// MagicRateLimited makes a candidate containing it receive a 429
+	// response with a Retry-After header.
+	MagicRateLimited = "status-verify-ratelimit"
+	// MagicTimeout makes a candidate containing it block past any
+	// reasonable caller deadline instead of responding.
+	MagicTimeout = "status-verify-timeout"
+	// MagicPartial makes a candidate containing it receive a 200 response
+	// with a truncated, invalid-JSON body.
+	MagicPartial = "status-verify-partial"
+)
+
+// retryAfterSeconds is the Retry-After value returned for MagicRateLimited.
+const retryAfterSeconds = "30"
+
+// timeoutDelay is how long the server blocks for MagicTimeout - long enough
+// that any test-scoped context deadline or client timeout elapses first.
+const timeoutDelay = 5 * time.Second
+
+// Server is an httptest.Server whose single handler inspects the incoming
+// request's Authorization header (or, failing that, its raw query/body) for
+// one of the Magic* substrings and responds accordingly. Requests containing
+// none of them fall through to a 200 with okBody, so a server can also be
+// used to simulate the ordinary successful-verification case.
+type Server struct {
+	srv    *httptest.Server
+	okBody string
+}
+
+// New starts a Server. okBody is the response body returned for requests
+// that don't match any Magic* substring - callers pass whatever a real
+// successful verification response from their provider looks like.
+func New(okBody string) *Server {
+	s := &Server{okBody: okBody}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	candidate := r.Header.Get("Authorization")
+	if candidate == "" {
+		candidate = r.URL.RawQuery
+	}
+
+	switch {
+	case strings.Contains(candidate, MagicForbidden):
+		w.WriteHeader(http.StatusForbidden)
+	case strings.Contains(candidate, MagicRateLimited):
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		w.WriteHeader(http.StatusTooManyRequests)
+	case strings.Contains(candidate, MagicTimeout):
+		select {
+		case <-time.After(timeoutDelay):
+		case <-r.Context().Done():
+		}
+	case strings.Contains(candidate, MagicPartial):
+		w.WriteHeader(http.StatusOK)
+		body := s.okBody
+		if len(body) > len(body)/2 {
+			body = body[:len(body)/2]
+		}
+		_, _ = w.Write([]byte(body))
+	default:
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(s.okBody))
+	}
+}
+
+// URL is the base URL of the running server.
+func (s *Server) URL() string { return s.srv.URL }
+
+// Client returns the server's default HTTP client.
+func (s *Server) Client() *http.Client { return s.srv.Client() }
+
+// Close shuts down the underlying httptest.Server.
+func (s *Server) Close() { s.srv.Close() }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	regexp "github.com/wasilibs/go-re2"
 
@@ -26,6 +27,12 @@ var (
 	identifierPat = regexp.MustCompile(`(?i)sid.{0,20}AC[0-9a-f]{32}`) // Should we have this? Seems restrictive.
 	sidPat        = regexp.MustCompile(`\bAC[0-9a-f]{32}\b`)
 	keyPat        = regexp.MustCompile(`\b[0-9a-f]{32}\b`)
+
+	// apiKeySidPat matches a Twilio API Key SID. Unlike the Account SID + Auth Token
+	// pair above, an API Key SID (and its secret) authenticates on behalf of a parent
+	// account, so a match here is paired with an Account SID found elsewhere in the
+	// chunk rather than used as its own basic-auth username.
+	apiKeySidPat = regexp.MustCompile(`\bSK[0-9a-f]{32}\b`)
 )
 
 type serviceResponse struct {
@@ -38,6 +45,15 @@ type service struct {
 	AccountSID   string `json:"account_sid"`   // account sid
 }
 
+// apiKeyResponse is the body of GET .../Accounts/<AccountSid>/Keys/<Sid>.json,
+// fetched once an API Key SID + secret pair has verified against its parent
+// account, so ExtraData can surface the key's own friendly name and whether
+// it has since been revoked.
+type apiKeyResponse struct {
+	FriendlyName string `json:"friendly_name"` // friendly name of the API key
+	DateRevoked  string `json:"date_revoked"`  // empty/null until the key is revoked
+}
+
 // Keywords are used for efficiently pre-filtering chunks.
 // Use identifiers in the secret preferably, or the provider name.
 func (s Scanner) Keywords() []string {
@@ -49,8 +65,9 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 	dataStr := string(data)
 
 	identifierMatches := identifierPat.FindAllString(dataStr, -1)
+	apiKeySidMatches := apiKeySidPat.FindAllString(dataStr, -1)
 
-	if len(identifierMatches) == 0 {
+	if len(identifierMatches) == 0 && len(apiKeySidMatches) == 0 {
 		return
 	}
 
@@ -114,6 +131,85 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 		}
 	}
 
+	for _, apiKeySid := range apiKeySidMatches {
+		for _, secret := range keyMatches {
+			// Unlike the Account SID + Auth Token pair above, an API Key SID
+			// authenticates scoped to a parent account: there's no Accounts
+			// endpoint to hit without knowing which account it belongs to, so
+			// a match here needs an Account SID found alongside it to verify at all.
+			for _, accountSid := range sidMatches {
+				s1 := detectors.Result{
+					DetectorType: detectorspb.DetectorType_Twilio,
+					Raw:          []byte(apiKeySid),
+					RawV2:        []byte(apiKeySid + secret + accountSid),
+					Redacted:     apiKeySid,
+				}
+
+				s1.ExtraData = map[string]string{
+					"rotation_guide":  "https://howtorotate.com/docs/tutorials/twilio/",
+					"credential_type": "api_key",
+					"account_sid":     accountSid,
+				}
+
+				if verify {
+					client := s.client
+					if client == nil {
+						client = defaultClient
+					}
+
+					req, err := http.NewRequestWithContext(
+						ctx, "GET", fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s.json", accountSid), nil)
+					if err != nil {
+						continue
+					}
+					req.Header.Add("Accept", "*/*")
+					req.SetBasicAuth(apiKeySid, secret)
+					res, err := client.Do(req)
+					if err == nil {
+						defer res.Body.Close()
+
+						if res.StatusCode >= 200 && res.StatusCode < 300 {
+							s1.Verified = true
+							s1.AnalysisInfo = map[string]string{"key": secret, "sid": apiKeySid}
+
+							// The key itself verified; also fetch its own
+							// resource so ExtraData can surface its friendly
+							// name and revocation state. Best-effort: a
+							// failure here doesn't change the verification
+							// result, there's just less to report.
+							keyReq, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf(
+								"https://api.twilio.com/2010-04-01/Accounts/%s/Keys/%s.json", accountSid, apiKeySid), nil)
+							if err == nil {
+								keyReq.Header.Add("Accept", "*/*")
+								keyReq.SetBasicAuth(apiKeySid, secret)
+								if keyRes, err := client.Do(keyReq); err == nil {
+									defer keyRes.Body.Close()
+
+									if keyRes.StatusCode >= 200 && keyRes.StatusCode < 300 {
+										var keyInfo apiKeyResponse
+										if err := json.NewDecoder(keyRes.Body).Decode(&keyInfo); err == nil {
+											s1.ExtraData["friendly_name"] = keyInfo.FriendlyName
+											s1.ExtraData["revoked"] = strconv.FormatBool(keyInfo.DateRevoked != "")
+										}
+									}
+								}
+							}
+						} else if res.StatusCode == 401 || res.StatusCode == 403 {
+							// The secret is determinately not verified (nothing to do)
+						} else {
+							err = fmt.Errorf("unexpected HTTP response status %d", res.StatusCode)
+							s1.SetVerificationError(err, secret)
+						}
+					} else {
+						s1.SetVerificationError(err, secret)
+					}
+				}
+
+				results = append(results, s1)
+			}
+		}
+	}
+
 	return results, nil
 }
 
@@ -24,6 +24,10 @@ var _ detectors.Detector = (*Scanner)(nil)
 var (
 	client = common.SaneHttpClient()
 
+	// apiBaseURL is overridden in tests to point verification at a local
+	// stand-in instead of the real ZipAPI service.
+	apiBaseURL = "https://service.zipapi.us"
+
 	// Make sure that your group is surrounded in boundary characters such as below to reduce false positives.
 	keyPat   = regexp.MustCompile(detectors.PrefixRegex([]string{"zipapi"}) + `\b([0-9a-z]{32})\b`)
 	emailPat = regexp.MustCompile(common.EmailPattern)
@@ -64,7 +68,7 @@ func (s Scanner) FromData(ctx context.Context, verify bool, data []byte) (result
 				if verify {
 					data := fmt.Sprintf("%s:%s", emailMatch, passMatch)
 					sEnc := b64.StdEncoding.EncodeToString([]byte(data))
-					req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://service.zipapi.us/zipcode/90210/?X-API-KEY=%s", keyMatch), nil)
+					req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/zipcode/90210/?X-API-KEY=%s", apiBaseURL, keyMatch), nil)
 					if err != nil {
 						continue
 					}
@@ -0,0 +1,114 @@
+package zipapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors/detectortest"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+)
+
+const (
+	testKey      = "0123456789abcdef0123456789abcdef"
+	testEmail    = "tester@example.com"
+	testPassword = "zipapiSuperSecret1!"
+)
+
+func testData() []byte {
+	return []byte(fmt.Sprintf(
+		"zipapi key=%s email=%s password=%s", testKey, testEmail, testPassword,
+	))
+}
+
+func TestZipapi_FromChunk(t *testing.T) {
+	srv := detectortest.NewServer()
+	defer srv.Close()
+
+	srv.HandleFunc(http.MethodGet, "/zipcode/90210/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-KEY") == testKey {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	noRouteSrv := detectortest.NewServer() // no routes registered, so every request 404s
+	defer noRouteSrv.Close()
+
+	tests := []struct {
+		name         string
+		client       *http.Client
+		apiBaseURL   string
+		wantVerified bool
+	}{
+		{
+			name:         "found, verified",
+			client:       srv.Client(),
+			apiBaseURL:   srv.URL(),
+			wantVerified: true,
+		},
+		{
+			name:         "found, would be verified if not for timeout",
+			client:       common.SaneHttpClientTimeOut(time.Microsecond),
+			apiBaseURL:   srv.URL(),
+			wantVerified: false,
+		},
+		{
+			name:         "found, unexpected api surface",
+			client:       noRouteSrv.Client(),
+			apiBaseURL:   noRouteSrv.URL(),
+			wantVerified: false,
+		},
+	}
+
+	origClient, origBaseURL := client, apiBaseURL
+	defer func() { client, apiBaseURL = origClient, origBaseURL }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client = tt.client
+			apiBaseURL = tt.apiBaseURL
+
+			s := Scanner{}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			got, err := s.FromData(ctx, true, testData())
+			if err != nil {
+				t.Fatalf("FromData() error = %v", err)
+			}
+			if len(got) == 0 {
+				t.Fatalf("expected at least one result, got none")
+			}
+			for _, r := range got {
+				if r.DetectorType != detectorspb.DetectorType_ZipAPI {
+					t.Errorf("DetectorType = %v, want %v", r.DetectorType, detectorspb.DetectorType_ZipAPI)
+				}
+				if r.Verified != tt.wantVerified {
+					t.Errorf("Verified = %v, want %v", r.Verified, tt.wantVerified)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFromData(benchmark *testing.B) {
+	ctx := context.Background()
+	s := Scanner{}
+	for name, data := range detectors.MustGetBenchmarkData() {
+		benchmark.Run(name, func(b *testing.B) {
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				_, err := s.FromData(ctx, false, data)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
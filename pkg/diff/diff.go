@@ -0,0 +1,128 @@
+// Package diff parses unified diffs (as produced by `git diff`, a
+// GitHub/GitLab pull request diff, or a .patch file) into per-hunk pieces
+// so a diff-only scan can check just the changed lines instead of whole
+// file blobs.
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hunkHeaderPat = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// Hunk is one "@@ ... @@" block of a unified diff, scoped to a single file.
+type Hunk struct {
+	// Path is the post-image (new) file path the hunk applies to.
+	Path string
+	// OldPath is the pre-image (old) file path, equal to Path except
+	// across a rename.
+	OldPath string
+	// NewStartLine is the 1-based line number the hunk's first line
+	// corresponds to in the post-image file.
+	NewStartLine int
+	// OldStartLine is the 1-based line number the hunk's first line
+	// corresponds to in the pre-image file.
+	OldStartLine int
+	// Lines are the hunk's added ("+") and context (" ") lines, in order,
+	// with their leading marker stripped. Removed ("-") lines are omitted:
+	// diff mode only scans what the post-image actually contains.
+	Lines []string
+}
+
+// Text joins h's lines back into the blob a detector would scan, so a
+// finding's offset within it can be added to NewStartLine-1 to get its
+// real line number in the post-image file.
+func (h Hunk) Text() string {
+	return strings.Join(h.Lines, "\n")
+}
+
+// Parser parses a unified diff stream into per-file Hunks.
+type Parser struct{}
+
+// NewParser returns a Parser.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse reads a full unified diff from r and returns every hunk it
+// contains, in the order they appear.
+func (p *Parser) Parse(r io.Reader) ([]Hunk, error) {
+	var hunks []Hunk
+	var path, oldPath string
+	var current *Hunk
+
+	flush := func() {
+		if current != nil {
+			hunks = append(hunks, *current)
+			current = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			path, oldPath = "", ""
+
+		case strings.HasPrefix(line, "--- "):
+			oldPath = diffPath(line, "--- ")
+
+		case strings.HasPrefix(line, "+++ "):
+			path = diffPath(line, "+++ ")
+
+		case hunkHeaderPat.MatchString(line):
+			flush()
+			m := hunkHeaderPat.FindStringSubmatch(line)
+			oldStart, _ := strconv.Atoi(m[1])
+			newStart, _ := strconv.Atoi(m[2])
+			current = &Hunk{Path: path, OldPath: oldPath, NewStartLine: newStart, OldStartLine: oldStart}
+
+		case current != nil && strings.HasPrefix(line, "+"):
+			current.Lines = append(current.Lines, strings.TrimPrefix(line, "+"))
+
+		case current != nil && strings.HasPrefix(line, "-"):
+			// Removed line; doesn't appear in the post-image.
+
+		case current != nil && strings.HasPrefix(line, " "):
+			current.Lines = append(current.Lines, strings.TrimPrefix(line, " "))
+
+		case current != nil && line == "":
+			// Git emits a bare blank line (no leading space) for a blank
+			// context line.
+			current.Lines = append(current.Lines, "")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning diff: %w", err)
+	}
+	flush()
+
+	return hunks, nil
+}
+
+// diffPath extracts the file path from a "--- "/"+++ " diff header line,
+// stripping the conventional "a/"/"b/" prefix and reporting "" for
+// /dev/null (a file being added or removed).
+func diffPath(line, marker string) string {
+	rest := strings.TrimPrefix(line, marker)
+	if idx := strings.IndexByte(rest, '\t'); idx != -1 {
+		rest = rest[:idx]
+	}
+	if rest == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(rest, "a/") || strings.HasPrefix(rest, "b/") {
+		rest = rest[2:]
+	}
+	return rest
+}
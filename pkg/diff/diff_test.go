@@ -0,0 +1,97 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/config.yaml b/config.yaml
+index 1111111..2222222 100644
+--- a/config.yaml
++++ b/config.yaml
+@@ -10,3 +10,4 @@ foo: bar
+ unchanged line
+-old secret line
++new secret line
++another added line
+`
+
+func TestParserHunkPositions(t *testing.T) {
+	hunks, err := NewParser().Parse(strings.NewReader(sampleDiff))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(hunks))
+	}
+
+	h := hunks[0]
+	if h.Path != "config.yaml" {
+		t.Errorf("Path = %q, want %q", h.Path, "config.yaml")
+	}
+	if h.NewStartLine != 10 {
+		t.Errorf("NewStartLine = %d, want 10", h.NewStartLine)
+	}
+	if h.OldStartLine != 10 {
+		t.Errorf("OldStartLine = %d, want 10", h.OldStartLine)
+	}
+
+	wantLines := []string{"unchanged line", "new secret line", "another added line"}
+	if len(h.Lines) != len(wantLines) {
+		t.Fatalf("Lines = %v, want %v", h.Lines, wantLines)
+	}
+	for i, want := range wantLines {
+		if h.Lines[i] != want {
+			t.Errorf("Lines[%d] = %q, want %q", i, h.Lines[i], want)
+		}
+	}
+}
+
+func TestParserMultipleFiles(t *testing.T) {
+	twoFileDiff := sampleDiff + `diff --git a/other.txt b/other.txt
+index 3333333..4444444 100644
+--- a/other.txt
++++ b/other.txt
+@@ -1,1 +1,1 @@
+-old
++new
+`
+	hunks, err := NewParser().Parse(strings.NewReader(twoFileDiff))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(hunks))
+	}
+	if hunks[1].Path != "other.txt" {
+		t.Errorf("hunks[1].Path = %q, want %q", hunks[1].Path, "other.txt")
+	}
+	if hunks[1].NewStartLine != 1 {
+		t.Errorf("hunks[1].NewStartLine = %d, want 1", hunks[1].NewStartLine)
+	}
+}
+
+func TestParserAddedFile(t *testing.T) {
+	addedFileDiff := `diff --git a/new.txt b/new.txt
+new file mode 100644
+index 0000000..1111111
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++line one
++line two
+`
+	hunks, err := NewParser().Parse(strings.NewReader(addedFileDiff))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(hunks))
+	}
+	if hunks[0].Path != "new.txt" {
+		t.Errorf("Path = %q, want %q", hunks[0].Path, "new.txt")
+	}
+	if hunks[0].Text() != "line one\nline two" {
+		t.Errorf("Text() = %q", hunks[0].Text())
+	}
+}
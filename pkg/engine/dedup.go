@@ -0,0 +1,330 @@
+package engine
+
+import (
+	"container/list"
+	"math/bits"
+	"sync"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/engine/ahocorasick"
+)
+
+// defaultDedupCacheSize bounds the number of distinct (secret, detector)
+// pairs tracked for cross-chunk deduplication when Config.DedupCacheSize is
+// left unset.
+const defaultDedupCacheSize = 100_000
+
+// dedupLengthWindow is the maximum character-length difference
+// likelyDuplicate treats as "close enough" to compare two secrets for
+// similarity, so the check only has to scan a narrow band of candidates
+// instead of every tracked key.
+const dedupLengthWindow = 10
+
+// chunkSecretKey identifies a decoded secret value scoped to the detector
+// that found it, so the same literal string found by two different
+// detectors is tracked (and deduplicated) independently.
+type chunkSecretKey struct {
+	secret      string
+	detectorKey ahocorasick.DetectorKey
+}
+
+// dedupCache is a bounded LRU of chunkSecretKeys seen so far on a scan,
+// used by likelyDuplicate to recognize the same secret reappearing across
+// chunks without retaining every secret ever seen. A secondary index by
+// secret length lets likelyDuplicate's similarity check consider only
+// candidates within dedupLengthWindow characters of the value being
+// checked.
+type dedupCache struct {
+	mu sync.Mutex
+
+	capacity   int
+	ll         *list.List // front = most recently used
+	elems      map[chunkSecretKey]*list.Element
+	byLength   map[int]map[chunkSecretKey]struct{}
+	signatures map[chunkSecretKey]uint64
+
+	hits      uint64
+	evictions uint64
+}
+
+// newDedupCache returns a dedupCache holding at most capacity keys,
+// evicting the least-recently-used entry once full. capacity <= 0 falls
+// back to defaultDedupCacheSize.
+func newDedupCache(capacity int) *dedupCache {
+	if capacity <= 0 {
+		capacity = defaultDedupCacheSize
+	}
+	return &dedupCache{
+		capacity:   capacity,
+		ll:         list.New(),
+		elems:      make(map[chunkSecretKey]*list.Element),
+		byLength:   make(map[int]map[chunkSecretKey]struct{}),
+		signatures: make(map[chunkSecretKey]uint64),
+	}
+}
+
+// touch records key as seen, moving it to the front of the LRU (or
+// inserting it if new), evicting the least-recently-used entry if the
+// cache is now over capacity.
+func (c *dedupCache) touch(key chunkSecretKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.elems[key]; ok {
+		c.hits++
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	elem := c.ll.PushFront(key)
+	c.elems[key] = elem
+	c.indexByLength(key)
+	c.signatures[key] = ngramSignature(key.secret)
+
+	if c.ll.Len() > c.capacity {
+		c.evictOldest()
+	}
+}
+
+// candidates returns every tracked key whose secret length is within
+// dedupLengthWindow characters of length.
+func (c *dedupCache) candidates(length int) []chunkSecretKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []chunkSecretKey
+	for l := length - dedupLengthWindow; l <= length+dedupLengthWindow; l++ {
+		for key := range c.byLength[l] {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// signatureFor returns the cached 4-gram signature of key's secret,
+// computed once when key was first touched.
+func (c *dedupCache) signatureFor(key chunkSecretKey) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.signatures[key]
+}
+
+// metrics returns the cache's cumulative hit and eviction counts.
+func (c *dedupCache) metrics() (hits, evictions uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.evictions
+}
+
+func (c *dedupCache) indexByLength(key chunkSecretKey) {
+	length := len(key.secret)
+	if c.byLength[length] == nil {
+		c.byLength[length] = make(map[chunkSecretKey]struct{})
+	}
+	c.byLength[length][key] = struct{}{}
+}
+
+func (c *dedupCache) evictOldest() {
+	oldest := c.ll.Back()
+	if oldest == nil {
+		return
+	}
+
+	key := oldest.Value.(chunkSecretKey)
+	c.ll.Remove(oldest)
+	delete(c.elems, key)
+	delete(c.signatures, key)
+
+	if set, ok := c.byLength[len(key.secret)]; ok {
+		delete(set, key)
+		if len(set) == 0 {
+			delete(c.byLength, len(key.secret))
+		}
+	}
+
+	c.evictions++
+}
+
+// minSignatureJaccard is the minimum estimated 4-gram Jaccard overlap (see
+// ngramSignature) two secrets must have before likelyDuplicate bothers
+// running the bounded Levenshtein comparison on them. It's deliberately
+// loose: the signature is a cheap, lossy estimate, and the DP below is the
+// real check.
+const minSignatureJaccard = 0.25
+
+// likelyDuplicate reports whether val's secret is a likely duplicate of
+// anything already tracked in dupes: an exact match regardless of detector,
+// or a near match against a *different* detector than the one that found
+// val. Two different detectors often match overlapping substrings of the
+// same underlying credential (e.g. a generic high-entropy detector alongside
+// a provider-specific one), which the near-match check is meant to catch;
+// the same detector matching the same value twice is not a dedup signal, so
+// same-detector candidates are skipped.
+//
+// "Near" is decided by a bounded Levenshtein distance (see
+// boundedLevenshtein), cheaply pre-filtered by comparing each candidate's
+// ngramSignature against val's so the DP only runs on candidates that are
+// plausibly similar in content, not just in length.
+func likelyDuplicate(ctx context.Context, val chunkSecretKey, dupes *dedupCache) bool {
+	maxDist := similarityMaxDist(len(val.secret))
+	valSig := ngramSignature(val.secret)
+
+	for _, other := range dupes.candidates(len(val.secret)) {
+		if val.secret == other.secret {
+			return true
+		}
+		if other.detectorKey == val.detectorKey {
+			continue
+		}
+		if !signaturesSimilar(valSig, dupes.signatureFor(other)) {
+			continue
+		}
+		if _, ok := boundedLevenshtein(val.secret, other.secret, maxDist); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// similarityMaxDist scales the edit-distance threshold likelyDuplicate
+// tolerates with the secret's length, so a one-character typo in a 128-byte
+// token isn't held to the same absolute threshold as one in an 8-byte token.
+func similarityMaxDist(length int) int {
+	maxDist := length / 8
+	if maxDist < 2 {
+		maxDist = 2
+	}
+	return maxDist
+}
+
+// ngramSignature computes a cheap 64-bit Bloom-style signature of s's
+// overlapping 4-grams: each 4-gram is hashed into one of 64 bits, which are
+// OR'd together. Comparing two signatures' popcount overlap (signaturesSimilar)
+// approximates their 4-gram Jaccard similarity without ever materializing
+// the n-gram sets, so it's cheap enough to run as a pre-filter before the
+// more expensive boundedLevenshtein comparison.
+func ngramSignature(s string) uint64 {
+	if len(s) < 4 {
+		return 1 << (fnv64a(s) % 64)
+	}
+
+	var sig uint64
+	for i := 0; i+4 <= len(s); i++ {
+		sig |= 1 << (fnv64a(s[i:i+4]) % 64)
+	}
+	return sig
+}
+
+// signaturesSimilar reports whether two ngramSignatures overlap enough to be
+// worth a full boundedLevenshtein comparison.
+func signaturesSimilar(a, b uint64) bool {
+	union := bits.OnesCount64(a | b)
+	if union == 0 {
+		return true
+	}
+	intersection := bits.OnesCount64(a & b)
+	return float64(intersection)/float64(union) >= minSignatureJaccard
+}
+
+// fnv64a is the FNV-1a hash, used by ngramSignature to map a 4-gram onto a
+// bit position.
+func fnv64a(s string) uint64 {
+	const offset64 = 14695981039346656037
+	const prime64 = 1099511628211
+
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
+
+// boundedLevenshtein computes the Levenshtein edit distance between a and b,
+// restricted to a band of width 2*maxDist+1 around the main diagonal, and
+// reports (distance, true) if that distance is within maxDist. It returns
+// (0, false) as soon as it can prove the true distance exceeds maxDist,
+// either because the length difference alone already does, or because every
+// entry in the DP's current row does partway through — at that point every
+// cell in a later row can only be larger, so continuing can't help.
+//
+// This keeps the cost close to O(min(len(a),len(b)) * maxDist) instead of
+// the O(len(a)*len(b)) of the unbounded DP, which matters since
+// likelyDuplicate may run this against many candidates per chunk.
+func boundedLevenshtein(a, b string, maxDist int) (int, bool) {
+	if d := len(a) - len(b); d > maxDist || -d > maxDist {
+		return 0, false
+	}
+	if a == b {
+		return 0, true
+	}
+
+	// Keep a the shorter string so the band's width is bounded by len(a),
+	// not max(len(a), len(b)).
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	const outOfBand = 1<<31 - 1 // effectively "infinite" within an int row
+	width := 2*maxDist + 1
+
+	prev := make([]int, width)
+	curr := make([]int, width)
+	for k := 0; k < width; k++ {
+		j := k - maxDist
+		if j < 0 {
+			prev[k] = outOfBand
+			continue
+		}
+		prev[k] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		rowMin := outOfBand
+		for k := 0; k < width; k++ {
+			j := i + (k - maxDist)
+			switch {
+			case j < 0 || j > len(b):
+				curr[k] = outOfBand
+			case j == 0:
+				curr[k] = i
+			default:
+				cost := 1
+				if a[i-1] == b[j-1] {
+					cost = 0
+				}
+
+				del, ins := outOfBand, outOfBand
+				if k > 0 {
+					del = curr[k-1] + 1
+				}
+				if k < width-1 {
+					ins = prev[k+1] + 1
+				}
+				sub := prev[k] + cost
+
+				best := sub
+				if del < best {
+					best = del
+				}
+				if ins < best {
+					best = ins
+				}
+				curr[k] = best
+			}
+			if curr[k] < rowMin {
+				rowMin = curr[k]
+			}
+		}
+		if rowMin > maxDist {
+			return 0, false
+		}
+		prev, curr = curr, prev
+	}
+
+	k := len(b) - len(a) + maxDist
+	if k < 0 || k >= width || prev[k] > maxDist {
+		return 0, false
+	}
+	return prev[k], true
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/docker"
+)
+
+// ScanDocker scans one or more Docker images with the provided options.
+func (e *Engine) ScanDocker(ctx context.Context, c sources.DockerConfig) error {
+	connection := sourcespb.Docker{
+		Images:       c.Images,
+		Platforms:    c.Platforms,
+		AllPlatforms: c.AllPlatforms,
+	}
+
+	if c.BearerToken != "" {
+		connection.Credential = &sourcespb.Docker_BearerToken{BearerToken: c.BearerToken}
+	} else if c.UseDockerKeychain {
+		connection.Credential = &sourcespb.Docker_DockerKeychain{DockerKeychain: true}
+	} else {
+		connection.Credential = &sourcespb.Docker_Unauthenticated{}
+	}
+
+	var conn anypb.Any
+	if err := anypb.MarshalFrom(&conn, &connection, proto.MarshalOptions{}); err != nil {
+		ctx.Logger().Error(err, "failed to marshal docker connection")
+		return err
+	}
+
+	sourceName := "trufflehog - docker"
+	sourceID, jobID, _ := e.sourceManager.GetIDs(ctx, sourceName, docker.SourceType)
+
+	dockerSource := &docker.Source{}
+	if err := dockerSource.Init(ctx, sourceName, jobID, sourceID, true, &conn, 1); err != nil {
+		return err
+	}
+
+	_, err := e.sourceManager.Run(ctx, sourceName, dockerSource)
+	return err
+}
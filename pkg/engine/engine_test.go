@@ -738,7 +738,13 @@ func TestLikelyDuplicate(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.Background()
-			result := likelyDuplicate(ctx, tc.val, tc.dupes)
+
+			dupes := newDedupCache(defaultDedupCacheSize)
+			for key := range tc.dupes {
+				dupes.touch(key)
+			}
+
+			result := likelyDuplicate(ctx, tc.val, dupes)
 			if result != tc.expected {
 				t.Errorf("expected %v, got %v", tc.expected, result)
 			}
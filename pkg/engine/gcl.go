@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/gcl"
+)
+
+// ScanGCL scans Google Cloud Logging with the provided options.
+func (e *Engine) ScanGCL(ctx context.Context, c sources.GCLConfig) error {
+	connection := sourcespb.GCL{
+		ProjectId:   c.ProjectID,
+		Filter:      c.Filter,
+		ApiKey:      c.ApiKey,
+		AccessToken: c.AccessToken,
+	}
+
+	var conn anypb.Any
+	if err := anypb.MarshalFrom(&conn, &connection, proto.MarshalOptions{}); err != nil {
+		ctx.Logger().Error(err, "failed to marshal GCL connection")
+		return err
+	}
+
+	sourceName := "trufflehog - gcl"
+	sourceID, jobID, _ := e.sourceManager.GetIDs(ctx, sourceName, gcl.SourceType)
+
+	gclSource := &gcl.Source{}
+	if err := gclSource.Init(ctx, sourceName, jobID, sourceID, true, &conn, 1); err != nil {
+		return err
+	}
+
+	_, err := e.sourceManager.Run(ctx, sourceName, gclSource)
+	return err
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	gogit "github.com/go-git/go-git/v5"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/git"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/gitlab"
+)
+
+// ScanGitLab scans GitLab with the provided options.
+func (e *Engine) ScanGitLab(ctx context.Context, c sources.GitlabConfig) error {
+	scanCtx, span := otel.Tracer("scanner").Start(ctx, "ScanGitLab")
+	defer span.End()
+
+	ctx = context.AddLogger(scanCtx)
+
+	connection := sourcespb.GitLab{
+		Endpoint:     c.Endpoint,
+		Repositories: c.Repos,
+		Depth:        int64(c.Depth),
+		SingleBranch: c.SingleBranch,
+	}
+	if !c.Since.IsZero() {
+		connection.Since = timestamppb.New(c.Since)
+	}
+	if len(c.Token) > 0 {
+		connection.Credential = &sourcespb.GitLab_Token{
+			Token: c.Token,
+		}
+	} else {
+		connection.Credential = &sourcespb.GitLab_Unauthenticated{}
+	}
+
+	var conn anypb.Any
+	err := anypb.MarshalFrom(&conn, &connection, proto.MarshalOptions{})
+	if err != nil {
+		ctx.Logger().Error(err, "failed to marshal gitlab connection")
+		return err
+	}
+
+	logOptions := &gogit.LogOptions{}
+	opts := []git.ScanOption{
+		git.ScanOptionFilter(c.Filter),
+		git.ScanOptionLogOptions(logOptions),
+	}
+	if !c.Since.IsZero() {
+		opts = append(opts, git.ScanOptionSince(c.Since))
+	}
+	scanOptions := git.NewScanOptions(opts...)
+
+	sourceName := "trufflehog - gitlab"
+	sourceID, jobID, _ := e.sourceManager.GetIDs(ctx, sourceName, gitlab.SourceType)
+
+	gitlabSource := &gitlab.Source{}
+	if err := gitlabSource.Init(ctx, sourceName, jobID, sourceID, true, &conn, c.Concurrency); err != nil {
+		return err
+	}
+	gitlabSource.WithScanOptions(scanOptions)
+
+	ctxRun, spanRun := otel.Tracer("scanner").Start(ctx, "Run")
+	defer spanRun.End()
+
+	_, err = e.sourceManager.Run(context.AddLogger(ctxRun), sourceName, gitlabSource)
+	return err
+}
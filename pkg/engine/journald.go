@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/journald"
+)
+
+// ScanJournald scans the local systemd journal with the provided options.
+func (e *Engine) ScanJournald(ctx context.Context, c sources.JournaldConfig) error {
+	connection := sourcespb.Journald{
+		Unit:   c.Unit,
+		Since:  c.Since,
+		Follow: c.Follow,
+	}
+
+	var conn anypb.Any
+	if err := anypb.MarshalFrom(&conn, &connection, proto.MarshalOptions{}); err != nil {
+		ctx.Logger().Error(err, "failed to marshal journald connection")
+		return err
+	}
+
+	sourceName := "trufflehog - journald"
+	sourceID, jobID, _ := e.sourceManager.GetIDs(ctx, sourceName, journald.SourceType)
+
+	journaldSource := &journald.Source{}
+	if err := journaldSource.Init(ctx, sourceName, jobID, sourceID, true, &conn, 1); err != nil {
+		return err
+	}
+
+	_, err := e.sourceManager.Run(ctx, sourceName, journaldSource)
+	return err
+}
@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/detectors"
+)
+
+// scanMetrics holds the Prometheus collectors exposed by StartMetricsServer,
+// so operators can observe a long-running scan (GitHub orgs, S3, GCS,
+// filesystem, ...) while it executes instead of only seeing the final
+// "finished scanning" summary.
+type scanMetrics struct {
+	chunksScanned     *prometheus.CounterVec
+	bytesScanned      *prometheus.CounterVec
+	verifiedSecrets   *prometheus.CounterVec
+	unverifiedSecrets *prometheus.CounterVec
+	detectorLatency   *prometheus.HistogramVec
+	scanInfo          *prometheus.GaugeVec
+}
+
+var sourceLabels = []string{"source_type", "source_name"}
+
+var sharedScanMetrics = &scanMetrics{
+	chunksScanned: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "scan",
+		Name:      "chunks_scanned_total",
+		Help:      "Total number of chunks scanned, labeled by source.",
+	}, sourceLabels),
+	bytesScanned: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "scan",
+		Name:      "bytes_scanned_total",
+		Help:      "Total number of chunk bytes scanned, labeled by source.",
+	}, sourceLabels),
+	verifiedSecrets: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "scan",
+		Name:      "verified_secrets_found_total",
+		Help:      "Total number of verified secrets found, labeled by source and detector.",
+	}, append(append([]string{}, sourceLabels...), "detector")),
+	unverifiedSecrets: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "scan",
+		Name:      "unverified_secrets_found_total",
+		Help:      "Total number of unverified secrets found, labeled by source and detector.",
+	}, append(append([]string{}, sourceLabels...), "detector")),
+	detectorLatency: promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "trufflehog",
+		Subsystem: "scan",
+		Name:      "detector_latency_seconds",
+		Help:      "Cumulative time spent in each detector's FromData calls over the scan, as reported by Engine.GetDetectorsMetrics.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"detector"}),
+	scanInfo: promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "trufflehog",
+		Name:      "scan_info",
+		Help:      "Info metric carrying the running trufflehog build version. Value is always 1.",
+	}, []string{"version"}),
+}
+
+// MetricsPrinter wraps another Printer, recording Prometheus metrics for each
+// result before delegating to it, so a scan can expose live progress via
+// StartMetricsServer without sprinkling Prometheus calls through the
+// detectors themselves.
+type MetricsPrinter struct {
+	next Printer
+}
+
+// NewMetricsPrinter returns a Printer that records metrics for each result
+// and then prints it using next.
+func NewMetricsPrinter(next Printer) *MetricsPrinter {
+	return &MetricsPrinter{next: next}
+}
+
+// Print records result's metrics and forwards it to the wrapped Printer.
+func (m *MetricsPrinter) Print(ctx context.Context, r *detectors.ResultWithMetadata) error {
+	sourceType, sourceName := r.SourceType.String(), r.SourceName
+	detector := r.DetectorType.String()
+
+	sharedScanMetrics.chunksScanned.WithLabelValues(sourceType, sourceName).Inc()
+	sharedScanMetrics.bytesScanned.WithLabelValues(sourceType, sourceName).Add(float64(len(r.Raw)))
+
+	if r.Verified {
+		sharedScanMetrics.verifiedSecrets.WithLabelValues(sourceType, sourceName, detector).Inc()
+	} else {
+		sharedScanMetrics.unverifiedSecrets.WithLabelValues(sourceType, sourceName, detector).Inc()
+	}
+
+	return m.next.Print(ctx, r)
+}
+
+// RecordDetectorLatencies observes each detector's cumulative scan duration,
+// as reported by Engine.GetDetectorsMetrics.
+func RecordDetectorLatencies(latencies map[string]time.Duration) {
+	for detector, d := range latencies {
+		sharedScanMetrics.detectorLatency.WithLabelValues(detector).Observe(d.Seconds())
+	}
+}
+
+// SetBuildVersion sets the trufflehog_scan_info info metric to version.
+func SetBuildVersion(version string) {
+	sharedScanMetrics.scanInfo.Reset()
+	sharedScanMetrics.scanInfo.WithLabelValues(version).Set(1)
+}
+
+// StartMetricsServer starts a Prometheus HTTP exporter on addr, serving the
+// collectors registered by this package at /metrics. It returns immediately;
+// the caller is responsible for calling Shutdown on the returned server once
+// the scan finishes.
+func StartMetricsServer(ctx context.Context, addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			ctx.Logger().Error(err, "metrics server failed")
+		}
+	}()
+
+	return server
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/config"
+)
+
+// VerificationMetrics counts verification calls the policy layer skipped,
+// blocked, or throttled, so a run can report how much of its per-detector
+// verification policy actually engaged.
+type VerificationMetrics struct {
+	// Skipped counts findings whose detector had a VerifyForceOff decision.
+	Skipped uint64
+	// Blocked counts verification requests rejected because their
+	// destination host wasn't permitted by the detector's policy.
+	Blocked uint64
+	// Throttled counts verification requests that had to wait for a
+	// per-detector rate limit token or concurrency slot to free up.
+	Throttled uint64
+}
+
+// ShouldVerify resolves id's tri-state VerificationPolicy decision against
+// globalVerify, the run's overall verify flag, recording a skip in metrics
+// when the policy forces verification off.
+func ShouldVerify(id config.DetectorID, policies config.VerificationPolicies, globalVerify bool, metrics *VerificationMetrics) bool {
+	switch policies.Decision(id) {
+	case config.VerifyForceOn:
+		return true
+	case config.VerifyForceOff:
+		if metrics != nil {
+			atomic.AddUint64(&metrics.Skipped, 1)
+		}
+		return false
+	default:
+		return globalVerify
+	}
+}
+
+// policyState is the per-detector rate limiter and concurrency cap a
+// PolicyTransport enforces, created lazily the first time that detector
+// issues a verification request.
+type policyState struct {
+	limiter  *rate.Limiter
+	inFlight chan struct{}
+}
+
+// PolicyTransport wraps an http.RoundTripper with a detector's
+// VerificationPolicy: a request to a host the policy doesn't permit is
+// rejected before ever reaching the underlying transport, and requests are
+// rate-limited and concurrency-capped per the policy's QPS/Burst/Concurrency.
+// Detectors that verify over HTTP should use a client built with one of
+// these in place of http.DefaultTransport.
+type PolicyTransport struct {
+	base     http.RoundTripper
+	detector config.DetectorID
+	policies config.VerificationPolicies
+	metrics  *VerificationMetrics
+
+	mu    sync.Mutex
+	state *policyState
+}
+
+// NewPolicyTransport returns a PolicyTransport enforcing policies for
+// detector, wrapping base (http.DefaultTransport if nil). metrics, if
+// non-nil, is incremented as requests are blocked or throttled; pass the
+// same *VerificationMetrics to every detector's PolicyTransport in a run to
+// get run-wide totals.
+func NewPolicyTransport(base http.RoundTripper, detector config.DetectorID, policies config.VerificationPolicies, metrics *VerificationMetrics) *PolicyTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &PolicyTransport{base: base, detector: detector, policies: policies, metrics: metrics}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *PolicyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if !t.policies.AllowsHost(t.detector, host) {
+		t.count(&t.metrics.Blocked)
+		return nil, fmt.Errorf("verification policy: host %q is not permitted for detector %v", host, t.detector)
+	}
+
+	state := t.stateForDetector()
+	if state == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	if state.limiter != nil {
+		if state.limiter.Tokens() < 1 {
+			t.count(&t.metrics.Throttled)
+		}
+		if err := state.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	if state.inFlight != nil {
+		select {
+		case state.inFlight <- struct{}{}:
+		default:
+			t.count(&t.metrics.Throttled)
+			select {
+			case state.inFlight <- struct{}{}:
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		}
+		defer func() { <-state.inFlight }()
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// stateForDetector returns this transport's policyState, creating it from the
+// detector's policy on first use. It returns nil if the detector has no
+// policy or the policy sets neither a QPS nor a concurrency limit, so
+// RoundTrip can skip the rate-limit/concurrency bookkeeping entirely.
+func (t *PolicyTransport) stateForDetector() *policyState {
+	policy, ok := t.policies[t.detector]
+	if !ok || (policy.QPS == 0 && policy.Concurrency == 0) {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state != nil {
+		return t.state
+	}
+
+	s := &policyState{}
+	if policy.QPS > 0 {
+		burst := policy.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		s.limiter = rate.NewLimiter(rate.Limit(policy.QPS), burst)
+	}
+	if policy.Concurrency > 0 {
+		s.inFlight = make(chan struct{}, policy.Concurrency)
+	}
+	t.state = s
+	return s
+}
+
+func (t *PolicyTransport) count(counter *uint64) {
+	if t.metrics != nil {
+		atomic.AddUint64(counter, 1)
+	}
+}
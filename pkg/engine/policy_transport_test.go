@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/config"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/detectorspb"
+)
+
+func TestShouldVerify(t *testing.T) {
+	awsID := config.DetectorID{ID: detectorspb.DetectorType_AWS}
+
+	tests := map[string]struct {
+		policies     config.VerificationPolicies
+		globalVerify bool
+		wantVerify   bool
+		wantSkipped  uint64
+	}{
+		"no policy inherits global verify (on)": {
+			policies:     config.VerificationPolicies{},
+			globalVerify: true,
+			wantVerify:   true,
+		},
+		"no policy inherits global verify (off)": {
+			policies:     config.VerificationPolicies{},
+			globalVerify: false,
+			wantVerify:   false,
+		},
+		"force on overrides global verify off": {
+			policies:     config.VerificationPolicies{awsID: {Decision: config.VerifyForceOn}},
+			globalVerify: false,
+			wantVerify:   true,
+		},
+		"force off overrides global verify on": {
+			policies:     config.VerificationPolicies{awsID: {Decision: config.VerifyForceOff}},
+			globalVerify: true,
+			wantVerify:   false,
+			wantSkipped:  1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			metrics := &VerificationMetrics{}
+			got := ShouldVerify(awsID, tc.policies, tc.globalVerify, metrics)
+			if got != tc.wantVerify {
+				t.Errorf("ShouldVerify() = %v, want %v", got, tc.wantVerify)
+			}
+			if metrics.Skipped != tc.wantSkipped {
+				t.Errorf("Skipped = %d, want %d", metrics.Skipped, tc.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestPolicyTransportHostScoping(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	awsID := config.DetectorID{ID: detectorspb.DetectorType_AWS}
+	host := srv.Listener.Addr().String()
+
+	tests := map[string]struct {
+		policies    config.VerificationPolicies
+		wantBlocked bool
+	}{
+		"no policy allows any host": {
+			policies: config.VerificationPolicies{},
+		},
+		"denied host is blocked": {
+			policies: config.VerificationPolicies{
+				awsID: {DeniedHosts: []string{host}},
+			},
+			wantBlocked: true,
+		},
+		"allowed host list permits a listed host": {
+			policies: config.VerificationPolicies{
+				awsID: {AllowedHosts: []string{host}},
+			},
+		},
+		"allowed host list blocks an unlisted host": {
+			policies: config.VerificationPolicies{
+				awsID: {AllowedHosts: []string{"definitely-not-" + host}},
+			},
+			wantBlocked: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			metrics := &VerificationMetrics{}
+			transport := NewPolicyTransport(http.DefaultTransport, awsID, tc.policies, metrics)
+			client := &http.Client{Transport: transport}
+
+			_, err := client.Get(srv.URL)
+			blocked := err != nil
+			if blocked != tc.wantBlocked {
+				t.Errorf("request blocked = %v (err=%v), want %v", blocked, err, tc.wantBlocked)
+			}
+			if blocked && metrics.Blocked != 1 {
+				t.Errorf("Blocked = %d, want 1", metrics.Blocked)
+			}
+		})
+	}
+}
+
+func TestPolicyTransportConcurrencyCap(t *testing.T) {
+	var inFlight, maxInFlight int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			prevMax := atomic.LoadInt32(&maxInFlight)
+			if n <= prevMax || atomic.CompareAndSwapInt32(&maxInFlight, prevMax, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	awsID := config.DetectorID{ID: detectorspb.DetectorType_AWS}
+	policies := config.VerificationPolicies{awsID: {Concurrency: 2}}
+	metrics := &VerificationMetrics{}
+	transport := NewPolicyTransport(http.DefaultTransport, awsID, policies, metrics)
+	client := &http.Client{Transport: transport}
+
+	done := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		go func() {
+			client.Get(srv.URL) //nolint:errcheck
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		<-done
+	}
+
+	if maxInFlight > 2 {
+		t.Errorf("observed %d concurrent requests, want at most 2", maxInFlight)
+	}
+	if metrics.Throttled == 0 {
+		t.Error("expected at least one request to have been throttled by the concurrency cap")
+	}
+}
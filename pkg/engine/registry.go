@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/registry"
+)
+
+// ScanRegistry scans every image in a container registry with the provided
+// options, discovering repositories and tags via the registry's catalog API
+// instead of requiring images to be listed up front.
+func (e *Engine) ScanRegistry(ctx context.Context, c sources.RegistryConfig) error {
+	connection := sourcespb.Registry{
+		Url:          c.URL,
+		RepoIncludes: c.RepoIncludes,
+		RepoExcludes: c.RepoExcludes,
+		TagIncludes:  c.TagIncludes,
+		TagExcludes:  c.TagExcludes,
+		Platform:     c.Platform,
+		Concurrency:  int64(c.Concurrency),
+	}
+
+	switch {
+	case c.Token != "":
+		connection.Credential = &sourcespb.Registry_BearerToken{BearerToken: c.Token}
+	case c.Username != "" || c.Password != "":
+		connection.Credential = &sourcespb.Registry_BasicAuth{
+			BasicAuth: &sourcespb.BasicAuth{Username: c.Username, Password: c.Password},
+		}
+	default:
+		connection.Credential = &sourcespb.Registry_Unauthenticated{}
+	}
+
+	var conn anypb.Any
+	if err := anypb.MarshalFrom(&conn, &connection, proto.MarshalOptions{}); err != nil {
+		ctx.Logger().Error(err, "failed to marshal registry connection")
+		return err
+	}
+
+	sourceName := "trufflehog - registry"
+	sourceID, jobID, _ := e.sourceManager.GetIDs(ctx, sourceName, registry.SourceType)
+
+	registrySource := &registry.Source{}
+	if err := registrySource.Init(ctx, sourceName, jobID, sourceID, true, &conn, c.Concurrency); err != nil {
+		return err
+	}
+
+	_, err := e.sourceManager.Run(ctx, sourceName, registrySource)
+	return err
+}
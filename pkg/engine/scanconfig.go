@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/scanconfig"
+)
+
+// sourceRunner scans a single configured source entry against e.
+type sourceRunner func(ctx context.Context, e *Engine, entry scanconfig.SourceEntry) error
+
+// sourceRunners maps a scan config entry's Type to the runner that knows how
+// to scan it. Adding a new source to `trufflehog scan --config` is a matter
+// of registering a constructor here, rather than editing a switch statement
+// like runSingleScan's.
+var sourceRunners = map[string]sourceRunner{
+	"git": func(ctx context.Context, e *Engine, entry scanconfig.SourceEntry) error {
+		if entry.Git == nil {
+			return fmt.Errorf("type git requires a git: block")
+		}
+		return e.ScanGit(ctx, *entry.Git)
+	},
+	"github": func(ctx context.Context, e *Engine, entry scanconfig.SourceEntry) error {
+		if entry.GitHub == nil {
+			return fmt.Errorf("type github requires a github: block")
+		}
+		return e.ScanGitHub(ctx, *entry.GitHub)
+	},
+	"s3": func(ctx context.Context, e *Engine, entry scanconfig.SourceEntry) error {
+		if entry.S3 == nil {
+			return fmt.Errorf("type s3 requires an s3: block")
+		}
+		return e.ScanS3(ctx, *entry.S3)
+	},
+	"filesystem": func(ctx context.Context, e *Engine, entry scanconfig.SourceEntry) error {
+		if entry.Filesystem == nil {
+			return fmt.Errorf("type filesystem requires a filesystem: block")
+		}
+		return e.ScanFileSystem(ctx, *entry.Filesystem)
+	},
+	"docker": func(ctx context.Context, e *Engine, entry scanconfig.SourceEntry) error {
+		if entry.Docker == nil {
+			return fmt.Errorf("type docker requires a docker: block")
+		}
+		return e.ScanDocker(ctx, *entry.Docker)
+	},
+	"gcs": func(ctx context.Context, e *Engine, entry scanconfig.SourceEntry) error {
+		if entry.GCS == nil {
+			return fmt.Errorf("type gcs requires a gcs: block")
+		}
+		return e.ScanGCS(ctx, *entry.GCS)
+	},
+}
+
+// ScanConfig runs every source in doc against e in order, so a single scan
+// invocation can cover many heterogeneous sources sharing one engine — and so
+// one set of metrics and one job report — instead of requiring one
+// invocation per source.
+func (e *Engine) ScanConfig(ctx context.Context, doc *scanconfig.Document) error {
+	for _, entry := range doc.Sources {
+		runner, ok := sourceRunners[entry.Type]
+		if !ok {
+			return fmt.Errorf("source %q: unknown type %q", entry.Name, entry.Type)
+		}
+		if err := runner(ctx, e, entry); err != nil {
+			return fmt.Errorf("source %q: %w", entry.Name, err)
+		}
+	}
+	return nil
+}
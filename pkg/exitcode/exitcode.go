@@ -0,0 +1,80 @@
+// Package exitcode defines trufflehog's process exit codes and the logic
+// for choosing one at the end of a scan. Giving each outcome its own code
+// lets pipelines distinguish "a verified secret was found" from "the scan
+// itself failed" from "one source out of many couldn't be scanned", instead
+// of collapsing everything into a single hard-coded 183.
+package exitcode
+
+// Exit codes returned by the trufflehog CLI.
+const (
+	// OK means the scan completed and found nothing --fail-on was watching for.
+	OK = 0
+	// VerifiedFound means the scan completed and found at least one verified secret.
+	VerifiedFound = 183
+	// UnverifiedFound means the scan completed and found at least one unverified secret, and no verified ones.
+	UnverifiedFound = 184
+	// ConfigError means the command-line flags or config file were invalid.
+	ConfigError = 2
+	// SourceAuthError means a source could not authenticate (e.g. to a GitHub org).
+	SourceAuthError = 3
+	// PartialFailure means the scan completed but one or more source units failed.
+	PartialFailure = 4
+	// Interrupted means the scan was canceled by a signal before it completed.
+	Interrupted = 130
+)
+
+// FailOn selects which scan outcome makes ForScan return a non-zero code.
+type FailOn string
+
+// Values accepted by the --fail-on flag.
+const (
+	FailOnVerified   FailOn = "verified"
+	FailOnUnverified FailOn = "unverified"
+	FailOnAny        FailOn = "any"
+	FailOnErrors     FailOn = "errors"
+)
+
+// Outcome summarizes what a finished scan found, for ForScan to map to an
+// exit code.
+type Outcome struct {
+	// VerifiedFound is true if at least one verified secret was found.
+	VerifiedFound bool
+	// UnverifiedFound is true if at least one unverified secret was found.
+	UnverifiedFound bool
+	// UnitErrors is true if at least one source unit failed during the scan.
+	UnitErrors bool
+}
+
+// ForScan returns the exit code a scan with the given outcome should exit
+// with, given failOn. It returns OK unless failOn's condition was met.
+func ForScan(failOn FailOn, o Outcome) int {
+	switch failOn {
+	case FailOnVerified:
+		if o.VerifiedFound {
+			return VerifiedFound
+		}
+	case FailOnUnverified:
+		if o.VerifiedFound {
+			return VerifiedFound
+		}
+		if o.UnverifiedFound {
+			return UnverifiedFound
+		}
+	case FailOnAny:
+		if o.VerifiedFound {
+			return VerifiedFound
+		}
+		if o.UnverifiedFound {
+			return UnverifiedFound
+		}
+		if o.UnitErrors {
+			return PartialFailure
+		}
+	case FailOnErrors:
+		if o.UnitErrors {
+			return PartialFailure
+		}
+	}
+
+	return OK
+}
@@ -0,0 +1,30 @@
+package exitcode
+
+import "testing"
+
+func TestForScan(t *testing.T) {
+	tests := map[string]struct {
+		failOn FailOn
+		o      Outcome
+		want   int
+	}{
+		"verified: nothing found":               {FailOnVerified, Outcome{}, OK},
+		"verified: verified found":              {FailOnVerified, Outcome{VerifiedFound: true}, VerifiedFound},
+		"verified: unverified found only":       {FailOnVerified, Outcome{UnverifiedFound: true}, OK},
+		"unverified: unverified found":          {FailOnUnverified, Outcome{UnverifiedFound: true}, UnverifiedFound},
+		"unverified: verified takes precedence": {FailOnUnverified, Outcome{VerifiedFound: true, UnverifiedFound: true}, VerifiedFound},
+		"any: unit errors only":                 {FailOnAny, Outcome{UnitErrors: true}, PartialFailure},
+		"any: verified takes precedence":        {FailOnAny, Outcome{VerifiedFound: true, UnitErrors: true}, VerifiedFound},
+		"errors: no errors":                     {FailOnErrors, Outcome{VerifiedFound: true}, OK},
+		"errors: unit errors":                   {FailOnErrors, Outcome{UnitErrors: true}, PartialFailure},
+		"unknown fail-on mode":                  {FailOn("bogus"), Outcome{VerifiedFound: true}, OK},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ForScan(tt.failOn, tt.o); got != tt.want {
+				t.Errorf("ForScan(%q, %+v) = %d, want %d", tt.failOn, tt.o, got, tt.want)
+			}
+		})
+	}
+}
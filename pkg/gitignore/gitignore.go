@@ -0,0 +1,185 @@
+// Package gitignore implements gitignore-style pattern matching for
+// .trufflehogignore files discovered inside a scanned tree.
+package gitignore
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// IgnoreFileName is the name of the per-directory ignore file BuildMatcher
+// looks for while walking a tree.
+const IgnoreFileName = ".trufflehogignore"
+
+// Pattern is a single compiled rule from one ignore file.
+type Pattern struct {
+	// base is the directory (relative to the scan root, "/"-separated, ""
+	// for the root itself) that the ignore file declaring this pattern
+	// lives in. Only paths under base are considered for a match.
+	base string
+	// glob is the doublestar pattern to match against, with any leading
+	// "/" and trailing "/" already stripped.
+	glob string
+	// negate is true for a "!"-prefixed pattern that re-includes a path an
+	// earlier pattern excluded.
+	negate bool
+	// dirOnly is true for a pattern ending in "/", which only matches
+	// directories.
+	dirOnly bool
+	// anchored is true for a pattern containing a "/" other than a
+	// trailing one, meaning it's matched against the full path relative
+	// to base rather than against any path segment.
+	anchored bool
+}
+
+// ParsePatterns parses the lines of one ignore file rooted at base (a
+// "/"-separated path relative to the scan root) into a list of Patterns,
+// preserving file order so later patterns can override earlier ones during
+// matching. Blank lines and "#"-prefixed comments are skipped.
+func ParsePatterns(base string, lines []string) []Pattern {
+	patterns := make([]Pattern, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := Pattern{base: base}
+
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		trimmed := strings.TrimPrefix(line, "/")
+		if strings.Contains(trimmed, "/") || trimmed != line {
+			p.anchored = true
+		}
+		p.glob = trimmed
+
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// matches reports whether path (relative to the scan root, "/"-separated)
+// matches p. isDir indicates whether path itself is a directory.
+func (p Pattern) matches(path string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+
+	rel, ok := relativeTo(p.base, path)
+	if !ok {
+		return false
+	}
+
+	if p.anchored {
+		ok, _ := doublestar.Match(p.glob, rel)
+		return ok
+	}
+
+	// An unanchored pattern matches a path component at any depth under
+	// base, e.g. "*.log" matches both "a.log" and "sub/dir/a.log".
+	if ok, _ := doublestar.Match(p.glob, filepath.Base(rel)); ok {
+		return true
+	}
+	ok, _ = doublestar.Match("**/"+p.glob, rel)
+	return ok
+}
+
+// relativeTo returns path with base's prefix stripped, or false if path
+// doesn't fall under base.
+func relativeTo(base, path string) (string, bool) {
+	if base == "" {
+		return path, true
+	}
+	prefix := base + "/"
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(path, prefix), true
+}
+
+// Matcher evaluates a path against an ordered list of Patterns collected
+// from every ignore file between a scan root and that path's directory,
+// root-first. Patterns are evaluated in that order; the last one that
+// matches a given path decides whether it's ignored, so a nested ignore
+// file's rules naturally override an ancestor's, since they were appended
+// later by BuildMatcher.
+type Matcher struct {
+	patterns []Pattern
+}
+
+// NewMatcher returns a Matcher evaluating patterns in the given order.
+func NewMatcher(patterns []Pattern) *Matcher {
+	return &Matcher{patterns: patterns}
+}
+
+// Match reports whether path (relative to the scan root, "/"-separated) is
+// ignored. isDir indicates whether path itself is a directory, needed to
+// honor directory-only ("foo/") patterns.
+func (m *Matcher) Match(path string, isDir bool) bool {
+	ignored := false
+	for _, p := range m.patterns {
+		if p.matches(path, isDir) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// BuildMatcher walks every directory under root, reading any
+// IgnoreFileName file it finds and appending its patterns, in file order,
+// to the accumulated set. A file several directories deep under root ends
+// up checked against every ignore file between it and root, root-first, so
+// a pattern from a directory closer to the file overrides one from an
+// ancestor.
+func BuildMatcher(root string) (*Matcher, error) {
+	var patterns []Pattern
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, IgnoreFileName))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			rel = ""
+		}
+		rel = filepath.ToSlash(rel)
+
+		patterns = append(patterns, ParsePatterns(rel, strings.Split(string(data), "\n"))...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMatcher(patterns), nil
+}
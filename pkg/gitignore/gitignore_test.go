@@ -0,0 +1,70 @@
+package gitignore
+
+import "testing"
+
+func TestMatcherNegation(t *testing.T) {
+	patterns := ParsePatterns("", []string{
+		"*.log",
+		"!important.log",
+	})
+	m := NewMatcher(patterns)
+
+	if !m.Match("debug.log", false) {
+		t.Error("expected debug.log to be ignored")
+	}
+	if m.Match("important.log", false) {
+		t.Error("expected important.log to be re-included by the negation")
+	}
+}
+
+func TestMatcherDirectoryOnly(t *testing.T) {
+	patterns := ParsePatterns("", []string{"build/"})
+	m := NewMatcher(patterns)
+
+	if !m.Match("build", true) {
+		t.Error("expected the build directory to be ignored")
+	}
+	if m.Match("build", false) {
+		t.Error("a file literally named build should not match a directory-only pattern")
+	}
+}
+
+func TestMatcherNestedOverride(t *testing.T) {
+	patterns := append(
+		ParsePatterns("", []string{"*.secret"}),
+		ParsePatterns("vendor", []string{"!keep.secret"})...,
+	)
+	m := NewMatcher(patterns)
+
+	if !m.Match("top.secret", false) {
+		t.Error("expected top.secret to be ignored by the root pattern")
+	}
+	if !m.Match("vendor/other.secret", false) {
+		t.Error("expected vendor/other.secret to still be ignored by the root pattern")
+	}
+	if m.Match("vendor/keep.secret", false) {
+		t.Error("expected the nested ignore file's negation to override the root pattern")
+	}
+}
+
+func TestMatcherAnchoredVsUnanchored(t *testing.T) {
+	patterns := ParsePatterns("", []string{"/root-only.txt", "anywhere.txt"})
+	m := NewMatcher(patterns)
+
+	if !m.Match("root-only.txt", false) {
+		t.Error("expected root-only.txt at the root to be ignored")
+	}
+	if m.Match("sub/root-only.txt", false) {
+		t.Error("expected an anchored pattern not to match in a subdirectory")
+	}
+	if !m.Match("anywhere.txt", false) || !m.Match("sub/dir/anywhere.txt", false) {
+		t.Error("expected an unanchored pattern to match at any depth")
+	}
+}
+
+func TestMatcherNoPatternsMatched(t *testing.T) {
+	m := NewMatcher(ParsePatterns("", []string{"*.log"}))
+	if m.Match("main.go", false) {
+		t.Error("expected main.go not to be ignored")
+	}
+}
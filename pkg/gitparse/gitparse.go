@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,7 +25,13 @@ type Commit struct {
 	Diffs   []Diff
 }
 
-// Diff contains the info about a file diff in a commit.
+// Diff contains the info about a single hunk of a file diff in a commit.
+// LineStart is the line number, in the new file, that Content's first line
+// corresponds to - each subsequent line in Content is one line further,
+// since with -U0 the only lines captured are additions, which always
+// occupy consecutive new-file line numbers within a hunk regardless of how
+// many removals are interleaved with them. A file touched by more than one
+// hunk produces one Diff per hunk, all sharing the same PathA/PathB.
 type Diff struct {
 	PathA     string
 	PathB     string
@@ -104,8 +111,8 @@ func RepoPath(source string, head string) (chan Commit, error) {
 					Message: strings.Builder{},
 				}
 				// Check that the commit line contains a hash and set it.
-				if len(line) >= 47 {
-					currentCommit.Hash = string(line[7:47])
+				if hash := parseCommitHash(line); hash != "" {
+					currentCommit.Hash = hash
 				}
 			case isAuthorLine(line):
 				currentCommit.Author = string(line[8:])
@@ -129,6 +136,17 @@ func RepoPath(source string, head string) (chan Commit, error) {
 				currentDiff.PathB = strings.TrimRight(string(line[6:]), "\n")
 			case isMinusFileLine(line):
 				currentDiff.PathA = strings.TrimRight(string(line[6:]), "\n")
+			case isHunkLine(line):
+				// A second (or later) hunk against the same file starts a
+				// fresh Diff, carrying the paths forward, so each hunk's
+				// LineStart stays accurate for its own Content.
+				if currentDiff.Content.Len() > 0 {
+					currentCommit.Diffs = append(currentCommit.Diffs, *currentDiff)
+					currentDiff = &Diff{PathA: currentDiff.PathA, PathB: currentDiff.PathB}
+				}
+				if newStart, ok := parseHunkNewStart(line); ok {
+					currentDiff.LineStart = newStart
+				}
 			case isPlusDiffLine(line):
 				currentDiff.Content.Write(line[1:])
 			case isMinusDiffLine(line):
@@ -177,6 +195,35 @@ func isCommitLine(line []byte) bool {
 	return false
 }
 
+// commitHashLengths are the hex-encoded object ID lengths this parser
+// recognizes on a `commit <hash>` line: 40 for the standard SHA-1 object
+// format, 64 for go-git's experimental SHA-256 object format.
+var commitHashLengths = [...]int{64, 40}
+
+// parseCommitHash extracts the hash from a `commit <hash>` log line.
+func parseCommitHash(line []byte) string {
+	const prefixLen = len("commit ")
+	if len(line) < prefixLen {
+		return ""
+	}
+	rest := line[prefixLen:]
+	for _, n := range commitHashLengths {
+		if len(rest) >= n && isHexBytes(rest[:n]) {
+			return string(rest[:n])
+		}
+	}
+	return ""
+}
+
+func isHexBytes(b []byte) bool {
+	for _, c := range b {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // diff --git a/internal/addrs/move_endpoint_module.go b/internal/addrs/move_endpoint_module.go
 func isDiffLine(line []byte) bool {
 	if len(line) > 5 && bytes.Equal(line[:4], []byte("diff")) {
@@ -233,6 +280,35 @@ func isMinusDiffLine(line []byte) bool {
 	return false
 }
 
+// @@ -34,6 +34,8 @@ func optionalTrailingContext() {
+func isHunkLine(line []byte) bool {
+	if len(line) > 2 && bytes.Equal(line[:2], []byte("@@")) {
+		return true
+	}
+	return false
+}
+
+// parseHunkNewStart extracts the new-file starting line number - the
+// number following the "+" in a "@@ -a,b +c,d @@" hunk header - so added
+// lines in the hunk that follows can be attributed to their line number in
+// the new file.
+func parseHunkNewStart(line []byte) (int, bool) {
+	plusIdx := bytes.IndexByte(line, '+')
+	if plusIdx == -1 {
+		return 0, false
+	}
+	rest := line[plusIdx+1:]
+	end := bytes.IndexAny(string(rest), ", @")
+	if end == -1 {
+		end = len(rest)
+	}
+	n, err := strconv.Atoi(string(rest[:end]))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
 // Line that starts with 4 spaces
 func isMessageLine(line []byte) bool {
 	if len(line) > 4 && bytes.Equal(line[:4], []byte("    ")) {
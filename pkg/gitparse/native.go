@@ -0,0 +1,187 @@
+package gitparse
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// RepoObjects walks the commit graph reachable from head in a repository
+// backed by storer, and streams one Commit per reachable commit, exactly
+// like RepoPath - but entirely in-process via go-git, with no `git` binary
+// required. Accepting a storage.Storer rather than a filesystem path lets a
+// caller hand it a memory.Storage populated from a packfile fetched
+// straight into RAM (e.g. a shallow clone of a remote ref, or a pack
+// pulled from object storage) instead of a repository checked out to disk.
+//
+// head selects a single starting commit; an empty head walks every
+// reference in the repository (the RepoObjects equivalent of `git log
+// --all`). Shallow repositories are handled for free - go-git's commit
+// walk simply stops at a shallow boundary the same way `git log` does.
+func RepoObjects(ctx context.Context, storer storage.Storer, head string) (chan Commit, error) {
+	repo, err := git.Open(storer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening repository: %w", err)
+	}
+
+	logOpts := &git.LogOptions{}
+	switch {
+	case head != "":
+		logOpts.From = plumbing.NewHash(head)
+	default:
+		logOpts.All = true
+	}
+
+	iter, err := repo.Log(logOpts)
+	if err != nil {
+		return nil, fmt.Errorf("walking commit log: %w", err)
+	}
+
+	commitChan := make(chan Commit)
+	go func() {
+		defer close(commitChan)
+		defer iter.Close()
+
+		_ = iter.ForEach(func(commit *object.Commit) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			out := Commit{
+				Hash:   commit.Hash.String(),
+				Author: commit.Author.String(),
+				Date:   commit.Author.When,
+			}
+			out.Message.WriteString(commit.Message)
+			out.Diffs, err = nativeDiffs(commit)
+			if err != nil {
+				// A single unreadable commit (e.g. a delta chain go-git
+				// can't resolve) shouldn't stop the whole walk.
+				return nil
+			}
+
+			select {
+			case commitChan <- out:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return nil
+		})
+	}()
+
+	return commitChan, nil
+}
+
+// RepoPathNative is the go-git-native equivalent of RepoPath: it opens the
+// on-disk repository at source and streams its commits via RepoObjects,
+// without ever shelling out to the system git binary.
+func RepoPathNative(ctx context.Context, source string, head string) (chan Commit, error) {
+	absPath, err := filepath.Abs(source)
+	if err != nil {
+		return nil, fmt.Errorf("resolving absolute path for %s: %w", source, err)
+	}
+
+	dotGit := filepath.Join(absPath, ".git")
+	storer := filesystem.NewStorage(osfs.New(dotGit), cache.NewObjectLRUDefault())
+
+	return RepoObjects(ctx, storer, head)
+}
+
+// nativeDiffs builds Diff entries for commit by patching it against its
+// first parent, mirroring the "--diff-filter=AM" added/modified-only,
+// additions-only behavior RepoPath gets from `git log -p -U0`. Merge
+// commits and the root commit (no parent to diff against) have nothing to
+// patch against, so they're reported with no diffs, same as RepoPath
+// effectively produces for them.
+func nativeDiffs(commit *object.Commit) ([]Diff, error) {
+	if commit.NumParents() != 1 {
+		return nil, nil
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return nil, fmt.Errorf("getting parent of %s: %w", commit.Hash, err)
+	}
+
+	patch, err := parent.Patch(commit)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s against parent: %w", commit.Hash, err)
+	}
+
+	var diffs []Diff
+	for _, filePatch := range patch.FilePatches() {
+		from, to := filePatch.Files()
+		if to == nil {
+			// Pure deletions aren't scanned by RepoPath either
+			// (--diff-filter=AM), so skip them here too.
+			continue
+		}
+
+		pathA, pathB := "", to.Path()
+		if from != nil {
+			pathA = from.Path()
+		}
+
+		if filePatch.IsBinary() {
+			diffs = append(diffs, Diff{PathA: pathA, PathB: pathB, IsBinary: true})
+			continue
+		}
+
+		diffs = append(diffs, fileHunkDiffs(filePatch.Chunks(), pathA, pathB)...)
+	}
+
+	return diffs, nil
+}
+
+// fileHunkDiffs walks chunks in file order, tracking the new-file line
+// number as it goes, and emits one Diff per added chunk with LineStart set
+// to where that chunk begins in the new file - the same per-hunk
+// new-file-line attribution RepoPath's unified-diff parser derives from
+// each hunk's "@@ -a,b +c,d @@" header. Equal chunks advance the new-file
+// line counter without producing a Diff (unchanged content isn't scanned);
+// deleted chunks don't, since they only ever existed in the old file.
+func fileHunkDiffs(chunks []fdiff.Chunk, pathA, pathB string) []Diff {
+	var diffs []Diff
+	newLine := 1
+	for _, chunk := range chunks {
+		content := chunk.Content()
+		switch chunk.Type() {
+		case fdiff.Add:
+			var diff Diff
+			diff.PathA, diff.PathB = pathA, pathB
+			diff.LineStart = newLine
+			diff.Content.WriteString(content)
+			diffs = append(diffs, diff)
+			newLine += countLines(content)
+		case fdiff.Equal:
+			newLine += countLines(content)
+		case fdiff.Delete:
+			// No-op: removed lines never occupied a new-file line number.
+		}
+	}
+	return diffs
+}
+
+// countLines counts the newline-terminated lines in content, plus one more
+// if content has trailing text with no final newline (e.g. the last line of
+// a file missing a trailing newline).
+func countLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	n := strings.Count(content, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import "testing"
+
+func TestParseBlobFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		wantErr   bool
+		wantNil   bool
+		wantSkip4 bool // whether the parsed filter skips a 4-byte blob
+		wantSkip1 bool // whether the parsed filter skips a 1-byte blob
+	}{
+		{name: "empty spec is no filter", spec: "", wantNil: true},
+		{name: "tree:0 is a no-op", spec: "tree:0", wantNil: true},
+		{name: "blob:none skips everything", spec: "blob:none", wantSkip4: true, wantSkip1: true},
+		{name: "blob:limit in bytes", spec: "blob:limit=2", wantSkip4: true, wantSkip1: false},
+		{name: "blob:limit with k suffix", spec: "blob:limit=1k", wantSkip4: false, wantSkip1: false},
+		{name: "unsupported spec errors", spec: "object:type=tag", wantErr: true},
+		{name: "malformed limit errors", spec: "blob:limit=abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseBlobFilter(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBlobFilter(%q) err = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if f != nil {
+					t.Fatalf("ParseBlobFilter(%q) = %+v, want nil", tt.spec, f)
+				}
+				return
+			}
+			if got := f.skip(4); got != tt.wantSkip4 {
+				t.Errorf("skip(4) = %v, want %v", got, tt.wantSkip4)
+			}
+			if got := f.skip(1); got != tt.wantSkip1 {
+				t.Errorf("skip(1) = %v, want %v", got, tt.wantSkip1)
+			}
+		})
+	}
+}
+
+func TestParseFilterSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"10", 10, false},
+		{"1k", 1024, false},
+		{"2M", 2 * 1024 * 1024, false},
+		{"1g", 1024 * 1024 * 1024, false},
+		{"", 0, true},
+		{"abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFilterSize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFilterSize(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("parseFilterSize(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNilBlobFilterSkipsNothing(t *testing.T) {
+	var f *blobFilter
+	if f.skip(1 << 40) {
+		t.Fatal("nil blobFilter should never skip")
+	}
+}
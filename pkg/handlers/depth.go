@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+)
+
+// archiveDepthKey is the context key under which the current archive
+// nesting depth is stored, so a handler that recurses into nested archives
+// (for example, unpacking a zip found inside another zip) can tell how deep
+// it already is without threading an extra parameter through every call.
+type archiveDepthKey struct{}
+
+// DefaultMaxArchiveDepth bounds how many archives deep FromFile/IsFiletype
+// implementations are allowed to recurse, so a zip-of-zips-of-... (whether
+// crafted maliciously or just deeply nested by accident) can't recurse
+// without bound.
+const DefaultMaxArchiveDepth = 5
+
+// WithArchiveDepth returns a context carrying depth as the current archive
+// nesting depth, for a handler to read back via ArchiveDepth before
+// recursing into a nested archive.
+func WithArchiveDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, archiveDepthKey{}, depth)
+}
+
+// ArchiveDepth returns the archive nesting depth stored in ctx by
+// WithArchiveDepth, or 0 if ctx carries none.
+func ArchiveDepth(ctx context.Context) int {
+	depth, _ := ctx.Value(archiveDepthKey{}).(int)
+	return depth
+}
+
+// ExceedsMaxArchiveDepth reports whether ctx's current archive nesting
+// depth has already reached maxDepth, in which case a handler about to
+// recurse into a nested archive should stop and skip it instead.
+func ExceedsMaxArchiveDepth(ctx context.Context, maxDepth int) bool {
+	return ArchiveDepth(ctx) >= maxDepth
+}
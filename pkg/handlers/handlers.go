@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"bufio"
 	"context"
 	"io"
+	"sync"
 
 	logContext "github.com/trufflesecurity/trufflehog/v3/pkg/context"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
@@ -14,6 +16,63 @@ func DefaultHandlers() []Handler {
 	}
 }
 
+// newRPMHandler, newOCIHandler, and newPackfileHandler build the
+// content-sniffed handlers (RPM, OCI image, git packfile) that plug into
+// this same dispatch once the content-sniffing path that selects between
+// them is wired up.
+
+// sniffLen is how many leading bytes of a file are made available to a
+// registered matcher for content-type sniffing, rather than relying purely
+// on a filename extension that may not even be known at this layer.
+const sniffLen = 512
+
+// registeredHandler pairs a custom handler with the matcher that decides
+// whether it applies to a given file, based on its content rather than its
+// name.
+type registeredHandler struct {
+	name    string
+	matcher func(head []byte) bool
+	newFunc func() Handler
+}
+
+var (
+	customHandlersMu sync.RWMutex
+	customHandlers   []registeredHandler
+)
+
+// Register adds a custom Handler to those HandleFile tries, for file
+// formats trufflehog doesn't natively recognize - Android APKs, Java class
+// files, WASM modules, ELF debug sections, PDF streams, sqlite DBs, and so
+// on. matcher is handed the first sniffLen bytes of the file (fewer, if the
+// file is smaller) and decides whether h applies; newFunc builds a fresh h
+// for each file, mirroring the existing Handler.New() per-file lifecycle.
+// Registering two handlers under the same name replaces the earlier one.
+func Register(name string, matcher func(head []byte) bool, newFunc func() Handler) {
+	customHandlersMu.Lock()
+	defer customHandlersMu.Unlock()
+
+	for i, rh := range customHandlers {
+		if rh.name == name {
+			customHandlers[i] = registeredHandler{name, matcher, newFunc}
+			return
+		}
+	}
+	customHandlers = append(customHandlers, registeredHandler{name, matcher, newFunc})
+}
+
+// sniff peeks up to sniffLen bytes from file without consuming them, for a
+// registered matcher to sniff the file's actual content type against,
+// returning a reader that still yields those bytes to whatever reads from
+// it next.
+func sniff(file io.Reader) ([]byte, io.Reader, error) {
+	br := bufio.NewReaderSize(file, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, br, err
+	}
+	return head, br, nil
+}
+
 // SpecializedHandler defines the interface for handlers that can process specialized archives.
 // It includes a method to handle specialized archives and determine if the file is of a special type.
 type SpecializedHandler interface {
@@ -59,9 +118,45 @@ func HandleFile(ctx context.Context, file io.Reader, chunkSkel *sources.Chunk, c
 			return handleChunks(aCtx, h.FromFile(ctx, file), chunkSkel, chunksChan)
 		}
 	}
+
+	if handled, ok := tryCustomHandlers(aCtx, file, chunkSkel, chunksChan); ok {
+		return handled
+	}
 	return false
 }
 
+// tryCustomHandlers sniffs the first sniffLen bytes of file and, if a
+// handler registered via Register matches them, hands the file off to it.
+// The bool return reports whether any registered handler matched at all,
+// so HandleFile can tell "matched but failed" apart from "nothing matched".
+func tryCustomHandlers(ctx logContext.Context, file io.Reader, chunkSkel *sources.Chunk, chunksChan chan *sources.Chunk) (bool, bool) {
+	customHandlersMu.RLock()
+	handlers := customHandlers
+	customHandlersMu.RUnlock()
+	if len(handlers) == 0 {
+		return false, false
+	}
+
+	head, file, err := sniff(file)
+	if err != nil {
+		ctx.Logger().Error(err, "error sniffing file content for custom handlers")
+		return false, false
+	}
+
+	for _, rh := range handlers {
+		if !rh.matcher(head) {
+			continue
+		}
+		h := rh.newFunc()
+		h.New()
+		if _, isType := h.IsFiletype(ctx, file); !isType {
+			continue
+		}
+		return handleChunks(ctx, h.FromFile(ctx, file), chunkSkel, chunksChan), true
+	}
+	return false, false
+}
+
 func handleChunks(ctx context.Context, handlerChan chan []byte, chunkSkel *sources.Chunk, chunksChan chan *sources.Chunk) bool {
 	for {
 		select {
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	logContext "github.com/trufflesecurity/trufflehog/v3/pkg/context"
+)
+
+// gzipMagic is the two-byte magic number that identifies a gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ociHandler specializes archiveHandler to process OCI/Docker container images, such
+// as those produced by `docker save` or an OCI image layout tarball. The outer tar is
+// read entry by entry, and each layer blob found within it (gzip-compressed or plain)
+// is streamed and scanned without ever being fully decompressed to disk.
+type ociHandler struct{ *defaultHandler }
+
+// newOCIHandler creates an ociHandler with the provided metrics.
+func newOCIHandler() *ociHandler {
+	return &ociHandler{defaultHandler: newDefaultHandler(ociHandlerType)}
+}
+
+// HandleFile processes an OCI image tarball. Each layer it contains is extracted and
+// its files are handed off to handleNonArchiveContent just like any other archive
+// member.
+func (h *ociHandler) HandleFile(ctx logContext.Context, input fileReader) chan DataOrErr {
+	dataOrErrChan := make(chan DataOrErr, defaultBufferSize)
+
+	go func() {
+		defer close(dataOrErrChan)
+
+		start := time.Now()
+
+		defer func() {
+			if r := recover(); r != nil {
+				dataOrErrChan <- DataOrErr{Err: fmt.Errorf("%w: panic processing OCI image: %v", ErrProcessingFatal, r)}
+			}
+		}()
+
+		err := h.processImageTar(ctx, input, dataOrErrChan)
+		if err == nil {
+			h.metrics.incFilesProcessed()
+		}
+		h.measureLatencyAndHandleErrors(ctx, start, err, dataOrErrChan)
+	}()
+
+	return dataOrErrChan
+}
+
+// processImageTar walks the outer image tarball, dispatching each layer blob it finds
+// to processLayer.
+func (h *ociHandler) processImageTar(ctx logContext.Context, input fileReader, dataOrErrChan chan DataOrErr) error {
+	tr := tar.NewReader(input)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		header, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("error reading image tar: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || !isLayerBlob(header.Name) {
+			continue
+		}
+
+		layerCtx := logContext.WithValues(ctx, "layer", header.Name)
+		if err := h.processLayer(layerCtx, tr, dataOrErrChan); err != nil {
+			dataOrErrChan <- DataOrErr{Err: fmt.Errorf("%w: error processing layer %q: %v", ErrProcessingWarning, header.Name, err)}
+			h.metrics.incErrors()
+		}
+	}
+}
+
+// isLayerBlob reports whether a path within the outer image tarball looks like a
+// filesystem layer rather than image config, manifest, or index metadata: either the
+// `docker save` convention of "<id>/layer.tar", or an OCI layout content-addressed
+// blob under "blobs/sha256/...".
+func isLayerBlob(name string) bool {
+	return strings.HasSuffix(name, "/layer.tar") || strings.HasPrefix(name, "blobs/sha256/")
+}
+
+// processLayer streams a single layer's (optionally gzip-compressed) tarball, emitting
+// each regular file's contents through handleNonArchiveContent.
+func (h *ociHandler) processLayer(ctx logContext.Context, r io.Reader, dataOrErrChan chan DataOrErr) error {
+	bufReader := bufio.NewReader(r)
+	if magic, err := bufReader.Peek(len(gzipMagic)); err == nil && string(magic) == string(gzipMagic) {
+		gzr, err := gzip.NewReader(bufReader)
+		if err != nil {
+			return fmt.Errorf("error creating gzip reader: %w", err)
+		}
+		defer gzr.Close()
+		r = gzr
+	} else {
+		r = bufReader
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		header, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			// Not every blob under blobs/sha256/ is a filesystem layer (some are
+			// config or manifest JSON); failing to parse it as a tar just means skip it.
+			return nil
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		fileCtx := logContext.WithValues(ctx, "filename", header.Name, "size", header.Size)
+		rdr, err := newMimeTypeReader(tr)
+		if err != nil {
+			return fmt.Errorf("error creating mime-type reader: %w", err)
+		}
+
+		if err := h.handleNonArchiveContent(fileCtx, rdr, dataOrErrChan); err != nil {
+			dataOrErrChan <- DataOrErr{Err: fmt.Errorf("%w: error processing layer file %q: %v", ErrProcessingWarning, header.Name, err)}
+			h.metrics.incErrors()
+		}
+
+		h.metrics.incFilesProcessed()
+		h.metrics.observeFileSize(header.Size)
+	}
+}
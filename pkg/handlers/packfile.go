@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	logContext "github.com/trufflesecurity/trufflehog/v3/pkg/context"
+)
+
+// packfileMagic is the 4-byte signature every packfile starts with, per
+// gitformat-pack(5), followed by a 4-byte big-endian version (currently
+// always 2 or 3) and a 4-byte object count.
+var packfileMagic = []byte("PACK")
+
+// packfileHandler specializes archiveHandler to stream git objects out of a
+// .pack file - the dominant on-disk format for a repository's objects once
+// they've been packed, whether sitting inside a live .git/objects/pack
+// directory, a `git bundle`, or a CI artifact. Only blob objects carry file
+// content worth scanning, so commit/tree/tag objects are decoded (packfile
+// deltas can chain through any object type) but never handed off for
+// chunking.
+type packfileHandler struct {
+	*defaultHandler
+	blobFilter *blobFilter
+}
+
+// newPackfileHandler creates a packfileHandler using the package-wide
+// defaultBlobFilter (see blobfilter.go / WithBlobFilter).
+func newPackfileHandler() *packfileHandler {
+	return &packfileHandler{defaultHandler: newDefaultHandler(packfileHandlerType), blobFilter: defaultBlobFilter}
+}
+
+// IsFiletype sniffs input's first 4 bytes for the "PACK" signature every
+// packfile starts with, so packfileHandler composes with the dispatch
+// archiveHandler uses to pick a SpecializedHandler the same way rpmHandler
+// and ociHandler's content-sniffed formats do. It never consumes input
+// beyond what it peeks, so the returned reader still starts at byte 0.
+func (h *packfileHandler) IsFiletype(_ logContext.Context, input io.Reader) (io.Reader, bool) {
+	bufReader := bufio.NewReader(input)
+	magic, err := bufReader.Peek(len(packfileMagic))
+	if err != nil {
+		return bufReader, false
+	}
+	return bufReader, bytes.Equal(magic, packfileMagic)
+}
+
+// HandleSpecialized satisfies SpecializedHandler for composability with
+// archiveHandler's nested-archive dispatch, but a .pack file has nothing
+// further to sniff beyond IsFiletype's magic-number check - there's no
+// second specialized format a packfile could itself be wrapping - so this
+// always reports isSpecial=false and leaves input for IsFiletype/HandleFile
+// to take over.
+func (h *packfileHandler) HandleSpecialized(_ logContext.Context, input io.Reader) (io.Reader, bool, error) {
+	return input, false, nil
+}
+
+// HandleFile streams every blob out of a .pack file, feeding its content
+// through handleNonArchiveContent as a nested chunk tagged with objectHash
+// and objectType. Resolving delta-compressed objects against the rest of
+// the pack requires either the pack's own trailing object graph or its
+// companion .idx - since input is a single forward-only stream with
+// neither available up front, objects are parsed into an in-memory store
+// first (a two-pass decode: once to record every object, once more to
+// resolve deltas against what was recorded) rather than resolved in a
+// single streaming pass.
+func (h *packfileHandler) HandleFile(ctx logContext.Context, input fileReader) chan DataOrErr {
+	dataOrErrChan := make(chan DataOrErr, defaultBufferSize)
+
+	go func() {
+		defer close(dataOrErrChan)
+
+		start := time.Now()
+
+		defer func() {
+			if r := recover(); r != nil {
+				dataOrErrChan <- DataOrErr{Err: fmt.Errorf("%w: panic processing packfile: %v", ErrProcessingFatal, r)}
+			}
+		}()
+
+		store := memory.NewStorage()
+		scanner := packfile.NewScanner(input)
+		parser, err := packfile.NewParser(scanner, store)
+		if err != nil {
+			dataOrErrChan <- DataOrErr{Err: fmt.Errorf("%w: error creating packfile parser: %v", ErrProcessingFatal, err)}
+			return
+		}
+		if _, err := parser.Parse(); err != nil {
+			dataOrErrChan <- DataOrErr{Err: fmt.Errorf("%w: error parsing packfile: %v", ErrProcessingFatal, err)}
+			return
+		}
+
+		blobCommits := commitsByBlob(store)
+
+		err = h.processObjects(ctx, store, blobCommits, dataOrErrChan)
+		if err == nil {
+			h.metrics.incFilesProcessed()
+		}
+		h.measureLatencyAndHandleErrors(ctx, start, err, dataOrErrChan)
+	}()
+
+	return dataOrErrChan
+}
+
+// commitsByBlob walks every commit object store holds, resolving each one's
+// tree recursively, and records which commit(s) reference each blob it
+// finds - so a finding in a blob that recurs unchanged across history can
+// still be attributed to every commit that actually carries it, not just
+// the one the pack happened to be fetched for. A commit or tree that fails
+// to decode is skipped rather than aborting the whole walk, since a
+// reachable-but-unparseable object elsewhere in the pack shouldn't prevent
+// attributing the blobs that did resolve.
+func commitsByBlob(store *memory.Storage) map[plumbing.Hash][]string {
+	result := make(map[plumbing.Hash][]string)
+
+	commitIter, err := store.IterEncodedObjects(plumbing.CommitObject)
+	if err != nil {
+		return result
+	}
+
+	_ = commitIter.ForEach(func(obj plumbing.EncodedObject) error {
+		commit, err := object.DecodeCommit(store, obj)
+		if err != nil {
+			return nil
+		}
+		tree, err := commit.Tree()
+		if err != nil {
+			return nil
+		}
+
+		files := tree.Files()
+		defer files.Close()
+		_ = files.ForEach(func(f *object.File) error {
+			result[f.Hash] = append(result[f.Hash], commit.Hash.String())
+			return nil
+		})
+		return nil
+	})
+
+	return result
+}
+
+// processObjects walks every blob object store holds after parsing,
+// skipping any the handler's blobFilter rejects, and hands its content off
+// to handleNonArchiveContent tagged with the object's hash, type, and the
+// commit(s) - from blobCommits - that reference it, if any were resolved.
+func (h *packfileHandler) processObjects(ctx logContext.Context, store *memory.Storage, blobCommits map[plumbing.Hash][]string, dataOrErrChan chan DataOrErr) error {
+	objIter, err := store.IterEncodedObjects(plumbing.BlobObject)
+	if err != nil {
+		return fmt.Errorf("error iterating packfile blobs: %w", err)
+	}
+
+	return objIter.ForEach(func(obj plumbing.EncodedObject) error {
+		if h.blobFilter.skip(obj.Size()) {
+			h.metrics.incFilesSkipped()
+			return nil
+		}
+
+		r, err := obj.Reader()
+		if err != nil {
+			dataOrErrChan <- DataOrErr{
+				Err: fmt.Errorf("%w: error reading packfile blob %s: %v", ErrProcessingWarning, obj.Hash(), err),
+			}
+			return nil
+		}
+		defer r.Close()
+
+		objCtx := logContext.WithValues(ctx, "objectHash", obj.Hash().String(), "objectType", obj.Type().String())
+		if commits := blobCommits[obj.Hash()]; len(commits) > 0 {
+			objCtx = logContext.WithValues(objCtx, "commit", strings.Join(commits, ","))
+		}
+		rdr, err := newMimeTypeReader(r)
+		if err != nil {
+			dataOrErrChan <- DataOrErr{
+				Err: fmt.Errorf("%w: error creating mime-type reader for packfile blob %s: %v", ErrProcessingWarning, obj.Hash(), err),
+			}
+			return nil
+		}
+		if err := h.handleNonArchiveContent(objCtx, rdr, dataOrErrChan); err != nil {
+			dataOrErrChan <- DataOrErr{
+				Err: fmt.Errorf("%w: error processing packfile blob %s: %v", ErrProcessingWarning, obj.Hash(), err),
+			}
+			h.metrics.incErrors()
+		}
+		h.metrics.incFilesProcessed()
+		h.metrics.observeFileSize(obj.Size())
+		return nil
+	})
+}
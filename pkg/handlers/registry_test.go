@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRegisterReplacesSameName(t *testing.T) {
+	calls := 0
+	Register("test-dummy", func(head []byte) bool { return true }, func() Handler {
+		calls++
+		return nil
+	})
+	Register("test-dummy", func(head []byte) bool { return true }, func() Handler {
+		calls++
+		return nil
+	})
+
+	customHandlersMu.RLock()
+	defer customHandlersMu.RUnlock()
+	found := 0
+	for _, rh := range customHandlers {
+		if rh.name == "test-dummy" {
+			found++
+		}
+	}
+	if found != 1 {
+		t.Fatalf("expected exactly one registered handler named test-dummy, got %d", found)
+	}
+}
+
+func TestArchiveDepth(t *testing.T) {
+	ctx := context.Background()
+	if got := ArchiveDepth(ctx); got != 0 {
+		t.Fatalf("expected default depth 0, got %d", got)
+	}
+
+	ctx = WithArchiveDepth(ctx, 3)
+	if got := ArchiveDepth(ctx); got != 3 {
+		t.Fatalf("expected depth 3, got %d", got)
+	}
+	if !ExceedsMaxArchiveDepth(ctx, 3) {
+		t.Fatal("expected depth 3 to exceed max depth 3")
+	}
+	if ExceedsMaxArchiveDepth(ctx, 4) {
+		t.Fatal("expected depth 3 to not exceed max depth 4")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/sassoftware/go-rpmutils"
@@ -58,6 +59,8 @@ func (h *rpmHandler) HandleFile(ctx logContext.Context, input fileReader) chan D
 			return
 		}
 
+		h.processRPMHeader(ctx, rpm, dataOrErrChan)
+
 		reader, err := rpm.PayloadReaderExtended()
 		if err != nil {
 			dataOrErrChan <- DataOrErr{
@@ -78,6 +81,56 @@ func (h *rpmHandler) HandleFile(ctx logContext.Context, input fileReader) chan D
 	return dataOrErrChan
 }
 
+// rpmMetadataField names a single piece of header text to be scanned alongside the
+// package's payload files: either a changelog entry or an install scriptlet.
+type rpmMetadataField struct {
+	name string
+	tag  int
+}
+
+// rpmMetadataFields enumerates the RPM header tags that can carry secrets but live
+// outside the payload archive: changelog entries (which sometimes carry
+// copy-pasted credentials from a build log) and the pre/post (un)install scriptlets
+// (which often embed provisioning tokens).
+var rpmMetadataFields = []rpmMetadataField{
+	{"changelog", rpmutils.CHANGELOGTEXT},
+	{"prein", rpmutils.PREIN},
+	{"postin", rpmutils.POSTIN},
+	{"preun", rpmutils.PREUN},
+	{"postun", rpmutils.POSTUN},
+}
+
+// processRPMHeader scans the RPM header's changelog entries and install scriptlets,
+// which live outside the payload archive handled by processRPMFiles.
+func (h *rpmHandler) processRPMHeader(ctx logContext.Context, rpm *rpmutils.Rpm, dataOrErrChan chan DataOrErr) {
+	for _, field := range rpmMetadataFields {
+		values, err := rpm.Header.GetStrings(field.tag)
+		if err != nil || len(values) == 0 {
+			continue
+		}
+
+		fieldCtx := logContext.WithValues(ctx, "rpm_header_field", field.name)
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			rdr, err := newMimeTypeReader(strings.NewReader(value))
+			if err != nil {
+				dataOrErrChan <- DataOrErr{
+					Err: fmt.Errorf("%w: error creating mime-type reader for rpm %s: %v", ErrProcessingWarning, field.name, err),
+				}
+				continue
+			}
+			if err := h.handleNonArchiveContent(fieldCtx, rdr, dataOrErrChan); err != nil {
+				dataOrErrChan <- DataOrErr{
+					Err: fmt.Errorf("%w: error processing rpm %s: %v", ErrProcessingWarning, field.name, err),
+				}
+				h.metrics.incErrors()
+			}
+		}
+	}
+}
+
 func (h *rpmHandler) processRPMFiles(
 	ctx logContext.Context,
 	reader rpmutils.PayloadReader,
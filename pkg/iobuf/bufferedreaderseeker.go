@@ -0,0 +1,216 @@
+package iobuf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// BufferedReadSeeker wraps a reader so that callers can always Seek and
+// ReadAt on it, regardless of whether the underlying reader already
+// implements io.Seeker. When it doesn't, bytes are spooled as they're read
+// so that a later Seek backward, or a ReadAt, can be served from what's
+// already been consumed instead of failing outright.
+//
+// NewBufferedReadSeeker spools into one buffer that grows for as long as
+// the BufferedReadSeeker lives - fine for a small-to-moderate file, but
+// unbounded for a large archive streamed from the network.
+// NewBufferedReadSeekerWithLimit trades that for a bounded, LRU-evicted
+// page cache; see its doc comment.
+type BufferedReadSeeker struct {
+	reader io.Reader
+	seeker io.Seeker
+
+	// buffer holds every byte spooled from reader so far, for a
+	// BufferedReadSeeker built with NewBufferedReadSeeker. It's nil when
+	// built with NewBufferedReadSeekerWithLimit, which uses cache instead.
+	buffer *bytes.Buffer
+	cache  *lruPageCache
+
+	bytesRead int64
+	index     int64
+
+	activeBuffering bool
+}
+
+// NewBufferedReadSeeker wraps r so it can always be Seek'd and ReadAt'd.
+// If r doesn't already implement io.Seeker, every byte read from it is
+// spooled into an in-memory buffer that grows without bound for the
+// lifetime of the BufferedReadSeeker - see NewBufferedReadSeekerWithLimit
+// for a version that bounds that growth.
+func NewBufferedReadSeeker(r io.Reader) *BufferedReadSeeker {
+	brs := &BufferedReadSeeker{reader: r, activeBuffering: true}
+	if seeker, ok := r.(io.Seeker); ok {
+		brs.seeker = seeker
+	} else {
+		brs.buffer = &bytes.Buffer{}
+	}
+	return brs
+}
+
+// EnableBuffering turns spooling back on after DisableBuffering.
+func (b *BufferedReadSeeker) EnableBuffering() { b.activeBuffering = true }
+
+// DisableBuffering stops Read from appending newly read bytes to the
+// buffer (or cache) for a non-seekable reader - a degenerate "no cache"
+// mode for a caller that knows it'll only ever read forward once and
+// doesn't want the memory (or page-eviction) overhead of spooling bytes it
+// will never revisit. Seek, ReadAt, and Size still spool as needed, since
+// they can't otherwise do their job.
+func (b *BufferedReadSeeker) DisableBuffering() { b.activeBuffering = false }
+
+// Read implements io.Reader.
+func (b *BufferedReadSeeker) Read(out []byte) (int, error) {
+	if b.seeker != nil {
+		return b.reader.Read(out)
+	}
+
+	if b.cache != nil {
+		n, err := b.readAtCache(out, b.index)
+		b.index += int64(n)
+		return n, err
+	}
+
+	if b.index < int64(b.buffer.Len()) {
+		n := copy(out, b.buffer.Bytes()[b.index:])
+		b.index += int64(n)
+		return n, nil
+	}
+
+	n, err := b.reader.Read(out)
+	if n > 0 {
+		if b.activeBuffering {
+			b.buffer.Write(out[:n])
+		}
+		b.bytesRead += int64(n)
+		b.index += int64(n)
+	}
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt.
+func (b *BufferedReadSeeker) ReadAt(out []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("iobuf: negative offset")
+	}
+
+	if b.seeker != nil {
+		if ra, ok := b.reader.(io.ReaderAt); ok {
+			return ra.ReadAt(out, offset)
+		}
+		if _, err := b.seeker.Seek(offset, io.SeekStart); err != nil {
+			return 0, err
+		}
+		return io.ReadFull(b.reader, out)
+	}
+
+	if b.cache != nil {
+		return b.readAtCache(out, offset)
+	}
+
+	if err := b.fillBufferTo(offset + int64(len(out))); err != nil && int64(b.buffer.Len()) <= offset {
+		return 0, err
+	}
+
+	if offset >= int64(b.buffer.Len()) {
+		return 0, io.EOF
+	}
+	n := copy(out, b.buffer.Bytes()[offset:])
+	if n < len(out) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fillBufferTo spools reader into buffer until it holds at least target
+// bytes, or reader is exhausted.
+func (b *BufferedReadSeeker) fillBufferTo(target int64) error {
+	chunk := make([]byte, 4096)
+	for int64(b.buffer.Len()) < target {
+		n, err := b.reader.Read(chunk)
+		if n > 0 {
+			b.buffer.Write(chunk[:n])
+			b.bytesRead += int64(n)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Seek implements io.Seeker.
+func (b *BufferedReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	if b.seeker != nil {
+		pos, err := b.seeker.Seek(offset, whence)
+		b.index = pos
+		return pos, err
+	}
+
+	var newIndex int64
+	switch whence {
+	case io.SeekStart:
+		newIndex = offset
+	case io.SeekCurrent:
+		newIndex = b.index + offset
+	case io.SeekEnd:
+		size, err := b.Size()
+		if err != nil {
+			return 0, err
+		}
+		newIndex = size + offset
+	default:
+		return 0, fmt.Errorf("iobuf: invalid whence %d", whence)
+	}
+	if newIndex < 0 {
+		return 0, fmt.Errorf("iobuf: negative position")
+	}
+
+	if b.cache != nil {
+		if err := b.ensureSpooledThrough(newIndex); err != nil && b.bytesRead < newIndex {
+			newIndex = b.bytesRead
+		}
+		b.index = newIndex
+		return b.index, nil
+	}
+
+	if err := b.fillBufferTo(newIndex); err != nil && int64(b.buffer.Len()) < newIndex {
+		newIndex = int64(b.buffer.Len())
+	}
+	b.index = newIndex
+	return b.index, nil
+}
+
+// Size reports the total length of the underlying reader, spooling it
+// entirely if it isn't seekable.
+func (b *BufferedReadSeeker) Size() (int64, error) {
+	if b.seeker != nil {
+		cur, err := b.seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		size, err := b.seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, err
+		}
+		_, err = b.seeker.Seek(cur, io.SeekStart)
+		return size, err
+	}
+
+	if b.cache != nil {
+		return b.drainCache()
+	}
+
+	chunk := make([]byte, 4096)
+	for {
+		n, err := b.reader.Read(chunk)
+		if n > 0 {
+			b.buffer.Write(chunk[:n])
+			b.bytesRead += int64(n)
+		}
+		if err != nil {
+			break
+		}
+	}
+	return int64(b.buffer.Len()), nil
+}
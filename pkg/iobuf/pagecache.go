@@ -0,0 +1,264 @@
+package iobuf
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"os"
+)
+
+// defaultPageSize is the fixed unit a lruPageCache spools and evicts in,
+// matching the page size go-git's plumbing/cache buffer_lru uses for the
+// same "bound a stream of object bytes in memory" problem.
+const defaultPageSize = 64 * 1024
+
+// pageCacheMetrics tracks how an lruPageCache is doing, so a caller
+// streaming a large non-seekable source can tell whether it's thrashing.
+type pageCacheMetrics struct {
+	Hits, Misses, Evictions uint64
+}
+
+// page is one fixed-size window of a spooled reader's bytes, keyed by its
+// index (byte offset / pageSize).
+type page struct {
+	index int64
+	data  []byte
+}
+
+// lruPageCache bounds how much of a non-seekable reader's bytes are held
+// in memory at once, evicting the least-recently-used page once curBytes
+// exceeds maxBytes - modeled on go-git's plumbing/cache buffer_lru, but
+// spilling an evicted page to a temp file instead of dropping it, so a
+// Seek backward past the in-memory window still works.
+type lruPageCache struct {
+	pageSize int64
+	maxBytes int64
+	curBytes int64
+
+	order   *list.List              // front = most recently used
+	byIndex map[int64]*list.Element // page index -> element in order
+
+	spill    *os.File // lazily created on the first eviction
+	spillDir string
+	spillAt  map[int64]int64 // page index -> byte offset within spill
+
+	metrics pageCacheMetrics
+}
+
+func newLRUPageCache(maxBytes int64, spillDir string) *lruPageCache {
+	return &lruPageCache{
+		pageSize: defaultPageSize,
+		maxBytes: maxBytes,
+		order:    list.New(),
+		byIndex:  make(map[int64]*list.Element),
+		spillAt:  make(map[int64]int64),
+		spillDir: spillDir,
+	}
+}
+
+// get returns a page's data, transparently reading it back from the spill
+// file if it was evicted from memory. A page read back from disk isn't
+// re-admitted to memory, so scanning through an already-spilled range
+// can't thrash the budget paging the same bytes back in over and over.
+func (c *lruPageCache) get(idx int64) ([]byte, bool) {
+	if el, ok := c.byIndex[idx]; ok {
+		c.order.MoveToFront(el)
+		c.metrics.Hits++
+		return el.Value.(*page).data, true
+	}
+
+	c.metrics.Misses++
+	off, ok := c.spillAt[idx]
+	if !ok || c.spill == nil {
+		return nil, false
+	}
+	data := make([]byte, c.pageSize)
+	n, err := c.spill.ReadAt(data, off)
+	if err != nil && err != io.EOF {
+		return nil, false
+	}
+	return data[:n], true
+}
+
+// put inserts a freshly-read page, evicting the least-recently-used page
+// to disk if doing so is needed to stay within maxBytes.
+func (c *lruPageCache) put(idx int64, data []byte) error {
+	if el, ok := c.byIndex[idx]; ok {
+		c.order.MoveToFront(el)
+		return nil
+	}
+
+	cp := make([]byte, len(data))
+	copy(cp, data)
+	el := c.order.PushFront(&page{index: idx, data: cp})
+	c.byIndex[idx] = el
+	c.curBytes += int64(len(cp))
+
+	for c.curBytes > c.maxBytes && c.order.Len() > 1 {
+		if err := c.evictOldest(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *lruPageCache) evictOldest() error {
+	back := c.order.Back()
+	if back == nil {
+		return nil
+	}
+	p := back.Value.(*page)
+
+	if err := c.spillPage(p); err != nil {
+		return err
+	}
+
+	c.order.Remove(back)
+	delete(c.byIndex, p.index)
+	c.curBytes -= int64(len(p.data))
+	c.metrics.Evictions++
+	return nil
+}
+
+func (c *lruPageCache) spillPage(p *page) error {
+	if c.spill == nil {
+		f, err := os.CreateTemp(c.spillDir, "trufflehog-bufseek-*.spill")
+		if err != nil {
+			return fmt.Errorf("creating spill file: %w", err)
+		}
+		c.spill = f
+	}
+	off, err := c.spill.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("seeking spill file: %w", err)
+	}
+	if _, err := c.spill.Write(p.data); err != nil {
+		return fmt.Errorf("spilling page %d: %w", p.index, err)
+	}
+	c.spillAt[p.index] = off
+	return nil
+}
+
+// close releases the spill file, if one was ever created.
+func (c *lruPageCache) close() error {
+	if c.spill == nil {
+		return nil
+	}
+	name := c.spill.Name()
+	if err := c.spill.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+// NewBufferedReadSeekerWithLimit is like NewBufferedReadSeeker, but for a
+// non-seekable r it bounds how much of r's bytes are held in memory at
+// once to maxInMemory bytes, evicting least-recently-used pages to a temp
+// file under spillDir (the OS default temp directory if spillDir is
+// empty) instead of growing an unbounded buffer. A seekable r is handled
+// exactly as NewBufferedReadSeeker handles it, since there's nothing to
+// bound - Seek is already free.
+//
+// Call Close when done with the returned BufferedReadSeeker to remove any
+// spill file it created; CacheMetrics reports hits, misses, and evictions.
+func NewBufferedReadSeekerWithLimit(r io.Reader, maxInMemory int64, spillDir string) *BufferedReadSeeker {
+	brs := &BufferedReadSeeker{reader: r, activeBuffering: true}
+	if seeker, ok := r.(io.Seeker); ok {
+		brs.seeker = seeker
+		return brs
+	}
+	brs.cache = newLRUPageCache(maxInMemory, spillDir)
+	return brs
+}
+
+// CacheMetrics reports hits/misses/evictions for a BufferedReadSeeker
+// built with NewBufferedReadSeekerWithLimit. It's the zero value for one
+// built with NewBufferedReadSeeker, which has no bounded cache to report.
+func (b *BufferedReadSeeker) CacheMetrics() (hits, misses, evictions uint64) {
+	if b.cache == nil {
+		return 0, 0, 0
+	}
+	return b.cache.metrics.Hits, b.cache.metrics.Misses, b.cache.metrics.Evictions
+}
+
+// Close releases the temp file a bounded cache spilled evicted pages to,
+// if one was created. It's a no-op for a BufferedReadSeeker with no
+// bounded cache.
+func (b *BufferedReadSeeker) Close() error {
+	if b.cache == nil {
+		return nil
+	}
+	return b.cache.close()
+}
+
+// ensureSpooledThrough reads from reader, one page at a time, until the
+// cache has spooled at least target bytes or reader is exhausted.
+func (b *BufferedReadSeeker) ensureSpooledThrough(target int64) error {
+	buf := make([]byte, b.cache.pageSize)
+	for b.bytesRead < target {
+		n, err := io.ReadFull(b.reader, buf)
+		if n > 0 {
+			idx := b.bytesRead / b.cache.pageSize
+			if perr := b.cache.put(idx, buf[:n]); perr != nil {
+				return perr
+			}
+			b.bytesRead += int64(n)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readAtCache serves dst from the page cache, spooling further into it as
+// needed, starting at offset.
+func (b *BufferedReadSeeker) readAtCache(dst []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("iobuf: negative offset")
+	}
+
+	end := offset + int64(len(dst))
+	if err := b.ensureSpooledThrough(end); err != nil && b.bytesRead <= offset {
+		return 0, io.EOF
+	}
+
+	total := 0
+	for total < len(dst) {
+		pos := offset + int64(total)
+		if pos >= b.bytesRead {
+			break
+		}
+		idx := pos / b.cache.pageSize
+		pageOff := pos % b.cache.pageSize
+		data, ok := b.cache.get(idx)
+		if !ok || pageOff >= int64(len(data)) {
+			break
+		}
+		n := copy(dst[total:], data[pageOff:])
+		total += n
+	}
+	if total < len(dst) {
+		return total, io.EOF
+	}
+	return total, nil
+}
+
+// drainCache spools reader to exhaustion into the cache, to answer Size.
+func (b *BufferedReadSeeker) drainCache() (int64, error) {
+	buf := make([]byte, b.cache.pageSize)
+	for {
+		n, err := io.ReadFull(b.reader, buf)
+		if n > 0 {
+			idx := b.bytesRead / b.cache.pageSize
+			if perr := b.cache.put(idx, buf[:n]); perr != nil {
+				return b.bytesRead, perr
+			}
+			b.bytesRead += int64(n)
+		}
+		if err != nil {
+			break
+		}
+	}
+	return b.bytesRead, nil
+}
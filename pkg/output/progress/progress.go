@@ -0,0 +1,192 @@
+// Package progress renders a live view of an in-progress scan: chunks/bytes
+// scanned, throughput, verified/unverified hit counts, recently processed
+// source units, and elapsed time. Rendering is decoupled from how progress
+// is collected (a Tracker just needs periodic counters and unit names fed to
+// it), so it can be reused by any future embedding of the engine, not just
+// the CLI.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Snapshot is a point-in-time view of scan progress.
+type Snapshot struct {
+	ChunksScanned     uint64
+	BytesScanned      uint64
+	VerifiedSecrets   uint64
+	UnverifiedSecrets uint64
+	RecentUnits       []string
+	Elapsed           time.Duration
+}
+
+// Renderer draws Snapshots somewhere. TTYRenderer and LogRenderer both
+// implement it.
+type Renderer interface {
+	// Render draws s, replacing whatever this Renderer last drew if it can.
+	Render(s Snapshot)
+	// Stop draws a final Snapshot and leaves the output in a clean state.
+	Stop(s Snapshot)
+}
+
+// NewRenderer returns a TTYRenderer if isTTY is true, otherwise a
+// LogRenderer that calls log instead of redrawing in place.
+func NewRenderer(w io.Writer, isTTY bool, log func(msg string, keysAndValues ...any)) Renderer {
+	if isTTY {
+		return NewTTYRenderer(w)
+	}
+	return NewLogRenderer(log)
+}
+
+// TTYRenderer redraws a fixed-height, multi-line status block in place using
+// ANSI cursor-movement escapes. Use it when the output is a terminal.
+type TTYRenderer struct {
+	w    io.Writer
+	mu   sync.Mutex
+	rows int
+}
+
+// NewTTYRenderer returns a TTYRenderer that draws to w.
+func NewTTYRenderer(w io.Writer) *TTYRenderer {
+	return &TTYRenderer{w: w}
+}
+
+// Render implements Renderer.
+func (r *TTYRenderer) Render(s Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+	lines := renderLines(s)
+	fmt.Fprint(r.w, strings.Join(lines, "\n")+"\n")
+	r.rows = len(lines)
+}
+
+// Stop implements Renderer.
+func (r *TTYRenderer) Stop(s Snapshot) {
+	r.Render(s)
+}
+
+func (r *TTYRenderer) clear() {
+	for i := 0; i < r.rows; i++ {
+		fmt.Fprint(r.w, "\033[1A\033[2K")
+	}
+}
+
+func renderLines(s Snapshot) []string {
+	var bytesPerSec float64
+	if s.Elapsed > 0 {
+		bytesPerSec = float64(s.BytesScanned) / s.Elapsed.Seconds()
+	}
+
+	units := "-"
+	if len(s.RecentUnits) > 0 {
+		units = strings.Join(s.RecentUnits, ", ")
+	}
+
+	return []string{
+		fmt.Sprintf("elapsed: %s", s.Elapsed.Round(time.Second)),
+		fmt.Sprintf("chunks: %d  bytes: %d  throughput: %.0f B/s", s.ChunksScanned, s.BytesScanned, bytesPerSec),
+		fmt.Sprintf("verified: %d  unverified: %d", s.VerifiedSecrets, s.UnverifiedSecrets),
+		fmt.Sprintf("processing: %s", units),
+	}
+}
+
+// LogRenderer emits one log line per Render call instead of redrawing in
+// place. Use it when the output isn't a terminal (e.g. CI), where ANSI
+// cursor movement wouldn't render sensibly.
+type LogRenderer struct {
+	log func(msg string, keysAndValues ...any)
+}
+
+// NewLogRenderer returns a LogRenderer that calls log for each Snapshot.
+func NewLogRenderer(log func(msg string, keysAndValues ...any)) *LogRenderer {
+	return &LogRenderer{log: log}
+}
+
+// Render implements Renderer.
+func (r *LogRenderer) Render(s Snapshot) {
+	r.log("scan progress",
+		"chunks", s.ChunksScanned,
+		"bytes", s.BytesScanned,
+		"verified_secrets", s.VerifiedSecrets,
+		"unverified_secrets", s.UnverifiedSecrets,
+		"elapsed", s.Elapsed.Round(time.Second).String(),
+	)
+}
+
+// Stop implements Renderer.
+func (r *LogRenderer) Stop(s Snapshot) {
+	r.Render(s)
+}
+
+// Tracker accumulates recently processed unit names and periodically polls
+// for the scan's running counters, driving a Renderer until Stop is called.
+type Tracker struct {
+	renderer Renderer
+	interval time.Duration
+	start    time.Time
+	maxUnits int
+
+	mu          sync.Mutex
+	recentUnits []string
+}
+
+// NewTracker returns a Tracker that renders to renderer every interval,
+// retaining the maxUnits most recently observed unit names.
+func NewTracker(renderer Renderer, interval time.Duration, maxUnits int) *Tracker {
+	return &Tracker{renderer: renderer, interval: interval, start: time.Now(), maxUnits: maxUnits}
+}
+
+// ObserveUnit records unitName as the most recently processed source unit.
+func (t *Tracker) ObserveUnit(unitName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.recentUnits = append(t.recentUnits, unitName)
+	if overflow := len(t.recentUnits) - t.maxUnits; overflow > 0 {
+		t.recentUnits = t.recentUnits[overflow:]
+	}
+}
+
+// Counters is called by Run on every tick to get the scan's current totals.
+type Counters func() (chunks, bytesScanned, verified, unverified uint64)
+
+// Run renders a Snapshot from counters every interval until done is closed,
+// then draws one final Snapshot via Stop.
+func (t *Tracker) Run(done <-chan struct{}, counters Counters) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.renderer.Render(t.snapshot(counters))
+		case <-done:
+			t.renderer.Stop(t.snapshot(counters))
+			return
+		}
+	}
+}
+
+func (t *Tracker) snapshot(counters Counters) Snapshot {
+	chunks, bytesScanned, verified, unverified := counters()
+
+	t.mu.Lock()
+	units := make([]string, len(t.recentUnits))
+	copy(units, t.recentUnits)
+	t.mu.Unlock()
+
+	return Snapshot{
+		ChunksScanned:     chunks,
+		BytesScanned:      bytesScanned,
+		VerifiedSecrets:   verified,
+		UnverifiedSecrets: unverified,
+		RecentUnits:       units,
+		Elapsed:           time.Since(t.start),
+	}
+}
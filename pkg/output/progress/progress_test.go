@@ -0,0 +1,53 @@
+package progress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackerObserveUnitRetainsOnlyMostRecent(t *testing.T) {
+	tr := NewTracker(NewLogRenderer(func(string, ...any) {}), time.Second, 2)
+
+	tr.ObserveUnit("a")
+	tr.ObserveUnit("b")
+	tr.ObserveUnit("c")
+
+	got := tr.snapshot(func() (uint64, uint64, uint64, uint64) { return 0, 0, 0, 0 }).RecentUnits
+	want := []string{"b", "c"}
+	if len(got) != len(want) {
+		t.Fatalf("RecentUnits = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("RecentUnits = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestTrackerRunStopsOnDone(t *testing.T) {
+	var stopped bool
+	done := make(chan struct{})
+
+	renderer := &stubRenderer{onStop: func() { stopped = true }}
+	tr := NewTracker(renderer, time.Millisecond, 3)
+
+	finished := make(chan struct{})
+	go func() {
+		tr.Run(done, func() (uint64, uint64, uint64, uint64) { return 1, 2, 3, 4 })
+		close(finished)
+	}()
+
+	close(done)
+	<-finished
+
+	if !stopped {
+		t.Error("expected Tracker.Run to call Renderer.Stop when done is closed")
+	}
+}
+
+type stubRenderer struct {
+	onStop func()
+}
+
+func (s *stubRenderer) Render(Snapshot) {}
+func (s *stubRenderer) Stop(Snapshot)   { s.onStop() }
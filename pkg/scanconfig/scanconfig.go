@@ -0,0 +1,96 @@
+// Package scanconfig loads a declarative, multi-source scan document (YAML or
+// JSON) describing an ordered list of heterogeneous sources — git repos, a
+// GitHub org, an S3 bucket, a Docker image, and so on — so a single
+// `trufflehog scan --config scans.yaml` invocation can run them all against
+// one shared engine instead of requiring one invocation per source.
+package scanconfig
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/config"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// Document is the top-level shape of a scan config file.
+type Document struct {
+	Sources []SourceEntry `yaml:"sources"`
+	// VerifyPolicies overrides per-detector verification behavior (verify
+	// decision, rate limit, network scoping); see config.VerificationPolicy.
+	VerifyPolicies []config.DetectorPolicyEntry `yaml:"verify_policies,omitempty"`
+	// DetectorOverrides forces the verify decision for a detector, a whole
+	// version range of a detector, a detector group/tag, or every detector,
+	// in precedence order; see config.DetectorOverrides.
+	DetectorOverrides []config.OverrideEntry `yaml:"detector_overrides,omitempty"`
+}
+
+// VerificationPolicies resolves doc's VerifyPolicies entries into a
+// config.VerificationPolicies lookup.
+func (doc *Document) VerificationPolicies() (config.VerificationPolicies, error) {
+	return config.BuildVerificationPolicies(doc.VerifyPolicies)
+}
+
+// Overrides resolves doc's DetectorOverrides entries into a
+// config.DetectorOverrides lookup.
+func (doc *Document) Overrides() (*config.DetectorOverrides, error) {
+	return config.BuildDetectorOverrides(doc.DetectorOverrides)
+}
+
+// SourceEntry describes a single source to run as part of a Document. Type
+// selects which of the typed config blocks below is used; exactly one should
+// be set, matching Type.
+type SourceEntry struct {
+	// Name identifies this source in logs, metrics, and job reports. If
+	// unset, it is derived from Type and the source's position in the list.
+	Name string `yaml:"name"`
+	// Type selects the source runner, e.g. "git", "github", "s3".
+	Type string `yaml:"type"`
+
+	Git        *sources.GitConfig        `yaml:"git,omitempty"`
+	GitHub     *sources.GithubConfig     `yaml:"github,omitempty"`
+	S3         *sources.S3Config         `yaml:"s3,omitempty"`
+	Filesystem *sources.FilesystemConfig `yaml:"filesystem,omitempty"`
+	Docker     *sources.DockerConfig     `yaml:"docker,omitempty"`
+	GCS        *sources.GCSConfig        `yaml:"gcs,omitempty"`
+}
+
+// envVarPattern matches a ${VAR} reference so secrets can be kept out of the
+// config file itself.
+var envVarPattern = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// Load reads and parses the scan config file at path. Every ${VAR} reference
+// in the file is replaced with the value of the environment variable VAR
+// before parsing; an unset variable expands to an empty string. JSON is valid
+// YAML, so the same parser handles both.
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read scan config %q: %w", path, err)
+	}
+
+	doc := &Document{}
+	if err := yaml.Unmarshal(expandEnv(data), doc); err != nil {
+		return nil, fmt.Errorf("could not parse scan config %q: %w", path, err)
+	}
+
+	for i := range doc.Sources {
+		if doc.Sources[i].Name == "" {
+			doc.Sources[i].Name = fmt.Sprintf("%s-%d", doc.Sources[i].Type, i)
+		}
+	}
+
+	return doc, nil
+}
+
+// expandEnv replaces every ${VAR} reference in data with the value of the
+// environment variable VAR.
+func expandEnv(data []byte) []byte {
+	return envVarPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		name := envVarPattern.FindSubmatch(match)[1]
+		return []byte(os.Getenv(string(name)))
+	})
+}
@@ -0,0 +1,272 @@
+package sources
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CanonicalIDer is implemented by a SourceUnit that computes its own
+// CanonicalID rather than relying on CanonicalID's canonicalMarshal-of-the-
+// whole-value fallback - useful for a unit that carries fields (timestamps,
+// cached handles) that shouldn't affect its identity.
+type CanonicalIDer interface {
+	CanonicalID() [32]byte
+}
+
+// CanonicalID returns a deterministic content hash for unit: SHA-256 over
+// its canonicalMarshal encoding, so two units with the same exported fields
+// hash identically regardless of map/struct field order. If unit implements
+// CanonicalIDer, its own CanonicalID is used instead. A persistent job store
+// can use this to dedupe units across restarts and checkpoint progress by
+// hash instead of by the source's own (not necessarily stable) unit ID.
+func CanonicalID(unit SourceUnit) [32]byte {
+	if ider, ok := unit.(CanonicalIDer); ok {
+		return ider.CanonicalID()
+	}
+	data, err := canonicalMarshal(unit)
+	if err != nil {
+		// unit's own SourceUnitID is always a valid, if weaker, fallback
+		// identity - canonicalMarshal only fails on unsupported field kinds
+		// (e.g. a chan or func field), which no well-formed SourceUnit has.
+		return sha256.Sum256([]byte(unit.SourceUnitID()))
+	}
+	return sha256.Sum256(data)
+}
+
+// canonicalMarshal renders v as deterministic JSON, following the
+// canonicaljson-spec approach: object keys sorted by codepoint, strings
+// escaped with the minimum escapes required, and non-integer numbers written
+// in capital-E exponential notation with a significand in (-10, 10). Two
+// values that are equal produce byte-identical output regardless of struct
+// field order, map iteration order, or how a float happened to be written in
+// source - which is what lets CanonicalID use it as the input to a stable
+// content hash.
+//
+// Unlike encoding/json, canonicalMarshal walks struct fields directly rather
+// than round-tripping through an intermediate representation, so it respects
+// the same `json:"name,omitempty"` tags a type already declares for
+// encoding/json without requiring a second set of struct tags.
+func canonicalMarshal(v any) ([]byte, error) {
+	var b strings.Builder
+	if err := writeCanonical(&b, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+func writeCanonical(b *strings.Builder, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			b.WriteString("null")
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		b.WriteString("null")
+		return nil
+	case reflect.Bool:
+		if v.Bool() {
+			b.WriteString("true")
+		} else {
+			b.WriteString("false")
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b.WriteString(strconv.FormatUint(v.Uint(), 10))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		b.WriteString(canonicalFloat(v.Float()))
+		return nil
+	case reflect.String:
+		writeCanonicalString(b, v.String())
+		return nil
+	case reflect.Slice:
+		if v.IsNil() {
+			b.WriteString("null")
+			return nil
+		}
+		return writeCanonicalArray(b, v)
+	case reflect.Array:
+		return writeCanonicalArray(b, v)
+	case reflect.Map:
+		if v.IsNil() {
+			b.WriteString("null")
+			return nil
+		}
+		return writeCanonicalMap(b, v)
+	case reflect.Struct:
+		return writeCanonicalStruct(b, v)
+	default:
+		return fmt.Errorf("canonicaljson: unsupported kind %s", v.Kind())
+	}
+}
+
+// canonicalFloat formats f the way the canonicaljson spec does: an
+// integer-valued float is written without a fractional part or exponent;
+// anything else is written in capital-E exponential notation with its
+// significand normalized into (-10, 10), e.g. 1500.0 -> "1500",
+// 0.000015 -> "1.5E-5".
+func canonicalFloat(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		// Not representable in JSON; canonicalize to the closest sentinel
+		// a consumer can still hash deterministically.
+		return "null"
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	mantissa := strconv.FormatFloat(f, 'E', -1, 64)
+	// strconv's 'E' format already yields a one-digit-before-the-decimal
+	// significand (e.g. "1.5E+05"); canonicaljson drops the exponent's sign
+	// when positive and its leading zero padding.
+	parts := strings.SplitN(mantissa, "E", 2)
+	exp, _ := strconv.Atoi(parts[1])
+	return fmt.Sprintf("%sE%d", parts[0], exp)
+}
+
+// writeCanonicalString escapes s with the minimum escapes canonicaljson
+// requires - the two characters that would otherwise terminate the string
+// or an escape sequence, plus ASCII control characters - leaving every
+// other byte, including multi-byte UTF-8 sequences, untouched. This is
+// deliberately less aggressive than encoding/json's default, which also
+// escapes HTML-sensitive runes and some codepoints above U+007F.
+func writeCanonicalString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
+
+func writeCanonicalArray(b *strings.Builder, v reflect.Value) error {
+	b.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := writeCanonical(b, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	b.WriteByte(']')
+	return nil
+}
+
+func writeCanonicalMap(b *strings.Builder, v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("canonicaljson: unsupported map key type %s", v.Type().Key())
+	}
+	keys := v.MapKeys()
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.String()
+	}
+	sort.Strings(names)
+
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeCanonicalString(b, name)
+		b.WriteByte(':')
+		if err := writeCanonical(b, v.MapIndex(reflect.ValueOf(name))); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+func writeCanonicalStruct(b *strings.Builder, v reflect.Value) error {
+	type field struct {
+		name string
+		val  reflect.Value
+	}
+	var fields []field
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" { // unexported
+			continue
+		}
+		name, omitempty, skip := jsonFieldName(sf)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+		if omitempty && fv.IsZero() {
+			continue
+		}
+		fields = append(fields, field{name: name, val: fv})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeCanonicalString(b, f.name)
+		b.WriteByte(':')
+		if err := writeCanonical(b, f.val); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+// jsonFieldName mirrors encoding/json's struct tag handling closely enough
+// for canonicalMarshal to honor a type's existing `json:"..."` tags: a
+// `json:"-"` field is skipped, an explicit name overrides the Go field name,
+// and `,omitempty` is recognized.
+func jsonFieldName(sf reflect.StructField) (name string, omitempty, skip bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	if tag == "" {
+		return sf.Name, false, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = sf.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
@@ -0,0 +1,82 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+)
+
+type canonicalSample struct {
+	Z      string         `json:"z"`
+	A      int            `json:"a"`
+	Skip   string         `json:"-"`
+	Empty  string         `json:"empty,omitempty"`
+	Nested map[string]int `json:"nested"`
+}
+
+func TestCanonicalMarshalKeyOrderIndependent(t *testing.T) {
+	s1 := canonicalSample{Z: "hi", A: 1, Nested: map[string]int{"b": 2, "a": 1}}
+	s2 := canonicalSample{Z: "hi", A: 1, Nested: map[string]int{"a": 1, "b": 2}}
+
+	d1, err := canonicalMarshal(s1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2, err := canonicalMarshal(s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(d1) != string(d2) {
+		t.Fatalf("canonicalMarshal not independent of map order: %s vs %s", d1, d2)
+	}
+}
+
+func TestCanonicalMarshalTags(t *testing.T) {
+	s := canonicalSample{Z: "hi", A: 1, Skip: "nope"}
+	got, err := canonicalMarshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"a":1,"nested":null,"z":"hi"}`
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCanonicalFloat(t *testing.T) {
+	testCases := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0"},
+		{1500, "1500"},
+		{-3, "-3"},
+		{0.000015, "1.5E-5"},
+	}
+	for _, tc := range testCases {
+		if got := canonicalFloat(tc.in); got != tc.want {
+			t.Errorf("canonicalFloat(%v) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCanonicalStringEscaping(t *testing.T) {
+	var b strings.Builder
+	writeCanonicalString(&b, "hi \"there\"\n\tworld")
+	want := `"hi \"there\"\n\tworld"`
+	if b.String() != want {
+		t.Errorf("got %s, want %s", b.String(), want)
+	}
+}
+
+func TestCanonicalIDStableAcrossFieldOrder(t *testing.T) {
+	a := CommonSourceUnit{ID: "repo@abc"}
+	b := CommonSourceUnit{ID: "repo@abc"}
+	c := CommonSourceUnit{ID: "repo@def"}
+
+	if CanonicalID(a) != CanonicalID(b) {
+		t.Error("identical units produced different CanonicalIDs")
+	}
+	if CanonicalID(a) == CanonicalID(c) {
+		t.Error("distinct units produced the same CanonicalID")
+	}
+}
@@ -0,0 +1,66 @@
+package sources
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SourceUnitCodec marshals and unmarshals a SourceUnit for one wire format,
+// identified by a MIME content type. It's a superset of
+// SourceUnitUnmarshaller: anything implementing SourceUnitCodec already
+// satisfies SourceUnitUnmarshaller, so a Source embedding a registered codec
+// (the way CommonSourceUnitUnmarshaller is embedded today) needs no change
+// to keep passing an existing `var _ SourceUnitUnmarshaller = ...` assertion.
+//
+// A high-throughput enumerator - one that can produce millions of units,
+// where per-unit JSON parsing overhead adds up - registers a denser codec
+// under its own content type via RegisterSourceUnitCodec and negotiates it
+// with whatever's consuming the unit stream, instead of being stuck with
+// CommonSourceUnitUnmarshaller's JSON.
+type SourceUnitCodec interface {
+	// ContentType returns the MIME type this codec reads and writes, e.g.
+	// "application/json" or "application/x-protobuf".
+	ContentType() string
+	MarshalSourceUnit(unit SourceUnit) ([]byte, error)
+	UnmarshalSourceUnit(data []byte) (SourceUnit, error)
+}
+
+var (
+	sourceUnitCodecsMu sync.RWMutex
+	sourceUnitCodecs   = make(map[string]SourceUnitCodec)
+)
+
+// RegisterSourceUnitCodec registers codec under its own ContentType(), so
+// SourceUnitCodecFor(codec.ContentType()) finds it later. Call it during
+// package initialization, before any content-type negotiation happens.
+// Registering a second codec under a content type that's already taken
+// replaces the first, the same as a later RegisterSourceUnitKind call would.
+func RegisterSourceUnitCodec(codec SourceUnitCodec) {
+	sourceUnitCodecsMu.Lock()
+	defer sourceUnitCodecsMu.Unlock()
+	sourceUnitCodecs[codec.ContentType()] = codec
+}
+
+// SourceUnitCodecFor looks up the codec registered for contentType.
+func SourceUnitCodecFor(contentType string) (SourceUnitCodec, error) {
+	sourceUnitCodecsMu.RLock()
+	defer sourceUnitCodecsMu.RUnlock()
+	codec, ok := sourceUnitCodecs[contentType]
+	if !ok {
+		return nil, fmt.Errorf("no source unit codec registered for content type %q", contentType)
+	}
+	return codec, nil
+}
+
+// SourceUnitContentTypes returns the MIME types with a codec currently
+// registered, for a caller negotiating which one to use with its peer (e.g.
+// a job queue picking the densest format both producer and consumer share).
+func SourceUnitContentTypes() []string {
+	sourceUnitCodecsMu.RLock()
+	defer sourceUnitCodecsMu.RUnlock()
+	types := make([]string, 0, len(sourceUnitCodecs))
+	for t := range sourceUnitCodecs {
+		types = append(types, t)
+	}
+	return types
+}
@@ -6,13 +6,20 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
+	"path"
+	"runtime"
 	"strings"
+	"sync"
 
+	dockerclient "github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
@@ -22,25 +29,44 @@ import (
 	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
 )
 
+// SourceType is the type of this source, used for matching source types in
+// configuration and job input.
+const SourceType = sourcespb.SourceType_SOURCE_TYPE_DOCKER
+
 type Source struct {
-	name     string
-	sourceId int64
-	jobId    int64
-	verify   bool
-	conn     sourcespb.Docker
+	name            string
+	sourceId        int64
+	jobId           int64
+	verify          bool
+	concurrency     int
+	conn            sourcespb.Docker
+	exclusionConfig *sources.ExclusionConfig
 	sources.Progress
 }
 
 var FilesizeLimitBytes int64 = 10 * 1024 * 1024 // 10MB
 
+// defaultExclusionConfig, set via SetDefaultExclusionConfig, is applied to
+// every Source initialized afterward by Init. There's no path from the
+// CLI's --exclusion-config flag down to each individual Source's
+// construction deep inside the engine, so - like git's equivalent global
+// default - this is configured once up front rather than threaded through
+// as an Init parameter.
+var defaultExclusionConfig *sources.ExclusionConfig
+
+// SetDefaultExclusionConfig sets the exclusion config that Init installs on
+// every Source initialized afterward. Call it once during startup, before
+// any sources are constructed.
+func SetDefaultExclusionConfig(cfg *sources.ExclusionConfig) {
+	defaultExclusionConfig = cfg
+}
+
 // Ensure the Source satisfies the interface at compile time.
 var _ sources.Source = (*Source)(nil)
 
 // Type returns the type of source.
 // It is used for matching source types in configuration and job input.
-func (s *Source) Type() sourcespb.SourceType {
-	return sourcespb.SourceType_SOURCE_TYPE_DOCKER
-}
+func (s *Source) Type() sourcespb.SourceType { return SourceType }
 
 func (s *Source) SourceID() int64 {
 	return s.sourceId
@@ -56,6 +82,8 @@ func (s *Source) Init(_ context.Context, name string, jobId, sourceId int64, ver
 	s.sourceId = sourceId
 	s.jobId = jobId
 	s.verify = verify
+	s.concurrency = concurrency
+	s.exclusionConfig = defaultExclusionConfig
 
 	if err := anypb.UnmarshalTo(connection, &s.conn, proto.UnmarshalOptions{}); err != nil {
 		return fmt.Errorf("error unmarshalling connection: %w", err)
@@ -65,111 +93,536 @@ func (s *Source) Init(_ context.Context, name string, jobId, sourceId int64, ver
 }
 
 // Chunks emits data over a channel that is decoded and scanned for secrets.
+//
+// When an image reference resolves to an OCI image index / Docker manifest
+// list, each platform-specific sub-manifest selected by the configured
+// platform selectors (see platformSelectors) is scanned as its own chunk
+// stream, with the platform recorded on each chunk's SourceMetadata so
+// results can be attributed per architecture.
+//
+// Layers are extracted concurrently, bounded by the concurrency given to
+// Init, and deduplicated by digest across every image in s.conn.GetImages():
+// base layers shared between images (e.g. a common "FROM" ancestor) are only
+// read and scanned once per run.
 func (s *Source) Chunks(ctx context.Context, chunksChan chan *sources.Chunk) error {
 	remoteOpts, err := s.remoteOpts()
 	if err != nil {
 		return err
 	}
 
+	allPlatforms := s.conn.GetAllPlatforms()
+	var selectors []v1.Platform
+	if !allPlatforms {
+		selectors, err = s.platformSelectors()
+		if err != nil {
+			return err
+		}
+	}
+
+	workers := s.concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(workers)
+	var seenLayers sync.Map
+
 	for _, image := range s.conn.GetImages() {
-		var img v1.Image
-		var err error
-		var base, tag string
-
-		if strings.HasPrefix(image, "file://") {
-			image = strings.TrimPrefix(image, "file://")
-			base = image
-			img, err = tarball.ImageFromPath(image, nil)
+		switch {
+		case strings.HasPrefix(image, "file://"):
+			path := strings.TrimPrefix(image, "file://")
+			img, err := tarball.ImageFromPath(path, nil)
 			if err != nil {
 				return err
 			}
-		} else {
-			base, tag = baseAndTagFromImage(image)
-			imageName, err := name.NewTag(image)
+			if err := s.chunkImage(ctx, g, &seenLayers, img, path, "", nil, chunksChan); err != nil {
+				return err
+			}
+			continue
+
+		case strings.HasPrefix(image, "docker-daemon://"):
+			ref := strings.TrimPrefix(image, "docker-daemon://")
+			base, tag := baseAndTagFromImage(ref)
+			imageName, err := name.ParseReference(ref)
+			if err != nil {
+				return err
+			}
+
+			daemonOpts, err := s.daemonOpts()
 			if err != nil {
 				return err
 			}
+			img, err := daemon.Image(imageName, daemonOpts...)
+			if err != nil {
+				return err
+			}
+			if err := s.chunkImage(ctx, g, &seenLayers, img, base, tag, nil, chunksChan); err != nil {
+				return err
+			}
+			continue
 
-			img, err = remote.Image(imageName, remoteOpts...)
+		case strings.HasPrefix(image, "oci://"), strings.HasPrefix(image, "oci-layout://"):
+			path := strings.TrimPrefix(strings.TrimPrefix(image, "oci-layout://"), "oci://")
+			base, tag := baseAndTagFromImage(path)
+
+			idx, err := layout.ImageIndexFromPath(path)
 			if err != nil {
 				return err
 			}
+			if err := s.chunkIndex(ctx, g, &seenLayers, idx, base, tag, selectors, allPlatforms, chunksChan); err != nil {
+				return err
+			}
+			continue
+		}
+
+		base, tag := baseAndTagFromImage(image)
+		imageName, err := name.NewTag(image)
+		if err != nil {
+			return err
 		}
 
-		layers, err := img.Layers()
+		desc, err := remote.Get(imageName, remoteOpts...)
 		if err != nil {
 			return err
 		}
 
-		for _, layer := range layers {
-			digest, err := layer.Digest()
+		if !desc.MediaType.IsIndex() {
+			img, err := desc.Image()
 			if err != nil {
 				return err
 			}
+			if err := s.chunkImage(ctx, g, &seenLayers, img, base, tag, nil, chunksChan); err != nil {
+				return err
+			}
+			continue
+		}
+
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return err
+		}
+		if err := s.chunkIndex(ctx, g, &seenLayers, idx, base, tag, selectors, allPlatforms, chunksChan); err != nil {
+			return err
+		}
+	}
+
+	return g.Wait()
+}
+
+// chunkIndex scans every platform-specific sub-manifest of idx selected by
+// selectors (or all of them, if allPlatforms), shared by the registry and
+// OCI-layout dispatch paths since both can resolve to a multi-arch index.
+//
+// If selectors is non-empty and none of idx's manifests match any of them,
+// that's reported as an error rather than a silent zero-chunk scan, since
+// it's almost always a typo'd --platform value rather than an intentional
+// "scan nothing" - the error lists the platforms indexManifest actually
+// offers so the caller can correct it.
+func (s *Source) chunkIndex(ctx context.Context, g *errgroup.Group, seenLayers *sync.Map, idx v1.ImageIndex, base, tag string, selectors []v1.Platform, allPlatforms bool, chunksChan chan *sources.Chunk) error {
+	indexManifest, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+
+	matched := 0
+	var available []string
+	for _, m := range indexManifest.Manifests {
+		if m.Platform == nil {
+			continue
+		}
+		available = append(available, m.Platform.String())
+
+		if !allPlatforms && !platformMatches(selectors, *m.Platform) {
+			continue
+		}
+		matched++
+
+		img, err := idx.Image(m.Digest)
+		if err != nil {
+			return err
+		}
+		if err := s.chunkImage(ctx, g, seenLayers, img, base, tag, m.Platform, chunksChan); err != nil {
+			return err
+		}
+	}
+
+	if matched == 0 && !allPlatforms && len(selectors) > 0 {
+		return fmt.Errorf("image %s: no manifest matches the configured platform(s); available: %s", base, strings.Join(available, ", "))
+	}
+	return nil
+}
+
+// chunkImage queues a goroutine per layer of img onto g, tagging each
+// chunk's SourceMetadata with base/tag and, for a sub-manifest of a
+// multi-architecture image, the platform it was selected for. A layer whose
+// digest is already present in seenLayers (queued by an earlier image in
+// this run) is skipped rather than read and scanned again.
+//
+// Before any layer is scanned, resolveEffectiveFiles replays img's full
+// layer stack to work out which layer actually owns each surviving path,
+// honoring OverlayFS/AUFS-style whiteout files - a path deleted by a ".wh."
+// marker (or wiped by a ".wh..wh..opq" opaque-directory marker) in a higher
+// layer is excluded from every lower layer it would otherwise be scanned
+// from, so a file removed in, say, a later "RUN rm" build step isn't
+// reported as still present just because an earlier layer still contains
+// its bytes.
+//
+// Layers are queued rather than awaited here: chunkImage returns once every
+// layer of img has been handed to g, and the caller's final g.Wait() is what
+// actually blocks for completion, so layers from different images can be
+// in flight at once up to the pool's concurrency limit.
+//
+// Known limitation: seenLayers dedup (see Chunks) and per-image whiteout
+// resolution both key off a layer's raw digest, but which paths within a
+// shared layer survive is a property of the image stacking on top of it,
+// which can differ between images. Once a layer's digest has been scanned
+// for one image, a later image sharing that digest will not be rescanned
+// under its own whiteout resolution, even if that image deletes files the
+// first one kept. This trades a small amount of attribution accuracy for
+// avoiding redundant work on shared base layers, which is the more common
+// case in practice.
+func (s *Source) chunkImage(ctx context.Context, g *errgroup.Group, seenLayers *sync.Map, img v1.Image, base, tag string, platform *v1.Platform, chunksChan chan *sources.Chunk) error {
+	if err := s.chunkConfig(img, base, tag, platform, chunksChan); err != nil {
+		return err
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+
+	keepByDigest, err := resolveEffectiveFiles(layers)
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range layers {
+		layer := layer
+		digest, err := layer.Digest()
+		if err != nil {
+			return err
+		}
+		digestStr := digest.String()
+
+		if _, loaded := seenLayers.LoadOrStore(digestStr, struct{}{}); loaded {
+			continue
+		}
+
+		keep := keepByDigest[digestStr]
+		g.Go(func() error {
+			return s.chunkLayer(ctx, layer, digestStr, base, tag, platform, keep, chunksChan)
+		})
+	}
+
+	return nil
+}
+
+// chunkConfig emits the image's raw manifest and config JSON, plus one chunk
+// per build-history entry, as chunks of their own - secrets commonly leak
+// into these rather than (or in addition to) a layer's filesystem: an ENV or
+// LABEL baked into the config, or an ARG/RUN command's literal value baked
+// into history[].created_by (e.g. `ARG AWS_SECRET_ACCESS_KEY=...` or
+// `RUN curl -H "Authorization: ..."`). Each gets a synthetic File name
+// (e.g. "<config>", "<manifest>", "<history:2>") since these aren't paths
+// within any layer's filesystem.
+func (s *Source) chunkConfig(img v1.Image, base, tag string, platform *v1.Platform, chunksChan chan *sources.Chunk) error {
+	send := func(file string, data []byte) {
+		chunksChan <- &sources.Chunk{
+			SourceType: s.Type(),
+			SourceName: s.name,
+			SourceID:   s.SourceID(),
+			Data:       data,
+			SourceMetadata: &source_metadatapb.MetaData{
+				Data: &source_metadatapb.MetaData_Docker{
+					Docker: &source_metadatapb.Docker{
+						File:     file,
+						Image:    base,
+						Tag:      tag,
+						Platform: platformString(platform),
+					},
+				},
+			},
+			Verify: s.verify,
+		}
+	}
+
+	rawManifest, err := img.RawManifest()
+	if err != nil {
+		return err
+	}
+	send("<manifest>", rawManifest)
 
+	rawConfig, err := img.RawConfigFile()
+	if err != nil {
+		return err
+	}
+	send("<config>", rawConfig)
+
+	config, err := img.ConfigFile()
+	if err != nil {
+		return err
+	}
+	for i, h := range config.History {
+		if h.CreatedBy == "" {
+			continue
+		}
+		send(fmt.Sprintf("<history:%d>", i), []byte(h.CreatedBy))
+	}
+
+	return nil
+}
+
+// whiteoutPrefix marks an OverlayFS/AUFS whiteout entry: a file named
+// whiteoutPrefix+name in a directory means name was deleted from that
+// directory in a lower layer.
+const whiteoutPrefix = ".wh."
+
+// opaqueWhiteout marks an entire directory's pre-existing (lower-layer)
+// contents as deleted; entries the same layer adds alongside it still apply.
+const opaqueWhiteout = whiteoutPrefix + whiteoutPrefix + ".opq"
+
+// resolveEffectiveFiles replays layers bottom-to-top (the order img.Layers()
+// already returns them in) and returns, for each layer's digest, the set of
+// tar entry paths within it that survive to the image's final filesystem.
+// Only tar headers are read here, not file bodies, since this is a
+// bookkeeping pass ahead of the real, parallel scan in chunkLayer.
+func resolveEffectiveFiles(layers []v1.Layer) (map[string]map[string]bool, error) {
+	// owner maps a surviving path to the digest of the layer currently
+	// believed to provide it; a later layer's entry, or whiteout, replaces
+	// or removes that mapping as layers are replayed in order.
+	owner := make(map[string]string)
+
+	for _, layer := range layers {
+		digest, err := layer.Digest()
+		if err != nil {
+			return nil, err
+		}
+		digestStr := digest.String()
+
+		if err := func() error {
 			rc, err := layer.Compressed()
 			if err != nil {
 				return err
 			}
-
 			defer rc.Close()
 
 			gzipReader, err := gzip.NewReader(rc)
 			if err != nil {
 				return err
 			}
-
 			defer gzipReader.Close()
 
 			tarReader := tar.NewReader(gzipReader)
-
 			for {
 				header, err := tarReader.Next()
 				if err == io.EOF {
-					break // End of archive
+					return nil
 				}
 				if err != nil {
 					return err
 				}
 
-				// Skip files larger than FilesizeLimitBytes
-				if header.Size > FilesizeLimitBytes {
-					continue
+				name := path.Clean("/" + header.Name)
+				dir, base := path.Dir(name), path.Base(name)
+
+				switch {
+				case base == opaqueWhiteout:
+					for p := range owner {
+						if p == dir || strings.HasPrefix(p, dir+"/") {
+							delete(owner, p)
+						}
+					}
+				case strings.HasPrefix(base, whiteoutPrefix):
+					deleted := path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix))
+					for p := range owner {
+						if p == deleted || strings.HasPrefix(p, deleted+"/") {
+							delete(owner, p)
+						}
+					}
+				default:
+					owner[name] = digestStr
 				}
+			}
+		}(); err != nil {
+			return nil, err
+		}
+	}
+
+	keep := make(map[string]map[string]bool, len(layers))
+	for p, digestStr := range owner {
+		if keep[digestStr] == nil {
+			keep[digestStr] = make(map[string]bool)
+		}
+		keep[digestStr][p] = true
+	}
+	return keep, nil
+}
 
-				file := bytes.NewBuffer(nil)
+// shouldSkipEntry reports whether a tar entry's path should be skipped
+// outright based on the configured BlacklistedPaths, BlacklistedExtensions,
+// and BlacklistedStrings - vendored binaries, fonts, and known noisy system
+// directories are the common case this avoids wasted work and false-positive
+// risk on.
+func (s *Source) shouldSkipEntry(name string) bool {
+	for _, p := range s.conn.GetBlacklistedPaths() {
+		p = path.Clean("/" + p)
+		if name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
 
-				_, err = io.Copy(file, tarReader)
-				if err != nil {
-					return err
-				}
+	ext := path.Ext(name)
+	for _, blocked := range s.conn.GetBlacklistedExtensions() {
+		if !strings.HasPrefix(blocked, ".") {
+			blocked = "." + blocked
+		}
+		if strings.EqualFold(ext, blocked) {
+			return true
+		}
+	}
 
-				chunk := &sources.Chunk{
-					SourceType: s.Type(),
-					SourceName: s.name,
-					SourceID:   s.SourceID(),
-					Data:       file.Bytes(),
-					SourceMetadata: &source_metadatapb.MetaData{
-						Data: &source_metadatapb.MetaData_Docker{
-							Docker: &source_metadatapb.Docker{
-								File:  header.Name,
-								Image: base,
-								Tag:   tag,
-								Layer: digest.String(),
-							},
-						},
+	for _, sub := range s.conn.GetBlacklistedStrings() {
+		if sub != "" && strings.Contains(name, sub) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// chunkLayer streams layer's contents and emits one chunk per file within
+// it, skipping files larger than FilesizeLimitBytes, files excluded by the
+// configured blacklists, and - when keep is non-nil - paths that don't
+// survive to the image's effective filesystem (see resolveEffectiveFiles).
+func (s *Source) chunkLayer(ctx context.Context, layer v1.Layer, digest, base, tag string, platform *v1.Platform, keep map[string]bool, chunksChan chan *sources.Chunk) error {
+	rc, err := layer.Compressed()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	gzipReader, err := gzip.NewReader(rc)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break // End of archive
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean("/" + header.Name)
+
+		if keep != nil && !keep[name] {
+			continue // overwritten or deleted by a higher layer
+		}
+		if s.shouldSkipEntry(name) {
+			continue
+		}
+		if s.exclusionConfig.ShouldSkipPath(name) {
+			continue
+		}
+
+		// Skip files larger than FilesizeLimitBytes
+		if header.Size > FilesizeLimitBytes {
+			continue
+		}
+
+		file := bytes.NewBuffer(nil)
+
+		_, err = io.Copy(file, tarReader)
+		if err != nil {
+			return err
+		}
+
+		if s.exclusionConfig.ShouldSkipData(file.Bytes()) {
+			continue
+		}
+
+		chunk := &sources.Chunk{
+			SourceType: s.Type(),
+			SourceName: s.name,
+			SourceID:   s.SourceID(),
+			Data:       file.Bytes(),
+			SourceMetadata: &source_metadatapb.MetaData{
+				Data: &source_metadatapb.MetaData_Docker{
+					Docker: &source_metadatapb.Docker{
+						File:     header.Name,
+						Image:    base,
+						Tag:      tag,
+						Layer:    digest,
+						Platform: platformString(platform),
 					},
-					Verify: s.verify,
-				}
+				},
+			},
+			Verify: s.verify,
+		}
 
-				chunksChan <- chunk
-			}
+		select {
+		case chunksChan <- chunk:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 
 	return nil
 }
 
+// platformSelectors returns the platforms Chunks should select sub-manifests
+// for. If the user configured explicit platforms, those are parsed and
+// returned; otherwise the host's own platform is used, preserving the
+// pre-multi-arch behavior of scanning a single, host-appropriate manifest.
+func (s *Source) platformSelectors() ([]v1.Platform, error) {
+	platforms := s.conn.GetPlatforms()
+	if len(platforms) == 0 {
+		return []v1.Platform{{OS: runtime.GOOS, Architecture: runtime.GOARCH}}, nil
+	}
+
+	selectors := make([]v1.Platform, 0, len(platforms))
+	for _, p := range platforms {
+		platform, err := v1.ParsePlatform(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid platform %q: %w", p, err)
+		}
+		selectors = append(selectors, *platform)
+	}
+	return selectors, nil
+}
+
+// platformMatches reports whether p satisfies at least one selector. A
+// selector field left blank matches any value for that field.
+func platformMatches(selectors []v1.Platform, p v1.Platform) bool {
+	for _, sel := range selectors {
+		if sel.OS != "" && sel.OS != p.OS {
+			continue
+		}
+		if sel.Architecture != "" && sel.Architecture != p.Architecture {
+			continue
+		}
+		if sel.Variant != "" && sel.Variant != p.Variant {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// platformString formats platform for SourceMetadata, returning "" when
+// platform is nil (a single-manifest image with no per-arch attribution).
+func platformString(platform *v1.Platform) string {
+	if platform == nil {
+		return ""
+	}
+	return platform.String()
+}
+
 func baseAndTagFromImage(image string) (base, tag string) {
 	regRepoDelimiter := "/"
 	tagDelim := ":"
@@ -211,3 +664,21 @@ func (s *Source) remoteOpts() ([]remote.Option, error) {
 		return nil, fmt.Errorf("unknown credential type: %T", s.conn.Credential)
 	}
 }
+
+// daemonOpts returns the daemon.Option set used to pull a docker-daemon://
+// image. An image already loaded into a local daemon is read through the
+// daemon's own socket rather than an authenticated registry pull, so no
+// keychain is needed here - DaemonSocket only needs to be set to point at a
+// non-default daemon (e.g. a rootless or remote Docker context); otherwise
+// go-containerregistry dials the environment's default Docker socket.
+func (s *Source) daemonOpts() ([]daemon.Option, error) {
+	opts := []daemon.Option{daemon.WithUnbufferedOpener()}
+	if socket := s.conn.GetDaemonSocket(); socket != "" {
+		cli, err := dockerclient.NewClientWithOpts(dockerclient.WithHost(socket), dockerclient.WithAPIVersionNegotiation())
+		if err != nil {
+			return nil, fmt.Errorf("could not create docker client for socket %q: %w", socket, err)
+		}
+		opts = append(opts, daemon.WithClient(cli))
+	}
+	return opts, nil
+}
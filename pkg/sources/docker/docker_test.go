@@ -0,0 +1,159 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+func TestShouldSkipEntry(t *testing.T) {
+	s := &Source{}
+	s.conn.BlacklistedPaths = []string{"/proc", "/usr/lib"}
+	s.conn.BlacklistedExtensions = []string{"so", ".jar"}
+	s.conn.BlacklistedStrings = []string{"vendor/"}
+
+	tests := map[string]struct {
+		path string
+		want bool
+	}{
+		"exact blacklisted dir":                  {"/proc", true},
+		"file under blacklisted dir":             {"/proc/1/status", true},
+		"file under second blacklist":            {"/usr/lib/libc.so", true},
+		"extension without leading dot":          {"/opt/app/libfoo.so", true},
+		"extension with leading dot":             {"/opt/app/app.jar", true},
+		"blacklisted substring":                  {"/opt/app/vendor/pkg/file.go", true},
+		"unrelated file":                         {"/opt/app/main.go", false},
+		"similarly prefixed dir not blacklisted": {"/proceedings/readme.txt", false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := s.shouldSkipEntry(tc.path); got != tc.want {
+				t.Errorf("shouldSkipEntry(%q) = %v, want %v", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveEffectiveFiles(t *testing.T) {
+	lower := newTarLayer(t, map[string]string{
+		"/app/keep.txt":    "lower",
+		"/app/deleted.txt": "lower",
+		"/app/dir/a.txt":   "lower",
+	})
+	upper := newTarLayer(t, map[string]string{
+		"/app/dir/.wh..wh..opq": "",
+		"/app/dir/b.txt":        "upper",
+		"/app/.wh.deleted.txt":  "",
+	})
+
+	keep, err := resolveEffectiveFiles([]v1.Layer{lower, upper})
+	if err != nil {
+		t.Fatalf("resolveEffectiveFiles: %v", err)
+	}
+
+	lowerDigest, _ := lower.Digest()
+	upperDigest, _ := upper.Digest()
+
+	if !keep[lowerDigest.String()]["/app/keep.txt"] {
+		t.Errorf("expected /app/keep.txt to survive from the lower layer")
+	}
+	if keep[lowerDigest.String()]["/app/deleted.txt"] {
+		t.Errorf("expected /app/deleted.txt to be removed by the whiteout in the upper layer")
+	}
+	if keep[lowerDigest.String()]["/app/dir/a.txt"] {
+		t.Errorf("expected /app/dir/a.txt to be removed by the opaque marker in the upper layer")
+	}
+	if !keep[upperDigest.String()]["/app/dir/b.txt"] {
+		t.Errorf("expected /app/dir/b.txt from the upper layer to survive")
+	}
+}
+
+func TestChunkConfigEmitsManifestConfigAndHistory(t *testing.T) {
+	img, err := mutate.ConfigFile(empty.Image, &v1.ConfigFile{
+		Config: v1.Config{
+			Env:    []string{"AWS_SECRET_ACCESS_KEY=leaked-env-secret"},
+			Labels: map[string]string{"com.example.token": "leaked-label-secret"},
+		},
+		History: []v1.History{
+			{CreatedBy: "ARG AWS_SECRET_ACCESS_KEY=leaked-history-secret"},
+			{CreatedBy: ""}, // blank entries shouldn't produce a chunk
+		},
+	})
+	if err != nil {
+		t.Fatalf("building crafted image: %v", err)
+	}
+
+	s := &Source{name: "test", verify: false}
+	chunksChan := make(chan *sources.Chunk, 10)
+	if err := s.chunkConfig(img, "example/image", "latest", nil, chunksChan); err != nil {
+		t.Fatalf("chunkConfig: %v", err)
+	}
+	close(chunksChan)
+
+	var files []string
+	var sawEnvSecret, sawLabelSecret, sawHistorySecret bool
+	for chunk := range chunksChan {
+		files = append(files, chunk.SourceMetadata.GetDocker().GetFile())
+		data := string(chunk.Data)
+		if strings.Contains(data, "leaked-env-secret") {
+			sawEnvSecret = true
+		}
+		if strings.Contains(data, "leaked-label-secret") {
+			sawLabelSecret = true
+		}
+		if strings.Contains(data, "leaked-history-secret") {
+			sawHistorySecret = true
+		}
+	}
+
+	if !sawEnvSecret || !sawLabelSecret {
+		t.Errorf("expected the <config> chunk to carry ENV and LABEL secrets, files: %v", files)
+	}
+	if !sawHistorySecret {
+		t.Errorf("expected a <history:N> chunk for the ARG build-history entry, files: %v", files)
+	}
+}
+
+// newTarLayer builds an in-memory gzipped tar layer from files, for testing
+// resolveEffectiveFiles without needing a real registry or daemon image.
+func newTarLayer(t *testing.T, files map[string]string) v1.Layer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(body))}); err != nil {
+			t.Fatalf("writing tar header for %s: %v", name, err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatalf("writing tar body for %s: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("closing tar writer: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("closing gzip writer: %v", err)
+	}
+
+	data := buf.Bytes()
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+	if err != nil {
+		t.Fatalf("building layer from opener: %v", err)
+	}
+	return layer
+}
@@ -0,0 +1,179 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CategoryStats summarizes every error that canonicalized to the same
+// signature: how many times it occurred, one representative message, and
+// which tags (source/unit context passed to Add) it was seen under.
+type CategoryStats struct {
+	Count   uint64   `json:"count"`
+	Example string   `json:"example"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// scanErrorBucket is CategoryStats' mutable, in-progress counterpart kept
+// behind ScanErrors' mutex while a scan is still running.
+type scanErrorBucket struct {
+	count   uint64
+	example string
+	tags    map[string]struct{}
+}
+
+// volatileSubstr matches the parts of an error message that vary between
+// otherwise-identical failures - hex object hashes, hex pointers, absolute
+// paths, and bare numbers - so canonicalSignature can fold "object a1b2c3
+// not found" and "object d4e5f6 not found" into the same bucket instead of
+// one each.
+var volatileSubstr = regexp.MustCompile(`0x[0-9a-fA-F]+|\b[0-9a-fA-F]{7,40}\b|/[^\s:]+|\d+`)
+
+// canonicalSignature reduces err to a signature that's stable across
+// otherwise-identical failures, so ScanErrors.Add can dedupe by it.
+func canonicalSignature(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	msg := fmt.Sprintf("%T: %s", err, err.Error())
+	return volatileSubstr.ReplaceAllString(msg, "#")
+}
+
+// ScanErrors aggregates the errors a scan's units (repos, projects,
+// objects) encounter, deduplicating by canonicalSignature instead of
+// keeping every occurrence - a large scan that fails the same way
+// thousands of times (a permission-denied directory, a missing object)
+// reports that once, with a count, rather than flooding a human-readable
+// dump or --json report with near-identical entries.
+type ScanErrors struct {
+	mu      sync.Mutex
+	total   uint64
+	order   []string // signatures, in first-seen order, for deterministic reporting
+	buckets map[string]*scanErrorBucket
+}
+
+// NewScanErrors creates a ScanErrors. projects, if given, sizes the
+// underlying map for roughly that many distinct failure signatures - a
+// loose hint, not a hard cap; a scan that fails in more distinct ways than
+// projects simply grows the map like any other would. It's variadic so a
+// caller that doesn't know a useful hint up front can call NewScanErrors()
+// with none.
+func NewScanErrors(projects ...int) *ScanErrors {
+	hint := 0
+	if len(projects) > 0 {
+		hint = projects[0]
+	}
+	return &ScanErrors{buckets: make(map[string]*scanErrorBucket, hint)}
+}
+
+// Add records err, tagged with optional source/unit context (e.g. a repo
+// name or project ID), folding it into the bucket for its canonicalized
+// signature. A nil err is still counted, under its own "<nil>" signature,
+// matching the historical behavior of treating every Add call as one
+// failure regardless of whether the caller had a concrete error to attach.
+func (se *ScanErrors) Add(err error, tags ...string) {
+	sig := canonicalSignature(err)
+
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	se.total++
+	bucket, ok := se.buckets[sig]
+	if !ok {
+		bucket = &scanErrorBucket{tags: make(map[string]struct{})}
+		se.buckets[sig] = bucket
+		se.order = append(se.order, sig)
+	}
+	bucket.count++
+	if bucket.example == "" && err != nil {
+		bucket.example = err.Error()
+	}
+	for _, tag := range tags {
+		bucket.tags[tag] = struct{}{}
+	}
+}
+
+// Count returns the total number of errors Add has recorded, across every
+// category.
+func (se *ScanErrors) Count() uint64 {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	return se.total
+}
+
+// Categorized returns a snapshot of every distinct error signature Add has
+// seen, keyed by that signature, for a programmatic consumer that wants
+// more than the aggregate Count.
+func (se *ScanErrors) Categorized() map[string]CategoryStats {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	out := make(map[string]CategoryStats, len(se.buckets))
+	for sig, b := range se.buckets {
+		out[sig] = CategoryStats{Count: b.count, Example: b.example, Tags: sortedKeys(b.tags)}
+	}
+	return out
+}
+
+// String renders a human-readable dump of every category Add has seen, in
+// first-seen order, one example message per category with a "+N similar"
+// suffix for every repeat beyond the first.
+func (se *ScanErrors) String() string {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if se.total == 0 {
+		return "no errors"
+	}
+
+	var b strings.Builder
+	for i, sig := range se.order {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		bucket := se.buckets[sig]
+		b.WriteString(bucket.example)
+		if bucket.count > 1 {
+			fmt.Fprintf(&b, " (+%d similar)", bucket.count-1)
+		}
+		if len(bucket.tags) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(sortedKeys(bucket.tags), ", "))
+		}
+	}
+	return b.String()
+}
+
+// MarshalJSON implements json.Marshaler so a ScanErrors can be embedded
+// directly in a --json scan report.
+func (se *ScanErrors) MarshalJSON() ([]byte, error) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	out := struct {
+		Total      uint64                   `json:"total"`
+		Categories map[string]CategoryStats `json:"categories"`
+	}{
+		Total:      se.total,
+		Categories: make(map[string]CategoryStats, len(se.buckets)),
+	}
+	for sig, b := range se.buckets {
+		out.Categories[sig] = CategoryStats{Count: b.count, Example: b.example, Tags: sortedKeys(b.tags)}
+	}
+	return json.Marshal(out)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,7 +1,9 @@
 package sources
 
 import (
-	"reflect"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -9,38 +11,22 @@ import (
 func TestNewScanErrors(t *testing.T) {
 	testCases := []struct {
 		name     string
-		projects int
-		want     *ScanErrors
+		projects []int
 	}{
-		{
-			name:     "no projects",
-			projects: 0,
-			want: &ScanErrors{
-				errors: make([]error, 0, 0),
-			},
-		},
-		{
-			name:     "one project",
-			projects: 1,
-			want: &ScanErrors{
-				errors: make([]error, 0, 1),
-			},
-		},
-		{
-			name:     "fifty projects",
-			projects: 50,
-			want: &ScanErrors{
-				errors: make([]error, 0, 50),
-			},
-		},
+		{name: "no hint", projects: nil},
+		{name: "one project", projects: []int{1}},
+		{name: "fifty projects", projects: []int{50}},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NewScanErrors(tc.projects)
+			got := NewScanErrors(tc.projects...)
 
-			if !reflect.DeepEqual(got, tc.want) {
-				t.Errorf("got %+v, want %+v", got, tc.want)
+			if got.Count() != 0 {
+				t.Errorf("got Count() = %d, want 0", got.Count())
+			}
+			if len(got.Categorized()) != 0 {
+				t.Errorf("got %d categories, want 0", len(got.Categorized()))
 			}
 		})
 	}
@@ -83,7 +69,7 @@ func TestScanErrorsAdd(t *testing.T) {
 				wg.Add(1)
 				go func() {
 					for j := 0; j < tc.wantErr/tc.concurrency; j++ {
-						se.Add(nil)
+						se.Add(errors.New("boom"))
 					}
 					wg.Done()
 				}()
@@ -134,7 +120,7 @@ func TestScanErrorsCount(t *testing.T) {
 				wg.Add(1)
 				go func() {
 					for j := 0; j < tc.wantErrCnt/tc.concurrency; j++ {
-						se.Add(nil)
+						se.Add(errors.New("boom"))
 					}
 					wg.Done()
 				}()
@@ -147,3 +133,53 @@ func TestScanErrorsCount(t *testing.T) {
 		})
 	}
 }
+
+func TestScanErrorsCategorized(t *testing.T) {
+	se := NewScanErrors()
+
+	for i := 0; i < 5; i++ {
+		se.Add(fmt.Errorf("open /repos/project-%d/.git: permission denied", i), "clone")
+	}
+	for i := 0; i < 3; i++ {
+		se.Add(fmt.Errorf("object %040x not found", i), "fetch")
+	}
+
+	if got := se.Count(); got != 8 {
+		t.Fatalf("Count() = %d, want 8", got)
+	}
+
+	categories := se.Categorized()
+	if len(categories) != 2 {
+		t.Fatalf("got %d categories, want 2: %+v", len(categories), categories)
+	}
+
+	for _, stats := range categories {
+		switch stats.Count {
+		case 5:
+			if len(stats.Tags) != 1 || stats.Tags[0] != "clone" {
+				t.Errorf("permission-denied bucket: got tags %v, want [clone]", stats.Tags)
+			}
+		case 3:
+			if len(stats.Tags) != 1 || stats.Tags[0] != "fetch" {
+				t.Errorf("object-not-found bucket: got tags %v, want [fetch]", stats.Tags)
+			}
+		default:
+			t.Errorf("unexpected bucket count %d", stats.Count)
+		}
+	}
+}
+
+func TestScanErrorsString(t *testing.T) {
+	se := NewScanErrors()
+	if got := se.String(); got != "no errors" {
+		t.Errorf(`empty ScanErrors.String() = %q, want "no errors"`, got)
+	}
+
+	se.Add(errors.New("object abc1234 not found"))
+	se.Add(errors.New("object def5678 not found"))
+
+	got := se.String()
+	if !strings.Contains(got, "+1 similar") {
+		t.Errorf("String() = %q, want it to mention +1 similar", got)
+	}
+}
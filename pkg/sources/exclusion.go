@@ -0,0 +1,107 @@
+package sources
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExclusionConfig is a source-agnostic skip list consulted by the
+// filesystem, git, github, and docker sources before a chunk is dispatched
+// for decoding, so users scanning monorepos and container images can cut
+// well-known binary/noise paths out of a scan with one shared config
+// instead of re-specifying per-source filters.
+type ExclusionConfig struct {
+	// BlacklistedExtensions skips any path whose extension matches one of
+	// these, case-insensitively. A leading `.` is optional.
+	BlacklistedExtensions []string `yaml:"blacklisted_extensions"`
+	// BlacklistedPaths skips any path containing one of these as a
+	// substring. A `{sep}` placeholder expands to the OS path separator,
+	// so the same config works unmodified on Linux and Windows (e.g.
+	// `{sep}node_modules{sep}`).
+	BlacklistedPaths []string `yaml:"blacklisted_paths"`
+	// BlacklistedStrings skips any chunk whose decoded data contains one
+	// of these, as a case-sensitive substring.
+	BlacklistedStrings []string `yaml:"blacklisted_strings"`
+}
+
+// LoadExclusionConfig reads and parses an ExclusionConfig from the YAML
+// file at path.
+func LoadExclusionConfig(path string) (*ExclusionConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg ExclusionConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ShouldSkipPath reports whether name matches one of cfg's
+// BlacklistedPaths or BlacklistedExtensions. A nil cfg never skips
+// anything.
+func (cfg *ExclusionConfig) ShouldSkipPath(name string) bool {
+	if cfg == nil {
+		return false
+	}
+
+	for _, p := range cfg.BlacklistedPaths {
+		p = expandSepPlaceholder(p)
+		if p != "" && strings.Contains(name, p) {
+			return true
+		}
+	}
+
+	ext := extOf(name)
+	for _, blocked := range cfg.BlacklistedExtensions {
+		if !strings.HasPrefix(blocked, ".") {
+			blocked = "." + blocked
+		}
+		if ext != "" && strings.EqualFold(ext, blocked) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShouldSkipData reports whether data contains one of cfg's
+// BlacklistedStrings. A nil cfg never skips anything.
+func (cfg *ExclusionConfig) ShouldSkipData(data []byte) bool {
+	if cfg == nil {
+		return false
+	}
+
+	for _, s := range cfg.BlacklistedStrings {
+		if s != "" && bytes.Contains(data, []byte(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandSepPlaceholder replaces the `{sep}` placeholder in p with the
+// current OS's path separator.
+func expandSepPlaceholder(p string) string {
+	return strings.ReplaceAll(p, "{sep}", string(os.PathSeparator))
+}
+
+// extOf returns the last `.`-prefixed extension in name, including the
+// dot, or "" if name has none.
+func extOf(name string) string {
+	idx := strings.LastIndexByte(name, '.')
+	if idx == -1 {
+		return ""
+	}
+	// Don't treat a dotfile with no further extension (".gitignore") as
+	// having an extension.
+	if strings.LastIndexAny(name[:idx], `/\`) == idx-1 {
+		return ""
+	}
+	return name[idx:]
+}
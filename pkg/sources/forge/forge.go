@@ -0,0 +1,54 @@
+// Package forge defines a small, provider-agnostic interface for listing
+// and cloning repositories hosted on a code-forge (GitLab, GitHub, Gitea,
+// Bitbucket, ...), plus a RepoScanner that runs the clone/scan/resume
+// scaffolding that was previously duplicated inside each forge-specific
+// Source. A Source wires a Client implementation into a RepoScanner
+// instead of reimplementing enumeration, resume bookkeeping, and
+// concurrency control itself.
+package forge
+
+import (
+	"github.com/go-git/go-git/v5"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+)
+
+// Repo identifies a single repository a Client can enumerate and clone.
+type Repo struct {
+	// Name is the repository's human-readable name (e.g. "repo").
+	Name string
+	// FullPath is the repository's full namespaced path (e.g.
+	// "org/group/repo"), used for resume bookkeeping and logging.
+	FullPath string
+	// CloneURL is the URL CloneRepo accepts to clone this repository.
+	CloneURL string
+}
+
+// AuthKind identifies how a Client authenticates to its forge.
+type AuthKind string
+
+const (
+	AuthUnauthenticated AuthKind = "UNAUTHENTICATED"
+	AuthToken           AuthKind = "TOKEN"
+	AuthBasic           AuthKind = "BASIC_AUTH"
+)
+
+// Client abstracts the forge-specific operations a RepoScanner needs:
+// discovering which repositories to scan and cloning one of them to a
+// local path. Implementations wrap a specific forge's API client (go-
+// gitlab, go-github, ...).
+type Client interface {
+	// ListRepos returns the repositories this Client is configured to
+	// scan. filter, when non-nil, restricts the result to repos it
+	// reports true for; a nil filter returns everything the Client can
+	// see.
+	ListRepos(ctx context.Context, filter func(Repo) bool) ([]Repo, error)
+
+	// CloneRepo clones repo to a new temporary directory and returns its
+	// path alongside the opened repository.
+	CloneRepo(ctx context.Context, repo Repo) (path string, repository *git.Repository, err error)
+
+	// AuthKind reports how this Client authenticates, for logging and
+	// metrics.
+	AuthKind() AuthKind
+}
@@ -0,0 +1,71 @@
+package forge
+
+import (
+	"github.com/go-git/go-git/v5"
+	gitlabapi "github.com/xanzy/go-gitlab"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	sourcegit "github.com/trufflesecurity/trufflehog/v3/pkg/sources/git"
+)
+
+const gitlabPaginationLimit = 100 // Default is 20, max is 100.
+
+// GitLabClient is a Client backed by a go-gitlab API client.
+type GitLabClient struct {
+	api *gitlabapi.Client
+
+	user  string
+	token string
+	auth  AuthKind
+}
+
+// NewGitLabClient builds a GitLabClient around api, authenticating clones
+// with user/token. auth records how api itself authenticates, for
+// AuthKind.
+func NewGitLabClient(api *gitlabapi.Client, user, token string, auth AuthKind) *GitLabClient {
+	return &GitLabClient{api: api, user: user, token: token, auth: auth}
+}
+
+func (c *GitLabClient) AuthKind() AuthKind { return c.auth }
+
+// ListRepos enumerates every project the authenticated user can see,
+// returning only those filter reports true for (or all of them, if filter
+// is nil).
+func (c *GitLabClient) ListRepos(ctx context.Context, filter func(Repo) bool) ([]Repo, error) {
+	var repos []Repo
+	opts := &gitlabapi.ListProjectsOptions{ListOptions: gitlabapi.ListOptions{PerPage: gitlabPaginationLimit}}
+	for {
+		projects, res, err := c.api.Projects.ListProjects(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range projects {
+			repo := Repo{
+				Name:     project.Name,
+				FullPath: project.PathWithNamespace,
+				CloneURL: project.HTTPURLToRepo,
+			}
+			if filter == nil || filter(repo) {
+				repos = append(repos, repo)
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return repos, nil
+}
+
+// CloneRepo clones repo using the token this GitLabClient was built with,
+// or unauthenticated if none was given.
+func (c *GitLabClient) CloneRepo(ctx context.Context, repo Repo) (string, *git.Repository, error) {
+	if c.token == "" {
+		return sourcegit.CloneRepoUsingUnauthenticated(ctx, repo.CloneURL)
+	}
+	user := c.user
+	if user == "" {
+		user = "placeholder"
+	}
+	return sourcegit.CloneRepoUsingToken(ctx, c.token, repo.CloneURL, user)
+}
@@ -0,0 +1,120 @@
+package forge
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// ScanFunc scans a single cloned repository at path.
+type ScanFunc func(ctx context.Context, repo Repo, path string, repository *git.Repository) error
+
+// RepoScanner runs the clone/scan/resume scaffolding shared by every
+// forge-backed Source: it clones each Repo a Client lists (skipping ones a
+// resumed scan already finished), reports progress as it goes, and runs
+// clones concurrently up to a configured limit.
+type RepoScanner struct {
+	client      Client
+	concurrency int
+
+	resumeInfoMutex sync.Mutex
+	resumeInfoSlice []string
+}
+
+// NewRepoScanner builds a RepoScanner around client, running up to
+// concurrency clones/scans at once.
+func NewRepoScanner(client Client, concurrency int) *RepoScanner {
+	return &RepoScanner{client: client, concurrency: concurrency}
+}
+
+// Scan lists repos via r's Client (honoring any resume info already in
+// progress), clones each one and calls scan on it, and reports progress on
+// progress as it goes.
+func (r *RepoScanner) Scan(ctx context.Context, progress *sources.Progress, filter func(Repo) bool, scan ScanFunc) error {
+	repos, err := r.client.ListRepos(ctx, filter)
+	if err != nil {
+		return err
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].FullPath < repos[j].FullPath })
+
+	repoPaths := make([]string, len(repos))
+	for i, repo := range repos {
+		repoPaths[i] = repo.FullPath
+	}
+	reposToScan, progressIndexOffset := sources.FilterReposToResume(repoPaths, progress.EncodedResumeInfo)
+	toScan := make(map[string]bool, len(reposToScan))
+	for _, path := range reposToScan {
+		toScan[path] = true
+	}
+	filtered := repos[:0]
+	for _, repo := range repos {
+		if toScan[repo.FullPath] {
+			filtered = append(filtered, repo)
+		}
+	}
+	repos = filtered
+
+	scanErrs := sources.NewScanErrors()
+	jobPool := &errgroup.Group{}
+	jobPool.SetLimit(r.concurrency)
+
+	for i, repo := range repos {
+		i, repo := i, repo
+		jobPool.Go(func() error {
+			logger := ctx.Logger().WithValues("repo", repo.FullPath)
+			if common.IsDone(ctx) {
+				logger.V(2).Info("Skipping repo because context was cancelled")
+				return nil
+			}
+
+			r.setProgressCompleteWithRepo(progress, i, progressIndexOffset, len(repos), repo.FullPath)
+			defer r.removeRepoFromResumeInfo(repo.FullPath)
+
+			path, repository, err := r.client.CloneRepo(ctx, repo)
+			if err != nil {
+				scanErrs.Add(err)
+				return nil
+			}
+			defer os.RemoveAll(path)
+
+			logger.V(2).Info("starting scan", "num", i+1, "total", len(repos))
+			if err := scan(ctx, repo, path, repository); err != nil {
+				scanErrs.Add(err)
+				return nil
+			}
+			logger.V(2).Info("completed scan", "num", i+1, "total", len(repos))
+			return nil
+		})
+	}
+
+	_ = jobPool.Wait()
+	if scanErrs.Count() > 0 {
+		ctx.Logger().V(2).Info("encountered errors while scanning", "count", scanErrs.Count(), "errors", scanErrs)
+	}
+	progress.SetProgressComplete(len(repos), len(repos), "Completed scan", "")
+
+	return nil
+}
+
+func (r *RepoScanner) setProgressCompleteWithRepo(progress *sources.Progress, index, offset, total int, fullPath string) {
+	r.resumeInfoMutex.Lock()
+	r.resumeInfoSlice = append(r.resumeInfoSlice, fullPath)
+	sort.Strings(r.resumeInfoSlice)
+	encodedResumeInfo := sources.EncodeResumeInfo(r.resumeInfoSlice)
+	r.resumeInfoMutex.Unlock()
+
+	progress.SetProgressComplete(index+offset, total+offset, "Repo: "+fullPath, encodedResumeInfo)
+}
+
+func (r *RepoScanner) removeRepoFromResumeInfo(fullPath string) {
+	r.resumeInfoMutex.Lock()
+	defer r.resumeInfoMutex.Unlock()
+	r.resumeInfoSlice = sources.RemoveRepoFromResumeInfo(r.resumeInfoSlice, fullPath)
+}
@@ -0,0 +1,675 @@
+package gcs
+
+import (
+	stdctx "context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/storage"
+	"github.com/bmatcuk/doublestar/v4"
+	"golang.org/x/oauth2/google"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+)
+
+const (
+	defaultConcurrency = 10
+
+	// defaultChunkSize is the range size used to stream an object larger
+	// than withMaxObjectSize's threshold, rather than reading it in one
+	// GetObject-style call.
+	defaultChunkSize = 8 * 1024 * 1024 // 8 MiB
+	// defaultMaxObjectSize is the size above which an object is streamed in
+	// chunks instead of read all at once.
+	defaultMaxObjectSize = 50 * 1024 * 1024 // 50 MiB
+	// defaultResumeRetries is how many times a chunked read resumes from its
+	// last successful byte offset after a transient error before giving up.
+	defaultResumeRetries = 3
+)
+
+// object represents a GCS object discovered by listObjects, along with the
+// metadata needed to read and report on it.
+type object struct {
+	name        string
+	bucket      string
+	contentType string
+	size        int64
+	link        string
+	acl         []string
+	generation  int64
+	createdAt   time.Time
+	updatedAt   time.Time
+
+	reader io.Reader
+
+	// ack must be called once this object's chunks have all been emitted
+	// downstream; nack must be called if that fails. Both are no-ops for
+	// objects produced by listObjects. streamChangedObjects wires them to
+	// the underlying Pub/Sub message's Ack/Nack, so a scanner that crashes
+	// mid-chunk only replays the change events it never finished with.
+	ack  func()
+	nack func()
+}
+
+func noopAck() {}
+
+// gcsManager lists buckets/objects in a GCS project and streams their
+// contents, subject to include/exclude filters.
+type gcsManager struct {
+	projectID string
+
+	includeBuckets map[string]struct{}
+	excludeBuckets map[string]struct{}
+	includeObjects map[string]struct{}
+	excludeObjects map[string]struct{}
+
+	// includeObjectPrefixes is pushed down as a storage.Query.Prefix per
+	// bucket, one listing per prefix, so matching objects are never listed
+	// in the first place. excludeObjectPrefixes and objectGlob have no
+	// server-side equivalent and are evaluated after listing instead.
+	includeObjectPrefixes []string
+	excludeObjectPrefixes []string
+	objectGlob            string
+
+	concurrency   int
+	chunkSize     int64
+	maxObjectSize int64
+	resumeRetries int
+
+	// billingProject is charged for request and network costs when
+	// scanning a Requester Pays bucket.
+	billingProject string
+	// csekKey is the customer-supplied AES-256 encryption key required to
+	// read an object encrypted with CSEK.
+	csekKey []byte
+	// kmsKeyName records the Cloud KMS key a bucket/object is expected to be
+	// encrypted with. GCS decrypts CMEK-protected objects transparently
+	// server-side, so this isn't passed to any read call; it's accepted for
+	// configuration symmetry with withCSEKKey and future attrs filtering.
+	kmsKeyName string
+
+	// pubsubProjectID/pubsubSubscriptionID switch streamChangedObjects into
+	// event-driven mode: objects are pulled from GCS Object Change
+	// Notifications delivered to this subscription instead of being
+	// enumerated.
+	pubsubProjectID      string
+	pubsubSubscriptionID string
+
+	client     *storage.Client
+	workerPool *errgroup.Group
+
+	numBuckets uint32
+	numObjects uint64
+}
+
+type gcsManagerOption func(*gcsManager)
+
+// withAPIKey authenticates the GCS client with a plain API key.
+func withAPIKey(ctx context.Context, apiKey string) gcsManagerOption {
+	return func(m *gcsManager) {
+		client, err := storage.NewClient(ctx, option.WithAPIKey(apiKey))
+		if err != nil {
+			ctx.Logger().Error(err, "error creating GCS client with API key")
+			return
+		}
+		m.client = client
+	}
+}
+
+// withJSONServiceAccount authenticates the GCS client with a JSON service
+// account credential.
+func withJSONServiceAccount(ctx context.Context, json []byte) gcsManagerOption {
+	return func(m *gcsManager) {
+		client, err := storage.NewClient(ctx, option.WithCredentialsJSON(json))
+		if err != nil {
+			ctx.Logger().Error(err, "error creating GCS client with JSON service account")
+			return
+		}
+		m.client = client
+	}
+}
+
+// withDefaultADC authenticates the GCS client with Application Default
+// Credentials.
+func withDefaultADC(ctx context.Context) gcsManagerOption {
+	return func(m *gcsManager) {
+		creds, err := google.FindDefaultCredentials(ctx, storage.ScopeReadOnly)
+		if err != nil {
+			ctx.Logger().Error(err, "error finding default credentials")
+			return
+		}
+
+		client, err := storage.NewClient(ctx, option.WithCredentials(creds))
+		if err != nil {
+			ctx.Logger().Error(err, "error creating GCS client with default credentials")
+			return
+		}
+		m.client = client
+	}
+}
+
+// withIncludeBuckets restricts the scan to the named buckets.
+func withIncludeBuckets(buckets []string) gcsManagerOption {
+	return func(m *gcsManager) { m.includeBuckets = toSet(buckets) }
+}
+
+// withExcludeBuckets excludes the named buckets from the scan.
+func withExcludeBuckets(buckets []string) gcsManagerOption {
+	return func(m *gcsManager) { m.excludeBuckets = toSet(buckets) }
+}
+
+// withIncludeObjects restricts the scan to objects with the given names.
+func withIncludeObjects(objects []string) gcsManagerOption {
+	return func(m *gcsManager) { m.includeObjects = toSet(objects) }
+}
+
+// withExcludeObjects excludes objects with the given names from the scan.
+func withExcludeObjects(objects []string) gcsManagerOption {
+	return func(m *gcsManager) { m.excludeObjects = toSet(objects) }
+}
+
+// withIncludeObjectPrefixes restricts the scan to objects whose name starts
+// with one of prefixes, filtered server-side via storage.Query.Prefix.
+func withIncludeObjectPrefixes(prefixes []string) gcsManagerOption {
+	return func(m *gcsManager) { m.includeObjectPrefixes = prefixes }
+}
+
+// withExcludeObjectPrefixes excludes objects whose name starts with one of
+// prefixes. Evaluated client-side after listing.
+func withExcludeObjectPrefixes(prefixes []string) gcsManagerOption {
+	return func(m *gcsManager) { m.excludeObjectPrefixes = prefixes }
+}
+
+// withObjectGlob restricts the scan to objects whose name matches pattern,
+// using doublestar glob semantics (e.g. "**/*.json"). Evaluated client-side
+// after listing.
+func withObjectGlob(pattern string) gcsManagerOption {
+	return func(m *gcsManager) { m.objectGlob = pattern }
+}
+
+// withConcurrency sets how many buckets are listed concurrently. Values less
+// than 1 fall back to defaultConcurrency.
+func withConcurrency(concurrency int) gcsManagerOption {
+	return func(m *gcsManager) {
+		if concurrency < 1 {
+			concurrency = defaultConcurrency
+		}
+		m.concurrency = concurrency
+	}
+}
+
+// withChunkSize sets the range size used to stream objects larger than the
+// withMaxObjectSize threshold. Values less than 1 fall back to
+// defaultChunkSize.
+func withChunkSize(chunkSize int64) gcsManagerOption {
+	return func(m *gcsManager) {
+		if chunkSize < 1 {
+			chunkSize = defaultChunkSize
+		}
+		m.chunkSize = chunkSize
+	}
+}
+
+// withMaxObjectSize sets the size above which an object is read in chunks
+// rather than all at once. Values less than 1 fall back to
+// defaultMaxObjectSize.
+func withMaxObjectSize(maxObjectSize int64) gcsManagerOption {
+	return func(m *gcsManager) {
+		if maxObjectSize < 1 {
+			maxObjectSize = defaultMaxObjectSize
+		}
+		m.maxObjectSize = maxObjectSize
+	}
+}
+
+// withResumeRetries sets how many times a chunked read resumes from its last
+// successful byte offset after a transient error. Values less than 0 fall
+// back to defaultResumeRetries.
+func withResumeRetries(retries int) gcsManagerOption {
+	return func(m *gcsManager) {
+		if retries < 0 {
+			retries = defaultResumeRetries
+		}
+		m.resumeRetries = retries
+	}
+}
+
+// withBillingProject sets the project billed for request and network costs
+// when scanning a Requester Pays bucket.
+func withBillingProject(projectID string) gcsManagerOption {
+	return func(m *gcsManager) { m.billingProject = projectID }
+}
+
+// withCSEKKey sets the customer-supplied AES-256 encryption key used to read
+// objects protected with CSEK.
+func withCSEKKey(keyBytes []byte) gcsManagerOption {
+	return func(m *gcsManager) { m.csekKey = keyBytes }
+}
+
+// withKMSKeyName records the Cloud KMS key a bucket/object is expected to be
+// encrypted with. See gcsManager.kmsKeyName.
+func withKMSKeyName(name string) gcsManagerOption {
+	return func(m *gcsManager) { m.kmsKeyName = name }
+}
+
+// withPubSubSubscription switches the manager into event-driven mode:
+// streamChangedObjects pulls only the objects referenced by GCS Object
+// Change Notifications delivered to subID in projectID, instead of
+// listObjects' full enumeration.
+func withPubSubSubscription(projectID, subID string) gcsManagerOption {
+	return func(m *gcsManager) {
+		m.pubsubProjectID = projectID
+		m.pubsubSubscriptionID = subID
+	}
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+	return set
+}
+
+// newGCSManager returns a gcsManager for projectID, configured by opts. At
+// least one of withAPIKey, withJSONServiceAccount, or withDefaultADC must be
+// given to produce a usable client.
+func newGCSManager(projectID string, opts ...gcsManagerOption) (*gcsManager, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("projectID cannot be empty")
+	}
+
+	m := &gcsManager{projectID: projectID, concurrency: defaultConcurrency}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.client == nil {
+		// No withAPIKey/withJSONServiceAccount/withDefaultADC option supplied
+		// a client; fall back to the storage package's own default
+		// credential discovery. This never fails outright since the client
+		// authenticates lazily on its first request.
+		client, err := storage.NewClient(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("error creating default GCS client: %w", err)
+		}
+		m.client = client
+	}
+
+	return m, nil
+}
+
+// included reports whether name passes the include/exclude filters. An empty
+// include set means everything is included unless explicitly excluded.
+func included(name string, include, exclude map[string]struct{}) bool {
+	if _, excluded := exclude[name]; excluded {
+		return false
+	}
+	if len(include) == 0 {
+		return true
+	}
+	_, ok := include[name]
+	return ok
+}
+
+// listObjects streams every object visible to m, subject to the configured
+// bucket/object filters, over the returned channel.
+func (m *gcsManager) listObjects(ctx context.Context) (<-chan object, error) {
+	results := make(chan object)
+
+	bktIter := m.client.Buckets(ctx, m.projectID)
+	var buckets []string
+	for {
+		attrs, err := bktIter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing buckets: %w", err)
+		}
+		if !included(attrs.Name, m.includeBuckets, m.excludeBuckets) {
+			continue
+		}
+		buckets = append(buckets, attrs.Name)
+	}
+
+	m.workerPool, ctx2 := errgroup.WithContext(ctx)
+	m.workerPool.SetLimit(m.concurrency)
+
+	go func() {
+		defer close(results)
+		for _, bucketName := range buckets {
+			bucketName := bucketName
+			m.workerPool.Go(func() error {
+				return m.listBucketObjects(ctx2, bucketName, results)
+			})
+		}
+		_ = m.workerPool.Wait()
+	}()
+
+	return results, nil
+}
+
+// gcsChangeEventTypes are the GCS Object Change Notification "eventType"
+// attributes streamChangedObjects acts on. Other event types (e.g.
+// OBJECT_DELETE, OBJECT_ARCHIVE) are acked and dropped without being scanned.
+var gcsChangeEventTypes = map[string]struct{}{
+	"OBJECT_FINALIZE":        {},
+	"OBJECT_METADATA_UPDATE": {},
+}
+
+// streamChangedObjects streams objects referenced by GCS Object Change
+// Notifications delivered to m.pubsubSubscriptionID, rather than enumerating
+// every object in the project. It returns the same <-chan object shape as
+// listObjects, so downstream chunking is unchanged.
+//
+// Each object's ack/nack are wired to the underlying Pub/Sub message's
+// Ack/Nack, so a crashed scanner only replays change events it never
+// finished emitting chunks for.
+func (m *gcsManager) streamChangedObjects(ctx context.Context) (<-chan object, error) {
+	if m.pubsubSubscriptionID == "" {
+		return nil, fmt.Errorf("streamChangedObjects requires withPubSubSubscription")
+	}
+
+	client, err := pubsub.NewClient(ctx, m.pubsubProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Pub/Sub client: %w", err)
+	}
+	sub := client.Subscription(m.pubsubSubscriptionID)
+
+	results := make(chan object)
+
+	go func() {
+		defer close(results)
+		defer client.Close()
+
+		err := sub.Receive(ctx, func(msgCtx stdctx.Context, msg *pubsub.Message) {
+			if _, ok := gcsChangeEventTypes[msg.Attributes["eventType"]]; !ok {
+				msg.Ack()
+				return
+			}
+
+			bucketName := msg.Attributes["bucketId"]
+			objectName := msg.Attributes["objectId"]
+			if !included(objectName, m.includeObjects, m.excludeObjects) {
+				msg.Ack()
+				return
+			}
+
+			attrs, err := m.bucket(bucketName).Object(objectName).Attrs(msgCtx)
+			if err != nil {
+				ctx.Logger().Error(err, "error fetching attrs for changed object", "bucket", bucketName, "object", objectName)
+				msg.Nack()
+				return
+			}
+
+			atomic.AddUint64(&m.numObjects, 1)
+			obj := object{
+				name:        attrs.Name,
+				bucket:      attrs.Bucket,
+				contentType: attrs.ContentType,
+				size:        attrs.Size,
+				link:        attrs.MediaLink,
+				acl:         aclToStrings(attrs.ACL),
+				generation:  attrs.Generation,
+				createdAt:   attrs.Created,
+				updatedAt:   attrs.Updated,
+				ack:         msg.Ack,
+				nack:        msg.Nack,
+			}
+
+			select {
+			case results <- obj:
+			case <-msgCtx.Done():
+				msg.Nack()
+			}
+		})
+		if err != nil {
+			ctx.Logger().Error(err, "error receiving Pub/Sub messages", "subscription", m.pubsubSubscriptionID)
+		}
+	}()
+
+	return results, nil
+}
+
+// bucket returns a handle for bucketName, billed to m.billingProject if one
+// is set, so Requester Pays buckets can be scanned.
+func (m *gcsManager) bucket(bucketName string) *storage.BucketHandle {
+	bkt := m.client.Bucket(bucketName)
+	if m.billingProject != "" {
+		bkt = bkt.UserProject(m.billingProject)
+	}
+	return bkt
+}
+
+func (m *gcsManager) listBucketObjects(ctx context.Context, bucketName string, results chan<- object) error {
+	atomic.AddUint32(&m.numBuckets, 1)
+
+	bkt := m.bucket(bucketName)
+
+	prefixes := m.includeObjectPrefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+
+	// A name can match more than one prefix query (e.g. "a" and "ab" both
+	// match "abc.txt"); dedup so it's only reported once per bucket.
+	seen := make(map[string]struct{})
+
+	for _, prefix := range prefixes {
+		objIter := bkt.Objects(ctx, &storage.Query{Prefix: prefix})
+		for {
+			attrs, err := objIter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("error listing objects in bucket %q: %w", bucketName, err)
+			}
+
+			if _, ok := seen[attrs.Name]; ok {
+				continue
+			}
+			if !included(attrs.Name, m.includeObjects, m.excludeObjects) {
+				continue
+			}
+			if hasAnyPrefix(attrs.Name, m.excludeObjectPrefixes) {
+				continue
+			}
+			if m.objectGlob != "" {
+				if ok, err := doublestar.Match(m.objectGlob, attrs.Name); err != nil || !ok {
+					continue
+				}
+			}
+			seen[attrs.Name] = struct{}{}
+
+			atomic.AddUint64(&m.numObjects, 1)
+			results <- object{
+				name:        attrs.Name,
+				bucket:      attrs.Bucket,
+				contentType: attrs.ContentType,
+				size:        attrs.Size,
+				link:        attrs.MediaLink,
+				acl:         aclToStrings(attrs.ACL),
+				generation:  attrs.Generation,
+				createdAt:   attrs.Created,
+				updatedAt:   attrs.Updated,
+				ack:         noopAck,
+				nack:        noopAck,
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasAnyPrefix reports whether name starts with any of prefixes.
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func aclToStrings(rules []storage.ACLRule) []string {
+	acl := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		acl = append(acl, fmt.Sprintf("%s:%s", rule.Entity, rule.Role))
+	}
+	return acl
+}
+
+// open returns a reader for obj, pinned to the generation listObjects
+// observed. Objects larger than m.maxObjectSize (or defaultMaxObjectSize if
+// unset) are streamed in m.chunkSize (or defaultChunkSize) ranges, resuming
+// from the last successfully read byte offset on io.ErrUnexpectedEOF or a 5xx
+// response, up to m.resumeRetries (or defaultResumeRetries) times. If the
+// generation changes mid-read, the reader returns an error rather than
+// silently mixing bytes from two versions.
+func (m *gcsManager) open(ctx context.Context, obj object) (io.ReadCloser, error) {
+	maxObjectSize := m.maxObjectSize
+	if maxObjectSize == 0 {
+		maxObjectSize = defaultMaxObjectSize
+	}
+
+	handle := m.bucket(obj.bucket).Object(obj.name).Generation(obj.generation)
+	if m.csekKey != nil {
+		// A CSEK-protected object can't be transcoded server-side, so disable
+		// gzip decompression to avoid a mismatched-checksum error from the API.
+		handle = handle.Key(m.csekKey).ReadCompressed(false)
+	}
+
+	if obj.size <= maxObjectSize {
+		return handle.NewReader(ctx)
+	}
+
+	chunkSize := m.chunkSize
+	if chunkSize == 0 {
+		chunkSize = defaultChunkSize
+	}
+	resumeRetries := m.resumeRetries
+	if resumeRetries == 0 {
+		resumeRetries = defaultResumeRetries
+	}
+
+	return &chunkedObjectReader{
+		ctx:           ctx,
+		handle:        handle,
+		size:          obj.size,
+		generation:    obj.generation,
+		chunkSize:     chunkSize,
+		resumeRetries: resumeRetries,
+	}, nil
+}
+
+// chunkedObjectReader reads a single GCS object across repeated
+// NewRangeReader calls pinned to one generation, transparently resuming from
+// the last successfully read byte offset when a chunk fails partway through.
+type chunkedObjectReader struct {
+	ctx           context.Context
+	handle        *storage.ObjectHandle
+	size          int64
+	generation    int64
+	chunkSize     int64
+	resumeRetries int
+
+	offset  int64
+	current io.ReadCloser
+}
+
+// Read implements io.Reader, opening chunks on demand and resuming them
+// transparently on transient failure.
+func (r *chunkedObjectReader) Read(p []byte) (int, error) {
+	for {
+		if r.offset >= r.size {
+			return 0, io.EOF
+		}
+
+		if r.current == nil {
+			if err := r.openChunk(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := r.current.Read(p)
+		r.offset += int64(n)
+
+		if err == nil {
+			return n, nil
+		}
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+
+		if !isResumableReadErr(err) || r.resumeRetries <= 0 {
+			return n, err
+		}
+
+		r.resumeRetries--
+		r.current.Close()
+		r.current = nil
+		if retryErr := r.openChunk(); retryErr != nil {
+			return n, retryErr
+		}
+	}
+}
+
+func (r *chunkedObjectReader) openChunk() error {
+	length := r.chunkSize
+	if r.offset+length > r.size {
+		length = r.size - r.offset
+	}
+
+	reader, err := r.handle.NewRangeReader(r.ctx, r.offset, length)
+	if err != nil {
+		return fmt.Errorf("error opening GCS range reader at offset %d: %w", r.offset, err)
+	}
+
+	if reader.Attrs.Generation != 0 && reader.Attrs.Generation != r.generation {
+		reader.Close()
+		return fmt.Errorf("object generation changed mid-read (expected %d, got %d)", r.generation, reader.Attrs.Generation)
+	}
+
+	r.current = reader
+	return nil
+}
+
+// Close implements io.Closer.
+func (r *chunkedObjectReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	return r.current.Close()
+}
+
+// isResumableReadErr reports whether err looks like a transient failure
+// worth resuming from the last successful offset, rather than a permanent
+// one (e.g. permission denied, object not found).
+func isResumableReadErr(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= 500
+	}
+
+	return false
+}
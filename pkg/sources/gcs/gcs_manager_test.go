@@ -241,6 +241,51 @@ func TestNewGcsManager(t *testing.T) {
 				concurrency: defaultConcurrency,
 			},
 		},
+		{
+			name:   "new gcs manager, with billing project",
+			projID: testProjectID,
+			opts:   []gcsManagerOption{withDefaultADC(ctx), withBillingProject("billing-project")},
+			want: &gcsManager{
+				projectID:      testProjectID,
+				billingProject: "billing-project",
+				concurrency:    defaultConcurrency,
+			},
+		},
+		{
+			name:   "new gcs manager, with csek key",
+			projID: testProjectID,
+			opts:   []gcsManagerOption{withDefaultADC(ctx), withCSEKKey([]byte("0123456789abcdef0123456789abcdef"))},
+			want: &gcsManager{
+				projectID:   testProjectID,
+				csekKey:     []byte("0123456789abcdef0123456789abcdef"),
+				concurrency: defaultConcurrency,
+			},
+		},
+		{
+			name:   "new gcs manager, with kms key name",
+			projID: testProjectID,
+			opts:   []gcsManagerOption{withDefaultADC(ctx), withKMSKeyName("projects/p/locations/global/keyRings/r/cryptoKeys/k")},
+			want: &gcsManager{
+				projectID:   testProjectID,
+				kmsKeyName:  "projects/p/locations/global/keyRings/r/cryptoKeys/k",
+				concurrency: defaultConcurrency,
+			},
+		},
+		{
+			name:   "new gcs manager, with billing project and csek key",
+			projID: testProjectID,
+			opts: []gcsManagerOption{
+				withDefaultADC(ctx),
+				withBillingProject("billing-project"),
+				withCSEKKey([]byte("0123456789abcdef0123456789abcdef")),
+			},
+			want: &gcsManager{
+				projectID:      testProjectID,
+				billingProject: "billing-project",
+				csekKey:        []byte("0123456789abcdef0123456789abcdef"),
+				concurrency:    defaultConcurrency,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -480,6 +525,65 @@ func TestGCSManagerListObjects(t *testing.T) {
 			wantNumBkt: 1,
 			wantNumObj: 1,
 		},
+		{
+			name:      "list objects, include prefix",
+			projectID: testProjectID,
+			opts:      []gcsManagerOption{withDefaultADC(ctx), withIncludeObjectPrefixes([]string{"aws"})},
+			want: []object{
+				{
+					name:        "aws1.txt",
+					bucket:      testBucket,
+					contentType: "text/plain",
+					size:        150,
+					link:        "https://storage.googleapis.com/download/storage/v1/b/test-bkt-th/o/aws1.txt?generation=1677870994890594&alt=media",
+					acl:         []string{},
+				},
+				{
+					name:        "aws3.txt",
+					bucket:      testBucket2,
+					contentType: "text/plain",
+					size:        150,
+					link:        "https://storage.googleapis.com/download/storage/v1/b/test-bkt-th2/o/aws3.txt?generation=1677871022489611&alt=media",
+					acl:         []string{},
+				},
+			},
+			wantNumBkt: 4,
+			wantNumObj: 2,
+		},
+		{
+			name:      "list objects, exclude prefix",
+			projectID: testProjectID,
+			opts:      []gcsManagerOption{withDefaultADC(ctx), withExcludeObjectPrefixes([]string{"aws", "moar"})},
+			want: []object{
+				{
+					name:        "AMAZON_FASHION_5.json",
+					bucket:      testBucket4,
+					contentType: "application/json",
+					size:        1413469,
+					link:        "https://storage.googleapis.com/download/storage/v1/b/test-bkt-th4/o/AMAZON_FASHION_5.json?generation=1677871063457469&alt=media",
+					acl:         []string{},
+				},
+			},
+			wantNumBkt: 4,
+			wantNumObj: 1,
+		},
+		{
+			name:      "list objects, object glob",
+			projectID: testProjectID,
+			opts:      []gcsManagerOption{withDefaultADC(ctx), withObjectGlob("*.json")},
+			want: []object{
+				{
+					name:        "AMAZON_FASHION_5.json",
+					bucket:      testBucket4,
+					contentType: "application/json",
+					size:        1413469,
+					link:        "https://storage.googleapis.com/download/storage/v1/b/test-bkt-th4/o/AMAZON_FASHION_5.json?generation=1677871063457469&alt=media",
+					acl:         []string{},
+				},
+			},
+			wantNumBkt: 4,
+			wantNumObj: 1,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -524,7 +628,7 @@ func TestGCSManagerListObjects(t *testing.T) {
 				sort.Slice(tc.want, func(i, j int) bool { return tc.want[i].name < tc.want[j].name })
 
 				// Test the objects are equal.
-				if diff := cmp.Diff(res, tc.want, cmp.AllowUnexported(object{}), cmpopts.IgnoreFields(object{}, "reader", "createdAt", "updatedAt")); diff != "" {
+				if diff := cmp.Diff(res, tc.want, cmp.AllowUnexported(object{}), cmpopts.IgnoreFields(object{}, "reader", "createdAt", "updatedAt", "ack", "nack")); diff != "" {
 					t.Errorf("gcsManager.listObjects() mismatch (-want +got):\n%s", diff)
 				}
 			}()
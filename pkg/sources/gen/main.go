@@ -0,0 +1,236 @@
+// Command gen generates MarshalJSON/UnmarshalJSON methods for a SourceUnit
+// schema struct, the way github.com/fjl/gencodec generates codecs for
+// go-ethereum's RPC types: a field tagged `truffle:"required"` must be
+// present on unmarshal, or UnmarshalJSON returns an error naming it.
+//
+// Invoke it via a go:generate directive next to the schema struct it
+// describes:
+//
+//	//go:generate go run github.com/trufflesecurity/trufflehog/v3/pkg/sources/gen -type SourceUnit -out source_unit_gen.go
+//
+// The schema struct itself is handwritten and unexceptional - plain fields
+// with ordinary `json:"..."` tags, plus `truffle:"required"` on whichever
+// fields a unit can't do without. gen never touches the schema file; it
+// only reads it and writes -out.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the schema struct to generate a codec for")
+	outPath := flag.String("out", "", "output file path (default: <type, snake_case>_gen.go)")
+	flag.Parse()
+
+	if *typeName == "" {
+		log.Fatal("gen: -type is required")
+	}
+	inPath := flag.Arg(0)
+	if inPath == "" {
+		inPath = "."
+	}
+
+	schema, err := loadSchema(inPath, *typeName)
+	if err != nil {
+		log.Fatalf("gen: %v", err)
+	}
+
+	out := *outPath
+	if out == "" {
+		out = strings.ToLower(*typeName) + "_gen.go"
+	}
+	if err := writeCodec(out, schema); err != nil {
+		log.Fatalf("gen: %v", err)
+	}
+}
+
+// field is one struct field of a schema type, as gen needs it.
+type field struct {
+	GoName     string // Go field identifier, e.g. "Repo"
+	GoType     string // Go type as written in source, e.g. "string"
+	JSONName   string // name used in the wire format, e.g. "repo"
+	OmitEmpty  bool
+	Required   bool
+	Pointerish bool // true if GoType is already nil-able (pointer, slice, map, interface)
+}
+
+// schema is everything gen needs to generate a codec for one struct.
+type schema struct {
+	Package string
+	Type    string
+	Fields  []field
+}
+
+// loadSchema parses the Go source file(s) in dir (or the single file at dir,
+// if it names a file rather than a directory) looking for a struct type
+// named typeName, and extracts its fields' json/truffle tags.
+func loadSchema(path, typeName string) (*schema, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != typeName {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("%s is not a struct type", typeName)
+			}
+			return buildSchema(fset, f.Name.Name, typeName, st)
+		}
+	}
+	return nil, fmt.Errorf("no struct type %q found in %s", typeName, path)
+}
+
+func buildSchema(fset *token.FileSet, pkg, typeName string, st *ast.StructType) (*schema, error) {
+	s := &schema{Package: pkg, Type: typeName}
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("embedded field in %s is not supported by gen", typeName)
+		}
+		goType := exprString(fset, f.Type)
+		pointerish := strings.HasPrefix(goType, "*") ||
+			strings.HasPrefix(goType, "[]") ||
+			strings.HasPrefix(goType, "map[") ||
+			goType == "any" || goType == "interface{}"
+
+		tag := reflect.StructTag("")
+		if f.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(f.Tag.Value, "`"))
+		}
+		jsonName, omitEmpty := parseJSONTag(tag.Get("json"), f.Names[0].Name)
+		required := tag.Get("truffle") == "required"
+
+		for _, name := range f.Names {
+			s.Fields = append(s.Fields, field{
+				GoName:     name.Name,
+				GoType:     goType,
+				JSONName:   jsonName,
+				OmitEmpty:  omitEmpty,
+				Required:   required,
+				Pointerish: pointerish,
+			})
+		}
+	}
+	return s, nil
+}
+
+func parseJSONTag(tag, fallback string) (name string, omitEmpty bool) {
+	if tag == "" {
+		return fallback, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = fallback
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty
+}
+
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, expr); err != nil {
+		return fmt.Sprintf("%#v", expr)
+	}
+	return buf.String()
+}
+
+var codecTemplate = template.Must(template.New("codec").Parse(`// Code generated by pkg/sources/gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// {{.Type}}JSON is the intermediate wire representation {{.Type}}'s codec
+// round-trips through: every field is a pointer (or already nil-able) so
+// UnmarshalJSON can tell "field present but zero-valued" apart from "field
+// absent", which is what lets it enforce truffle:"required" fields.
+type {{.Type}}JSON struct {
+{{- range .Fields}}
+	{{.GoName}} {{if .Pointerish}}{{.GoType}}{{else}}*{{.GoType}}{{end}} ` + "`json:\"{{.JSONName}}{{if .OmitEmpty}},omitempty{{end}}\"`" + `
+{{- end}}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u {{.Type}}) MarshalJSON() ([]byte, error) {
+	var enc {{.Type}}JSON
+{{- range .Fields}}
+{{- if .Pointerish}}
+	enc.{{.GoName}} = u.{{.GoName}}
+{{- else if .OmitEmpty}}
+	if u.{{.GoName}} != *new({{.GoType}}) {
+		enc.{{.GoName}} = &u.{{.GoName}}
+	}
+{{- else}}
+	enc.{{.GoName}} = &u.{{.GoName}}
+{{- end}}
+{{- end}}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, returning an error that names
+// the first missing required field rather than the zero value it would
+// otherwise silently decode to.
+func (u *{{.Type}}) UnmarshalJSON(input []byte) error {
+	var dec {{.Type}}JSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+{{- range .Fields}}
+{{- if .Required}}
+	if dec.{{.GoName}} == nil {
+		return fmt.Errorf("{{$.Type}}: missing required field %q", "{{.JSONName}}")
+	}
+{{- end}}
+{{- if .Pointerish}}
+	u.{{.GoName}} = dec.{{.GoName}}
+{{- else}}
+	if dec.{{.GoName}} != nil {
+		u.{{.GoName}} = *dec.{{.GoName}}
+	}
+{{- end}}
+{{- end}}
+	return nil
+}
+`))
+
+func writeCodec(outPath string, s *schema) error {
+	var buf bytes.Buffer
+	if err := codecTemplate.Execute(&buf, s); err != nil {
+		return fmt.Errorf("executing codec template: %w", err)
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("generated code for %s did not gofmt: %w\n%s", s.Type, err, buf.String())
+	}
+	return os.WriteFile(outPath, formatted, 0o644)
+}
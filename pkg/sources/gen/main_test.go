@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `package example
+
+type SourceUnit struct {
+	Repo   string ` + "`json:\"repo\" truffle:\"required\"`" + `
+	Commit string ` + "`json:\"commit\" truffle:\"required\"`" + `
+	Path   string ` + "`json:\"path,omitempty\"`" + `
+}
+`
+
+func writeTestSchema(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.go")
+	if err := os.WriteFile(path, []byte(testSchema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSchema(t *testing.T) {
+	path := writeTestSchema(t)
+
+	s, err := loadSchema(path, "SourceUnit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Package != "example" || s.Type != "SourceUnit" {
+		t.Fatalf("got package=%q type=%q", s.Package, s.Type)
+	}
+	if len(s.Fields) != 3 {
+		t.Fatalf("got %d fields, want 3: %+v", len(s.Fields), s.Fields)
+	}
+
+	want := map[string]field{
+		"Repo":   {GoName: "Repo", GoType: "string", JSONName: "repo", Required: true},
+		"Commit": {GoName: "Commit", GoType: "string", JSONName: "commit", Required: true},
+		"Path":   {GoName: "Path", GoType: "string", JSONName: "path", OmitEmpty: true},
+	}
+	for _, f := range s.Fields {
+		w, ok := want[f.GoName]
+		if !ok {
+			t.Fatalf("unexpected field %q", f.GoName)
+		}
+		if f != w {
+			t.Errorf("field %q: got %+v, want %+v", f.GoName, f, w)
+		}
+	}
+}
+
+func TestLoadSchemaMissingType(t *testing.T) {
+	path := writeTestSchema(t)
+	if _, err := loadSchema(path, "NoSuchType"); err == nil {
+		t.Fatal("expected an error for a type that doesn't exist")
+	}
+}
+
+func TestWriteCodecProducesValidGo(t *testing.T) {
+	path := writeTestSchema(t)
+	s, err := loadSchema(path, "SourceUnit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "source_unit_gen.go")
+	if err := writeCodec(out, s); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := string(data)
+	for _, want := range []string{
+		"package example",
+		"func (u SourceUnit) MarshalJSON()",
+		"func (u *SourceUnit) UnmarshalJSON(",
+		`missing required field %q", "repo"`,
+		`missing required field %q", "commit"`,
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("generated code missing %q:\n%s", want, generated)
+		}
+	}
+	if strings.Contains(generated, `missing required field %q", "path"`) {
+		t.Errorf("Path isn't truffle:\"required\" and shouldn't be validated:\n%s", generated)
+	}
+}
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/gitparse"
+)
+
+// Backend abstracts how ScanCommits and ScanUnstaged read a repository's
+// history, so both can run against either the system git binary (today's
+// default) or a pure Go-git implementation that needs no git binary in
+// $PATH - useful for hermetic CI images and Windows, where shelling out to
+// git isn't always an option. Both methods return the same gitparse.Commit
+// channel shape the rest of this package already consumes, so swapping the
+// backend out is invisible to ScanCommits/ScanUnstaged beyond which one they
+// call.
+type Backend interface {
+	// LogDiffs streams one gitparse.Commit per commit reachable from
+	// headHash in the repository at path (or the full history, if
+	// headHash is empty), each carrying its file diffs.
+	LogDiffs(ctx context.Context, path, headHash string) (chan gitparse.Commit, error)
+
+	// UnstagedDiffs streams a single gitparse.Commit representing the
+	// repository's uncommitted changes at path.
+	UnstagedDiffs(ctx context.Context, path string) (chan gitparse.Commit, error)
+}
+
+// cliBackend is the default Backend: it shells out to the system git
+// binary via gitparse, exactly as this package has always done. It
+// requires git (and git-lfs, for LFS-aware scans) to be present in $PATH.
+type cliBackend struct{}
+
+func (cliBackend) LogDiffs(ctx context.Context, path, headHash string) (chan gitparse.Commit, error) {
+	return gitparse.RepoPath(ctx, path, headHash)
+}
+
+func (cliBackend) UnstagedDiffs(ctx context.Context, path string) (chan gitparse.Commit, error) {
+	return gitparse.Unstaged(ctx, path)
+}
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"io"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// binaryChunkOverlap is how many trailing bytes of one raw binary chunk are
+// repeated at the start of the next, so a secret straddling a chunk
+// boundary still appears whole in at least one chunk sent downstream.
+const binaryChunkOverlap = sources.PeekSize
+
+// chunkBinaryStream reads reader in bounded, sources.ChunkSize-sized pieces
+// and emits each as its own chunk on chunksChan, instead of buffering the
+// whole blob into memory the way a single io.ReadAll(reader) used to. Each
+// chunk is prefixed with the trailing binaryChunkOverlap bytes of the
+// previous one, so a detector scanning any single chunk still sees whole
+// any secret that happened to fall across a chunk boundary.
+func chunkBinaryStream(reader io.Reader, chunkSkel *sources.Chunk, chunksChan chan *sources.Chunk) error {
+	buf := make([]byte, sources.ChunkSize)
+	var overlap []byte
+
+	for {
+		n, readErr := io.ReadFull(reader, buf)
+		if n > 0 {
+			data := make([]byte, 0, len(overlap)+n)
+			data = append(data, overlap...)
+			data = append(data, buf[:n]...)
+
+			chunk := *chunkSkel
+			chunk.Data = data
+			chunksChan <- &chunk
+
+			if n >= binaryChunkOverlap {
+				overlap = append([]byte(nil), buf[n-binaryChunkOverlap:n]...)
+			} else {
+				overlap = append([]byte(nil), buf[:n]...)
+			}
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
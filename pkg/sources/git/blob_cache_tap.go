@@ -0,0 +1,31 @@
+package git
+
+import "github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+
+// tapChunks returns a channel that forwards every chunk sent to it on to
+// out, while also appending each chunk's Data to *captured, so a caller
+// populating a blob cache can record exactly what was emitted for a blob
+// without the producers of those chunks (handlers.HandleFile,
+// chunkBinaryStream, ...) needing to know anything about caching.
+//
+// The returned wait func must be called once the caller is done sending to
+// the returned channel; it closes the channel and blocks until every
+// already-sent chunk has been forwarded to out and recorded, so *captured
+// is safe to read immediately afterward.
+func tapChunks(out chan *sources.Chunk, captured *[][]byte) (tapped chan *sources.Chunk, wait func()) {
+	tapped = make(chan *sources.Chunk)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for chunk := range tapped {
+			*captured = append(*captured, chunk.Data)
+			out <- chunk
+		}
+	}()
+
+	return tapped, func() {
+		close(tapped)
+		<-done
+	}
+}
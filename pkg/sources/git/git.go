@@ -4,13 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,14 +19,13 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/google/go-github/v42/github"
 	"github.com/rs/zerolog"
 	log "github.com/sirupsen/logrus"
-	"golang.org/x/oauth2"
 	"golang.org/x/sync/semaphore"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
+	"github.com/trufflesecurity/trufflehog/v3/pkg/blobcache"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/gitparse"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/handlers"
@@ -34,6 +33,7 @@ import (
 	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/sanitizer"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/gitfilter"
 )
 
 type Source struct {
@@ -55,6 +55,89 @@ type Git struct {
 	sourceMetadataFunc func(file, email, commit, timestamp, repository string, line int64) *source_metadatapb.MetaData
 	verify             bool
 	concurrency        *semaphore.Weighted
+	backend            Backend
+	maxBinaryFileSize  int64
+	maxBlobRetries     int
+	minRetryDelay      time.Duration
+	maxRetryDelay      time.Duration
+	blobCache          *blobcache.Cache
+	exclusionConfig    *sources.ExclusionConfig
+	respectGitignore   bool
+}
+
+// SetMaxBinaryFileSize caps how large a binary blob handleBinary will
+// bother chunking at all; blobs over this size are logged and skipped
+// outright. A size of 0 (the default) means no cap.
+func (s *Git) SetMaxBinaryFileSize(size int64) {
+	s.maxBinaryFileSize = size
+}
+
+// SetBlobRetryConfig configures how handleBinary retries a transient error
+// reading a git blob: up to maxRetries attempts, with exponential backoff
+// between minDelay and maxDelay. maxRetries of 0 disables retrying.
+func (s *Git) SetBlobRetryConfig(maxRetries int, minDelay, maxDelay time.Duration) {
+	s.maxBlobRetries = maxRetries
+	s.minRetryDelay = minDelay
+	s.maxRetryDelay = maxDelay
+}
+
+// SetBlobCache installs a blob cache that handleBinary consults before
+// reading and scanning a binary blob's content, and populates with the
+// resulting chunks on a miss. Since git blobs are content-addressed, this
+// lets a binary that recurs unchanged across many commits in a long-history
+// repository be scanned once instead of once per commit it appears in. A
+// nil cache (the default) disables this behavior.
+func (s *Git) SetBlobCache(cache *blobcache.Cache) {
+	s.blobCache = cache
+}
+
+// defaultBlobCache, set via SetDefaultBlobCache, is applied to every Git
+// source built afterward by NewGit. There's no path from the CLI's flag
+// parsing down to each individual Source's construction deep inside the
+// engine, so - like the engine's other global scan knobs - this is
+// configured once up front rather than threaded through as a constructor
+// parameter.
+var defaultBlobCache *blobcache.Cache
+
+// SetDefaultBlobCache sets the blob cache that NewGit installs on every Git
+// source created afterward. Call it once during startup, before any
+// sources are constructed.
+func SetDefaultBlobCache(cache *blobcache.Cache) {
+	defaultBlobCache = cache
+}
+
+// defaultExclusionConfig, set via SetDefaultExclusionConfig, is consulted by
+// every Git source built afterward by NewGit, for the same reason
+// defaultBlobCache is: there's no path from the CLI's --exclusion-config
+// flag down to each individual Source's construction.
+var defaultExclusionConfig *sources.ExclusionConfig
+
+// SetDefaultExclusionConfig sets the exclusion config that NewGit installs
+// on every Git source created afterward. Call it once during startup,
+// before any sources are constructed.
+func SetDefaultExclusionConfig(cfg *sources.ExclusionConfig) {
+	defaultExclusionConfig = cfg
+}
+
+// SetRespectGitignore toggles whether ScanCommits consults the scanned
+// repository's own .gitignore files (plus $GIT_DIR/info/exclude, the user's
+// global excludes file, and .gitattributes linguist-generated markers)
+// before scanning a file's diff, on top of exclusionConfig's source-agnostic
+// skip list. Off by default, matching historical behavior.
+func (s *Git) SetRespectGitignore(respect bool) {
+	s.respectGitignore = respect
+}
+
+// defaultRespectGitignore, set via SetDefaultRespectGitignore, is applied to
+// every Git source built afterward by NewGit, for the same reason
+// defaultBlobCache is.
+var defaultRespectGitignore bool
+
+// SetDefaultRespectGitignore sets whether NewGit installs gitignore-aware
+// filtering on every Git source created afterward. Call it once during
+// startup, before any sources are constructed.
+func SetDefaultRespectGitignore(respect bool) {
+	defaultRespectGitignore = respect
 }
 
 func NewGit(sourceType sourcespb.SourceType, jobID, sourceID int64, sourceName string, verify bool, concurrency int,
@@ -68,9 +151,35 @@ func NewGit(sourceType sourcespb.SourceType, jobID, sourceID int64, sourceName s
 		sourceMetadataFunc: sourceMetadataFunc,
 		verify:             verify,
 		concurrency:        semaphore.NewWeighted(int64(concurrency)),
+		backend:            cliBackend{},
+		maxBlobRetries:     defaultMaxBlobRetries,
+		minRetryDelay:      defaultMinRetryDelay,
+		maxRetryDelay:      defaultMaxRetryDelay,
+		blobCache:          defaultBlobCache,
+		exclusionConfig:    defaultExclusionConfig,
+		respectGitignore:   defaultRespectGitignore,
 	}
 }
 
+// UseGoGitBackend switches s to the pure Go-git backend, which walks commit
+// history and diffs trees entirely in-process instead of shelling out to
+// the system git binary. Call it for hermetic scans (CI images, Windows)
+// that can't rely on git being on $PATH; leave the default cliBackend in
+// place when features that still require the CLI - shallow clones,
+// `git-lfs` resolution - are in play.
+func (s *Git) UseGoGitBackend() {
+	s.backend = goGitBackend{}
+}
+
+// backendOrDefault returns s.backend, falling back to cliBackend for any
+// Git built by struct literal rather than NewGit.
+func (s *Git) backendOrDefault() Backend {
+	if s.backend == nil {
+		return cliBackend{}
+	}
+	return s.backend
+}
+
 // Ensure the Source satisfies the interface at compile time.
 var _ sources.Source = (*Source)(nil)
 
@@ -128,62 +237,14 @@ func (s *Source) Init(aCtx context.Context, name string, jobId, sourceId int64,
 
 // Chunks emits chunks of bytes over a channel.
 func (s *Source) Chunks(ctx context.Context, chunksChan chan *sources.Chunk) error {
-	// TODO: refactor to remove duplicate code
-	switch cred := s.conn.GetCredential().(type) {
-	case *sourcespb.Git_BasicAuth:
-		user := cred.BasicAuth.Username
-		token := cred.BasicAuth.Password
-
-		for i, repoURI := range s.conn.Repositories {
-			s.SetProgressComplete(i, len(s.conn.Repositories), fmt.Sprintf("Repo: %s", repoURI), "")
-			if len(repoURI) == 0 {
-				continue
-			}
-			err := func(repoURI string) error {
-				path, repo, err := CloneRepoUsingToken(token, repoURI, user)
-				defer os.RemoveAll(path)
-				if err != nil {
-					return err
-				}
-				return s.git.ScanRepo(ctx, repo, path, NewScanOptions(), chunksChan)
-			}(repoURI)
-			if err != nil {
-				return err
-			}
-		}
-	case *sourcespb.Git_Unauthenticated:
+	switch s.conn.GetCredential().(type) {
+	case *sourcespb.Git_BasicAuth, *sourcespb.Git_Unauthenticated, *sourcespb.Git_SshAuth:
 		for i, repoURI := range s.conn.Repositories {
 			s.SetProgressComplete(i, len(s.conn.Repositories), fmt.Sprintf("Repo: %s", repoURI), "")
 			if len(repoURI) == 0 {
 				continue
 			}
-			err := func(repoURI string) error {
-				path, repo, err := CloneRepoUsingUnauthenticated(repoURI)
-				defer os.RemoveAll(path)
-				if err != nil {
-					return err
-				}
-				return s.git.ScanRepo(ctx, repo, path, NewScanOptions(), chunksChan)
-			}(repoURI)
-			if err != nil {
-				return err
-			}
-		}
-	case *sourcespb.Git_SshAuth:
-		for i, repoURI := range s.conn.Repositories {
-			s.SetProgressComplete(i, len(s.conn.Repositories), fmt.Sprintf("Repo: %s", repoURI), "")
-			if len(repoURI) == 0 {
-				continue
-			}
-			err := func(repoURI string) error {
-				path, repo, err := CloneRepoUsingSSH(repoURI)
-				defer os.RemoveAll(path)
-				if err != nil {
-					return err
-				}
-				return s.git.ScanRepo(ctx, repo, path, NewScanOptions(), chunksChan)
-			}(repoURI)
-			if err != nil {
+			if err := s.scanRepoOnce(ctx, repoURI, NewScanOptions(), chunksChan); err != nil {
 				return err
 			}
 		}
@@ -231,6 +292,43 @@ func CleanOnError(err *error, path string) {
 	}
 }
 
+// ObjectFormat identifies the hash algorithm a git repository's object
+// database was created with.
+type ObjectFormat string
+
+const (
+	ObjectFormatSHA1   ObjectFormat = "sha1"
+	ObjectFormatSHA256 ObjectFormat = "sha256"
+)
+
+// DetectObjectFormat reports the object format of the repo cloned at path,
+// read from its `extensions.objectFormat` config (unset, the default,
+// means SHA-1). `git clone` negotiates the remote's object format itself,
+// so CloneRepo needs no extra flag to fetch a SHA-256 repo correctly -
+// this is only for callers that need to know which format they got.
+//
+// Note this repo's go-git dependency is the stock, non-"sha256"-build-tag
+// build, whose plumbing.Hash is a fixed 20-byte array: it can open a
+// SHA-256 repo but plumbing.NewHash on a 64-hex-char object ID will
+// silently truncate it. Until that build tag is wired in, callers that
+// get ObjectFormatSHA256 back should not assume the go-git read path
+// (ScanRepo and friends) handles the repo's hashes correctly; the
+// CLI-text path (gitparse.RepoPath) already accepts either length.
+func DetectObjectFormat(path string) (ObjectFormat, error) {
+	cmd := exec.Command("git", "-C", path, "config", "--get", "extensions.objectFormat")
+	out, err := cmd.Output()
+	if err != nil {
+		// A non-zero exit here means the key is unset, which means SHA-1.
+		return ObjectFormatSHA1, nil
+	}
+	switch strings.TrimSpace(string(out)) {
+	case "sha256":
+		return ObjectFormatSHA256, nil
+	default:
+		return ObjectFormatSHA1, nil
+	}
+}
+
 func gitURLParse(gitURL string) (*url.URL, error) {
 	parsedURL, originalError := url.Parse(gitURL)
 	if originalError != nil {
@@ -277,10 +375,7 @@ func CloneRepo(userInfo *url.Userinfo, gitUrl string, args ...string) (clonePath
 		return "", nil, errors.New("clone command exited with no output")
 	}
 	if cloneCmd.ProcessState != nil && cloneCmd.ProcessState.ExitCode() != 0 {
-		safeUrl, err := stripPassword(gitUrl)
-		if err != nil {
-			log.WithError(err).Errorf("failed to strip credentials from git url")
-		}
+		safeUrl := RedactRemoteURL(gitUrl)
 		log.WithField("exit_code", cloneCmd.ProcessState.ExitCode()).WithField("repo", safeUrl).WithField("output", string(output)).Errorf("failed to clone repo")
 		return "", nil, fmt.Errorf("could not clone repo: %s", safeUrl)
 	}
@@ -289,10 +384,26 @@ func CloneRepo(userInfo *url.Userinfo, gitUrl string, args ...string) (clonePath
 		err = errors.WrapPrefix(err, "could not open cloned repo", 0)
 		return
 	}
-	log.WithField("clone_path", clonePath).WithField("repo", gitUrl).Debug("cloned repo")
+	log.WithField("clone_path", clonePath).WithField("repo", RedactRemoteURL(gitUrl)).Debug("cloned repo")
 	return
 }
 
+// ShallowCloneArgs builds the extra `git clone` arguments needed to clone
+// shallowly and/or restrict the clone to a single branch, for passing as
+// the trailing args to CloneRepo/CloneRepoUsingToken/
+// CloneRepoUsingUnauthenticated. depth of zero means full history; an
+// empty singleBranch means all branches.
+func ShallowCloneArgs(depth int, singleBranch string) []string {
+	var args []string
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	if singleBranch != "" {
+		args = append(args, "--single-branch", "--branch", singleBranch)
+	}
+	return args
+}
+
 // CloneRepoUsingToken clones a repo using a provided token.
 func CloneRepoUsingToken(token, gitUrl, user string, args ...string) (string, *git.Repository, error) {
 	userInfo := url.UserPassword(user, token)
@@ -325,7 +436,7 @@ func (s *Git) ScanCommits(ctx context.Context, repo *git.Repository, path string
 		zerolog.SetGlobalLevel(zerolog.Disabled)
 	}
 
-	commitChan, err := gitparse.RepoPath(ctx, path, scanOptions.HeadHash)
+	commitChan, err := s.backendOrDefault().LogDiffs(ctx, path, scanOptions.HeadHash)
 	if err != nil {
 		return err
 	}
@@ -336,6 +447,16 @@ func (s *Git) ScanCommits(ctx context.Context, repo *git.Repository, path string
 	// get the URL metadata for reporting (may be empty)
 	urlMetadata := getSafeRemoteURL(repo, "origin")
 
+	// ignoreStack and attrStack are nil unless respectGitignore is set, so
+	// every check against them below is a cheap nil comparison for the
+	// (historically default) case where this scan doesn't want them.
+	var ignoreStack *gitfilter.Stack
+	var attrStack *gitfilter.AttributeStack
+	if s.respectGitignore {
+		ignoreStack = gitfilter.NewStack(path, filepath.Join(path, ".git"))
+		attrStack = gitfilter.NewAttributeStack(path)
+	}
+
 	var depth int64
 	var reachedBase = false
 	log.WithField("repo", urlMetadata).Debugf("Scanning repo")
@@ -355,6 +476,10 @@ func (s *Git) ScanCommits(ctx context.Context, repo *git.Repository, path string
 				reachedBase = true
 			}
 		}
+		if !scanOptions.Since.IsZero() && commit.Date.Before(scanOptions.Since) {
+			log.Debugf("reached commit older than --since, skipping remainder of history")
+			break
+		}
 		for _, diff := range commit.Diffs {
 			log.WithField("commit", commit.Hash).WithField("file", diff.PathB).Trace("Scanning file from git")
 
@@ -366,6 +491,15 @@ func (s *Git) ScanCommits(ctx context.Context, repo *git.Repository, path string
 			if fileName == "" {
 				continue
 			}
+			if s.exclusionConfig.ShouldSkipPath(fileName) {
+				continue
+			}
+			if ignoreStack != nil && ignoreStack.Match(fileName, false) == gitfilter.Exclude {
+				continue
+			}
+			if attrStack != nil && attrStack.HasAttribute(fileName, "linguist-generated") {
+				continue
+			}
 			var email, hash, when string
 			email = commit.Author
 			hash = commit.Hash
@@ -382,12 +516,16 @@ func (s *Git) ScanCommits(ctx context.Context, repo *git.Repository, path string
 					SourceMetadata: metadata,
 					Verify:         s.verify,
 				}
-				if err := handleBinary(ctx, repo, chunksChan, chunkSkel, commitHash, fileName); err != nil {
+				if err := s.handleBinary(ctx, repo, path, chunksChan, chunkSkel, commitHash, fileName); err != nil {
 					log.WithError(err).WithField("file", fileName).Debug("Error handling binary file")
 				}
 				continue
 			}
 
+			if s.exclusionConfig.ShouldSkipData(diff.Content.Bytes()) {
+				continue
+			}
+
 			if diff.Content.Len() > sources.ChunkSize+sources.PeekSize {
 				s.gitChunk(diff, fileName, email, hash, when, urlMetadata, chunksChan)
 				continue
@@ -467,7 +605,7 @@ func (s *Git) ScanUnstaged(ctx context.Context, repo *git.Repository, path strin
 	// get the URL metadata for reporting (may be empty)
 	urlMetadata := getSafeRemoteURL(repo, "origin")
 
-	commitChan, err := gitparse.Unstaged(ctx, path)
+	commitChan, err := s.backendOrDefault().UnstagedDiffs(ctx, path)
 	if err != nil {
 		return err
 	}
@@ -475,6 +613,13 @@ func (s *Git) ScanUnstaged(ctx context.Context, repo *git.Repository, path strin
 		return nil
 	}
 
+	var ignoreStack *gitfilter.Stack
+	var attrStack *gitfilter.AttributeStack
+	if s.respectGitignore {
+		ignoreStack = gitfilter.NewStack(path, filepath.Join(path, ".git"))
+		attrStack = gitfilter.NewAttributeStack(path)
+	}
+
 	var depth int64
 	var reachedBase = false
 	log.Debugf("Scanning repo")
@@ -504,6 +649,15 @@ func (s *Git) ScanUnstaged(ctx context.Context, repo *git.Repository, path strin
 			if fileName == "" {
 				continue
 			}
+			if s.exclusionConfig.ShouldSkipPath(fileName) {
+				continue
+			}
+			if ignoreStack != nil && ignoreStack.Match(fileName, false) == gitfilter.Exclude {
+				continue
+			}
+			if attrStack != nil && attrStack.HasAttribute(fileName, "linguist-generated") {
+				continue
+			}
 			var email, hash, when string
 			email = commit.Author
 			hash = commit.Hash
@@ -520,12 +674,16 @@ func (s *Git) ScanUnstaged(ctx context.Context, repo *git.Repository, path strin
 					SourceMetadata: metadata,
 					Verify:         s.verify,
 				}
-				if err := handleBinary(ctx, repo, chunksChan, chunkSkel, commitHash, fileName); err != nil {
+				if err := s.handleBinary(ctx, repo, path, chunksChan, chunkSkel, commitHash, fileName); err != nil {
 					log.WithError(err).WithField("file", fileName).Debug("Error handling binary file")
 				}
 				continue
 			}
 
+			if s.exclusionConfig.ShouldSkipData(diff.Content.Bytes()) {
+				continue
+			}
+
 			metadata := s.sourceMetadataFunc(fileName, email, "Unstaged", when, urlMetadata, int64(diff.LineStart))
 			chunksChan <- &sources.Chunk{
 				SourceName:     s.sourceName,
@@ -551,6 +709,11 @@ func (s *Git) ScanRepo(ctx context.Context, repo *git.Repository, repoPath strin
 	if err := s.ScanUnstaged(ctx, repo, repoPath, scanOptions, chunksChan); err != nil {
 		log.WithError(err).Error("Error scanning unstaged changes")
 	}
+	if scanOptions.Submodules {
+		if err := s.scanSubmodules(ctx, repo, repoPath, scanOptions, chunksChan, map[string]bool{}, 0); err != nil {
+			log.WithError(err).Error("Error scanning submodules")
+		}
+	}
 	scanTime := time.Now().UnixNano() - start
 	log.Debugf("Scanning complete. Scan time: %f", time.Duration(scanTime).Seconds())
 	return nil
@@ -619,21 +782,6 @@ func GenerateLink(repo, commit, file string) string {
 	return link
 }
 
-func stripPassword(u string) (string, error) {
-	if strings.HasPrefix(u, "git@") {
-		return u, nil
-	}
-
-	repoURL, err := url.Parse(u)
-	if err != nil {
-		return "", errors.WrapPrefix(err, "repo remote cannot be sanitized as URI", 0)
-	}
-
-	repoURL.User = nil
-
-	return repoURL.String(), nil
-}
-
 // TryAdditionalBaseRefs looks for additional possible base refs for a repo and returns a hash if found.
 func TryAdditionalBaseRefs(repo *git.Repository, base string) (*plumbing.Hash, error) {
 	revisionPrefixes := []string{
@@ -663,46 +811,25 @@ func PrepareRepoSinceCommit(uriString, commitHash string) (string, bool, error)
 	// TODO: refactor with PrepareRepo to remove duplicated logic
 
 	// The git CLI doesn't have an option to shallow clone starting at a commit
-	// hash, but it does have an option to shallow clone since a timestamp. If
-	// the uriString is github.com, then we query the API for the timestamp of the
-	// hash and use that to clone.
+	// hash, but it does have an option to shallow clone since a timestamp, so
+	// resolve commitHash's committer date with a throwaway partial clone, then
+	// shallow clone the real destination since that date. Unlike the GitHub
+	// API call this used to make, this works against any git host.
 
 	uri, err := gitURLParse(uriString)
 	if err != nil {
 		return "", false, fmt.Errorf("unable to parse Git URI: %s", err)
 	}
 
-	if uri.Scheme == "file" || uri.Host != "github.com" {
-		return PrepareRepo(uriString)
-	}
-
-	uriPath := strings.TrimPrefix(uri.Path, "/")
-	owner, repoName, found := strings.Cut(uriPath, "/")
-	if !found {
+	if uri.Scheme == "file" {
 		return PrepareRepo(uriString)
 	}
 
-	client := github.NewClient(nil)
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: token},
-		)
-		tc := oauth2.NewClient(context.TODO(), ts)
-		client = github.NewClient(tc)
-	}
-
-	commit, _, err := client.Git.GetCommit(context.Background(), owner, repoName, commitHash)
+	timestamp, err := resolveCommitTimestamp(uri.String(), commitHash)
 	if err != nil {
+		log.WithError(err).WithField("commit", commitHash).Debug("unable to resolve commit timestamp for shallow clone, falling back to full clone")
 		return PrepareRepo(uriString)
 	}
-	var timestamp string
-	{
-		author := commit.GetAuthor()
-		if author == nil {
-			return PrepareRepo(uriString)
-		}
-		timestamp = author.GetDate().Format(time.RFC3339)
-	}
 
 	remotePath := uri.String()
 	var path string
@@ -728,6 +855,34 @@ func PrepareRepoSinceCommit(uriString, commitHash string) (string, bool, error)
 	return path, true, nil
 }
 
+// resolveCommitTimestamp resolves commitHash's committer date in the
+// repository at remotePath, for use as the --shallow-since cutoff of a
+// later shallow clone. It does this with a `--filter=blob:none` partial
+// clone into a scratch directory - which fetches full commit history but no
+// file contents, so it stays cheap even against large repositories - then
+// reads the date off the resolved commit with `git show` and removes the
+// scratch clone.
+func resolveCommitTimestamp(remotePath, commitHash string) (string, error) {
+	scratchDir, err := ioutil.TempDir(os.TempDir(), "trufflehog-shallow-since")
+	if err != nil {
+		return "", fmt.Errorf("creating scratch dir: %w", err)
+	}
+	defer os.RemoveAll(scratchDir)
+
+	cloneCmd := exec.Command("git", "clone", "--filter=blob:none", "--no-checkout", remotePath, scratchDir)
+	if out, err := cloneCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("partial clone of %s failed: %w: %s", remotePath, err, out)
+	}
+
+	showCmd := exec.Command("git", "-C", scratchDir, "show", "-s", "--format=%cI", commitHash)
+	out, err := showCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git show of %s failed: %w", commitHash, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
 // PrepareRepo clones a repo if possible and returns the cloned repo path.
 func PrepareRepo(uriString string) (string, bool, error) {
 	var path string
@@ -791,26 +946,54 @@ func getSafeRemoteURL(repo *git.Repository, preferred string) string {
 		remote = remotes[0]
 	}
 	// URLs is guaranteed to be non-empty
-	safeURL, err := stripPassword(remote.Config().URLs[0])
-	if err != nil {
-		return ""
-	}
-	return safeURL
+	return RedactRemoteURL(remote.Config().URLs[0])
 }
 
-func handleBinary(ctx context.Context, repo *git.Repository, chunksChan chan *sources.Chunk, chunkSkel *sources.Chunk, commitHash plumbing.Hash, path string) error {
-	log.WithField("path", path).Trace("Binary file found in repository.")
+func (s *Git) handleBinary(ctx context.Context, repo *git.Repository, repoPath string, chunksChan chan *sources.Chunk, chunkSkel *sources.Chunk, commitHash plumbing.Hash, filePath string) error {
+	log.WithField("path", filePath).Trace("Binary file found in repository.")
 	commit, err := repo.CommitObject(commitHash)
 	if err != nil {
 		return err
 	}
 
-	file, err := commit.File(path)
+	file, err := commit.File(filePath)
 	if err != nil {
 		return err
 	}
 
-	fileReader, err := file.Reader()
+	if s.maxBinaryFileSize > 0 && file.Size > s.maxBinaryFileSize {
+		log.WithField("path", filePath).WithField("size", file.Size).Info("Binary file exceeds MaxBinaryFileSize, skipping")
+		return nil
+	}
+
+	blobHash := file.Hash.String()
+	if s.blobCache != nil {
+		if cached, ok, err := s.blobCache.Get(blobHash); err != nil {
+			log.WithError(err).WithField("blob", blobHash).Debug("Error reading blob cache, scanning normally")
+		} else if ok {
+			log.WithField("path", filePath).WithField("blob", blobHash).Trace("Blob cache hit, replaying cached chunks")
+			for _, data := range cached {
+				chunk := *chunkSkel
+				chunk.Data = data
+				chunksChan <- &chunk
+			}
+			return nil
+		}
+	}
+
+	var cachedChunks [][]byte
+	if s.blobCache != nil {
+		tapped, wait := tapChunks(chunksChan, &cachedChunks)
+		defer func() {
+			wait()
+			if err := s.blobCache.Put(blobHash, cachedChunks); err != nil {
+				log.WithError(err).WithField("blob", blobHash).Debug("Error writing blob cache")
+			}
+		}()
+		chunksChan = tapped
+	}
+
+	fileReader, err := newRetryingBlobReader(file.Reader, s.maxBlobRetries, s.minRetryDelay, s.maxRetryDelay)
 	if err != nil {
 		return err
 	}
@@ -821,24 +1004,24 @@ func handleBinary(ctx context.Context, repo *git.Repository, chunksChan chan *so
 		return err
 	}
 
+	if lfsData, ok, err := tryResolveLFSPointer(ctx, repoPath, reader, commitHash.String(), filePath); err != nil {
+		log.WithError(err).WithField("path", filePath).Debug("Error resolving Git LFS pointer, falling back to raw pointer content")
+	} else if ok {
+		chunk := *chunkSkel
+		chunk.Data = lfsData
+		chunksChan <- &chunk
+		return nil
+	}
+
 	if handlers.HandleFile(ctx, reader, chunkSkel, chunksChan) {
 		return nil
 	}
 
-	log.WithField("path", path).Trace("Binary file is not recognized by file handlers. Chunking raw.")
+	log.WithField("path", filePath).Trace("Binary file is not recognized by file handlers. Chunking raw.")
 	if err := reader.Reset(); err != nil {
 		return err
 	}
 	reader.Stop()
 
-	chunkData, err := io.ReadAll(reader)
-	if err != nil {
-		return err
-	}
-
-	chunk := *chunkSkel
-	chunk.Data = chunkData
-	chunksChan <- &chunk
-
-	return nil
+	return chunkBinaryStream(reader, chunkSkel, chunksChan)
 }
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/gitparse"
+)
+
+// goGitBackend is a pure Go-git Backend: it walks commit history and diffs
+// trees entirely in-process, via go-git, so ScanCommits/ScanUnstaged can run
+// in environments (minimal containers, Windows) where a git binary isn't
+// guaranteed to be on $PATH. It trades some of the CLI backend's speed and
+// flag surface (shallow clones, git-lfs) for that portability.
+type goGitBackend struct{}
+
+// LogDiffs delegates to gitparse.RepoPathNative, which does this same
+// commit-graph walk and per-commit patch building directly against
+// go-git's plumbing - see that function for the walk/diff details.
+// headHash empty means "every reference", matching this method's existing
+// documented behavior (previously implemented here by falling back to
+// repo.Head(), which only ever covered the current HEAD, not the full
+// history `git log --all` exposes to the cliBackend).
+func (goGitBackend) LogDiffs(ctx context.Context, path, headHash string) (chan gitparse.Commit, error) {
+	return gitparse.RepoPathNative(ctx, path, headHash)
+}
+
+func (goGitBackend) UnstagedDiffs(ctx context.Context, path string) (chan gitparse.Commit, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening repository at %s: %w", path, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("getting worktree for %s: %w", path, err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("getting worktree status for %s: %w", path, err)
+	}
+
+	commitChan := make(chan gitparse.Commit, 1)
+	go func() {
+		defer close(commitChan)
+
+		out := gitparse.Commit{Hash: ""}
+		for file, fileStatus := range status {
+			if ctx.Err() != nil {
+				return
+			}
+			if fileStatus.Worktree == git.Unmodified {
+				continue
+			}
+
+			content, err := os.ReadFile(filepath.Join(path, file))
+			if err != nil {
+				// Deleted or otherwise unreadable files have nothing left to scan.
+				continue
+			}
+
+			diff := gitparse.Diff{PathA: file, PathB: file}
+			diff.Content.Write(content)
+			out.Diffs = append(out.Diffs, diff)
+		}
+
+		commitChan <- out
+	}()
+
+	return commitChan, nil
+}
@@ -0,0 +1,165 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MirrorCache keeps a persistent, bare "mirror" clone per repository under
+// Root, so repeated scans of the same repository only have to fetch new
+// commits instead of re-cloning from scratch every run. Its on-disk layout
+// mirrors gickup's Structured local backend: Root/<hoster>/<owner>/<repo>.git.
+type MirrorCache struct {
+	// Root is the directory mirrors are stored under, e.g.
+	// ~/.cache/trufflehog/mirrors.
+	Root string
+
+	// KeepVersions is how many historical snapshots of a mirror to retain
+	// across Sync calls, for forensic re-scans of a repo's state as of a
+	// prior run. Zero means don't snapshot at all; only the live mirror is
+	// kept.
+	KeepVersions int
+}
+
+// MirrorPath returns the structured on-disk path for a given repository's
+// bare mirror, without creating or touching anything.
+func (m *MirrorCache) MirrorPath(hoster, owner, repo string) string {
+	return filepath.Join(m.Root, hoster, owner, repo+".git")
+}
+
+// Sync ensures a bare mirror of cloneURL exists at MirrorPath(hoster, owner,
+// repo), cloning it if this is the first time this repository has been
+// seen, or running `git remote update --prune` to fetch new refs if a
+// mirror already exists. It returns the mirror's on-disk path for use as
+// the repo argument to ScanCommits/ScanUnstaged.
+func (m *MirrorCache) Sync(hoster, owner, repo, cloneURL string) (string, error) {
+	path := m.MirrorPath(hoster, owner, repo)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", fmt.Errorf("creating mirror parent dir: %w", err)
+		}
+		cmd := exec.Command("git", "clone", "--mirror", cloneURL, path)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git clone --mirror: %w: %s", err, out)
+		}
+		return path, nil
+	} else if err != nil {
+		return "", fmt.Errorf("checking for existing mirror: %w", err)
+	}
+
+	if err := m.snapshot(path); err != nil {
+		return "", fmt.Errorf("snapshotting mirror before update: %w", err)
+	}
+
+	cmd := exec.Command("git", "--git-dir", path, "remote", "update", "--prune")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git remote update --prune: %w: %s", err, out)
+	}
+
+	return path, nil
+}
+
+// snapshot copies path's current state aside before it's updated in place,
+// so a prior run's exact commit history can still be scanned later, then
+// prunes the oldest snapshots beyond KeepVersions. It's a no-op when
+// KeepVersions is zero.
+func (m *MirrorCache) snapshot(path string) error {
+	if m.KeepVersions <= 0 {
+		return nil
+	}
+
+	snapshotPath := fmt.Sprintf("%s.snapshot.%d", path, time.Now().UnixNano())
+	cmd := exec.Command("cp", "-r", path, snapshotPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("copying snapshot: %w: %s", err, out)
+	}
+
+	return m.pruneSnapshots(path)
+}
+
+// pruneSnapshots removes the oldest snapshots of path beyond KeepVersions.
+func (m *MirrorCache) pruneSnapshots(path string) error {
+	matches, err := filepath.Glob(path + ".snapshot.*")
+	if err != nil {
+		return fmt.Errorf("listing snapshots: %w", err)
+	}
+	sort.Strings(matches)
+
+	excess := len(matches) - m.KeepVersions
+	for i := 0; i < excess; i++ {
+		if err := os.RemoveAll(matches[i]); err != nil {
+			return fmt.Errorf("removing old snapshot %s: %w", matches[i], err)
+		}
+	}
+	return nil
+}
+
+// scanIndex is the on-disk record of the last commit scanned per ref for a
+// mirror, stored as a sibling of the mirror directory itself
+// (<mirror path>.index.json) so it travels with the mirror.
+type scanIndex struct {
+	// LastScanned maps ref name (e.g. "refs/heads/main") to the commit
+	// hash that was the HeadHash of the most recent completed scan.
+	LastScanned map[string]string `json:"last_scanned"`
+}
+
+func indexPath(mirrorPath string) string {
+	return mirrorPath + ".index.json"
+}
+
+func readIndex(mirrorPath string) (*scanIndex, error) {
+	data, err := os.ReadFile(indexPath(mirrorPath))
+	if os.IsNotExist(err) {
+		return &scanIndex{LastScanned: map[string]string{}}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading scan index: %w", err)
+	}
+
+	var idx scanIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing scan index: %w", err)
+	}
+	if idx.LastScanned == nil {
+		idx.LastScanned = map[string]string{}
+	}
+	return &idx, nil
+}
+
+func writeIndex(mirrorPath string, idx *scanIndex) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling scan index: %w", err)
+	}
+	return os.WriteFile(indexPath(mirrorPath), data, 0644)
+}
+
+// LastScanned returns the commit hash recorded as the end of the most
+// recent completed scan of ref in the mirror at mirrorPath, and false if
+// nothing has been recorded yet (e.g. this is the mirror's first scan).
+func (m *MirrorCache) LastScanned(mirrorPath, ref string) (string, bool) {
+	idx, err := readIndex(mirrorPath)
+	if err != nil {
+		return "", false
+	}
+	hash, ok := idx.LastScanned[strings.TrimSpace(ref)]
+	return hash, ok && hash != ""
+}
+
+// SetLastScanned records hash as the end of the most recently completed
+// scan of ref in the mirror at mirrorPath, so the next Sync/scan cycle can
+// pass it as ScanOptions.BaseHash and only chunk commits after it.
+func (m *MirrorCache) SetLastScanned(mirrorPath, ref, hash string) error {
+	idx, err := readIndex(mirrorPath)
+	if err != nil {
+		return err
+	}
+	idx.LastScanned[strings.TrimSpace(ref)] = hash
+	return writeIndex(mirrorPath, idx)
+}
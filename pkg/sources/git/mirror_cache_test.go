@@ -0,0 +1,63 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMirrorPath(t *testing.T) {
+	m := &MirrorCache{Root: "/cache/mirrors"}
+	got := m.MirrorPath("github.com", "trufflesecurity", "trufflehog")
+	want := filepath.Join("/cache/mirrors", "github.com", "trufflesecurity", "trufflehog.git")
+	if got != want {
+		t.Errorf("MirrorPath() = %q, want %q", got, want)
+	}
+}
+
+func TestLastScannedRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	mirrorPath := filepath.Join(dir, "repo.git")
+	m := &MirrorCache{Root: dir}
+
+	if _, ok := m.LastScanned(mirrorPath, "refs/heads/main"); ok {
+		t.Fatalf("expected no prior scan recorded")
+	}
+
+	if err := m.SetLastScanned(mirrorPath, "refs/heads/main", "deadbeef"); err != nil {
+		t.Fatalf("SetLastScanned: %v", err)
+	}
+
+	hash, ok := m.LastScanned(mirrorPath, "refs/heads/main")
+	if !ok || hash != "deadbeef" {
+		t.Errorf("LastScanned() = (%q, %v), want (\"deadbeef\", true)", hash, ok)
+	}
+}
+
+func TestPruneSnapshotsKeepsOnlyNewest(t *testing.T) {
+	dir := t.TempDir()
+	mirrorPath := filepath.Join(dir, "repo.git")
+	m := &MirrorCache{Root: dir, KeepVersions: 2}
+
+	for _, suffix := range []string{"1", "2", "3"} {
+		snap := mirrorPath + ".snapshot." + suffix
+		if err := os.MkdirAll(snap, 0755); err != nil {
+			t.Fatalf("creating fake snapshot %s: %v", snap, err)
+		}
+	}
+
+	if err := m.pruneSnapshots(mirrorPath); err != nil {
+		t.Fatalf("pruneSnapshots: %v", err)
+	}
+
+	matches, err := filepath.Glob(mirrorPath + ".snapshot.*")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 snapshots to remain, got %d: %v", len(matches), matches)
+	}
+	if _, err := os.Stat(mirrorPath + ".snapshot.1"); !os.IsNotExist(err) {
+		t.Errorf("expected oldest snapshot .1 to be pruned")
+	}
+}
@@ -0,0 +1,59 @@
+package git
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// denylistedURLParams are query parameter names that commonly carry a
+// credential on remotes that don't put one in the userinfo component at
+// all (some self-hosted / custom auth schemes pass a token as a query
+// param instead).
+var denylistedURLParams = []string{"token", "access_token", "api_key"}
+
+// scpLikeURL matches the SCP-like syntax git accepts for ssh remotes
+// without a scheme, e.g. "git@github.com:org/repo.git".
+var scpLikeURL = regexp.MustCompile(`^([^@/:]+)@([^:/]+):(.+)$`)
+
+// userinfoFallback strips a `scheme://user[:pass]@` userinfo component by
+// regex, for use only when url.Parse itself fails - a malformed URL should
+// never leak a raw credential into logs just because it couldn't be parsed
+// properly.
+var userinfoFallback = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*://)[^/@]+@`)
+
+// RedactRemoteURL returns u with any credential redacted: the entire
+// userinfo component (covering "user:pass@host" HTTPS basic auth,
+// "oauth2:<token>@host", "x-access-token:<pat>@host" GitHub App tokens,
+// and "<pat>@host" Azure DevOps-style username-only tokens alike - not just
+// the password half of a user:pass pair), the SCP-like "user@host:path"
+// ssh form, and any denylisted query parameter that carries a token
+// instead. It always returns a usable URL string, even when u can't be
+// parsed as one, so a malformed remote URL never leaks a raw secret into
+// logs or chunk metadata.
+func RedactRemoteURL(u string) string {
+	if m := scpLikeURL.FindStringSubmatch(u); m != nil {
+		return m[2] + ":" + m[3]
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return userinfoFallback.ReplaceAllString(u, "$1")
+	}
+
+	parsed.User = nil
+
+	if parsed.RawQuery != "" {
+		query := parsed.Query()
+		for _, key := range denylistedURLParams {
+			for k := range query {
+				if strings.EqualFold(k, key) {
+					query.Del(k)
+				}
+			}
+		}
+		parsed.RawQuery = query.Encode()
+	}
+
+	return parsed.String()
+}
@@ -0,0 +1,112 @@
+package git
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMaxBlobRetries = 3
+	defaultMinRetryDelay  = 50 * time.Millisecond
+	defaultMaxRetryDelay  = 5 * time.Second
+)
+
+// isRetryableBlobErr reports whether err reading a git object is likely
+// transient - worth retrying - as opposed to permanent, like the object
+// genuinely not existing. Transient errors are common against
+// network-backed or partial clones (go-git repos opened against a
+// `--filter=blob:none` or shallow GitDir), whose blob content is fetched
+// from the remote lazily on first read.
+func isRetryableBlobErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryingBlobReader wraps a git blob's Reader so that a transient error
+// mid-read reopens the blob (via open) and resumes from the last
+// successfully-read byte, rather than forcing the caller to re-fetch the
+// whole blob from offset zero.
+type retryingBlobReader struct {
+	open          func() (io.ReadCloser, error)
+	current       io.ReadCloser
+	read          int64
+	maxRetries    int
+	minRetryDelay time.Duration
+	maxRetryDelay time.Duration
+}
+
+// newRetryingBlobReader opens a blob via open and wraps it for transient-
+// error retry with resumption. maxRetries of 0 disables retrying entirely,
+// degrading to open's own error behavior.
+func newRetryingBlobReader(open func() (io.ReadCloser, error), maxRetries int, minDelay, maxDelay time.Duration) (*retryingBlobReader, error) {
+	current, err := open()
+	if err != nil {
+		return nil, err
+	}
+	return &retryingBlobReader{
+		open:          open,
+		current:       current,
+		maxRetries:    maxRetries,
+		minRetryDelay: minDelay,
+		maxRetryDelay: maxDelay,
+	}, nil
+}
+
+func (r *retryingBlobReader) Read(p []byte) (int, error) {
+	for attempt := 0; ; attempt++ {
+		n, err := r.current.Read(p)
+		r.read += int64(n)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if !isRetryableBlobErr(err) || attempt >= r.maxRetries {
+			return n, err
+		}
+
+		log.WithError(err).WithField("offset", r.read).WithField("attempt", attempt+1).
+			Debug("retrying git blob read after transient error")
+		r.current.Close()
+		time.Sleep(backoffDelay(attempt, r.minRetryDelay, r.maxRetryDelay))
+
+		next, openErr := r.open()
+		if openErr != nil {
+			return n, openErr
+		}
+		if _, skipErr := io.CopyN(io.Discard, next, r.read); skipErr != nil {
+			next.Close()
+			return n, skipErr
+		}
+		r.current = next
+	}
+}
+
+func (r *retryingBlobReader) Close() error {
+	return r.current.Close()
+}
+
+// backoffDelay returns an exponential backoff delay for the given retry
+// attempt (0-indexed), doubling from minDelay and capped at maxDelay, with
+// up to 20% jitter so concurrent retries don't all wake up at once.
+func backoffDelay(attempt int, minDelay, maxDelay time.Duration) time.Duration {
+	delay := minDelay << attempt
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay + jitter
+}
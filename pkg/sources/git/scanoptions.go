@@ -0,0 +1,88 @@
+package git
+
+import (
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
+)
+
+// ScanOptions configures how ScanCommits/ScanRepo/ScanUnstaged walk a
+// repository's history. Build one with NewScanOptions and the ScanOption
+// functions below rather than constructing it directly, so new fields can
+// default sensibly without breaking existing callers.
+type ScanOptions struct {
+	BaseHash   string
+	HeadHash   string
+	MaxDepth   int64
+	Filter     *common.Filter
+	Submodules bool
+	LogOptions *gogit.LogOptions
+	Since      time.Time
+}
+
+// ScanOption sets a field on a ScanOptions being built by NewScanOptions.
+type ScanOption func(*ScanOptions)
+
+// NewScanOptions builds a ScanOptions from the given options.
+func NewScanOptions(opts ...ScanOption) *ScanOptions {
+	scanOptions := &ScanOptions{}
+	for _, opt := range opts {
+		opt(scanOptions)
+	}
+	return scanOptions
+}
+
+// ScanOptionBaseHash sets the commit hash or ref to stop scanning at.
+func ScanOptionBaseHash(baseHash string) ScanOption {
+	return func(scanOptions *ScanOptions) {
+		scanOptions.BaseHash = baseHash
+	}
+}
+
+// ScanOptionHeadHash sets the commit hash or ref to start scanning from.
+func ScanOptionHeadHash(headHash string) ScanOption {
+	return func(scanOptions *ScanOptions) {
+		scanOptions.HeadHash = headHash
+	}
+}
+
+// ScanOptionMaxDepth sets the maximum number of commits to scan.
+func ScanOptionMaxDepth(maxDepth int64) ScanOption {
+	return func(scanOptions *ScanOptions) {
+		scanOptions.MaxDepth = maxDepth
+	}
+}
+
+// ScanOptionFilter sets the path filter used to decide which files in each
+// commit's diff are scanned.
+func ScanOptionFilter(filter *common.Filter) ScanOption {
+	return func(scanOptions *ScanOptions) {
+		scanOptions.Filter = filter
+	}
+}
+
+// ScanOptionSubmodules sets whether submodules are scanned recursively.
+func ScanOptionSubmodules(submodules bool) ScanOption {
+	return func(scanOptions *ScanOptions) {
+		scanOptions.Submodules = submodules
+	}
+}
+
+// ScanOptionLogOptions sets the go-git log options callers have already
+// built for the scan.
+func ScanOptionLogOptions(logOptions *gogit.LogOptions) ScanOption {
+	return func(scanOptions *ScanOptions) {
+		scanOptions.LogOptions = logOptions
+	}
+}
+
+// ScanOptionSince excludes commits older than since from the scan, letting
+// large, long-lived repositories be scanned incrementally instead of
+// replaying their entire history on every run.
+func ScanOptionSince(since time.Time) ScanOption {
+	return func(scanOptions *ScanOptions) {
+		scanOptions.Since = since
+	}
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// maxSubmoduleDepth bounds recursive submodule scanning so a cycle between
+// repositories (A vendors B, B vendors A at some earlier commit) or a
+// pathologically deep submodule chain can't recurse forever.
+const maxSubmoduleDepth = 10
+
+// scanSubmodules walks repo's .gitmodules (read via repo's worktree at
+// repoPath), clones/updates each submodule at the commit the parent has
+// pinned using the parent's own auth transport, and recursively scans it
+// with ScanRepo. seen guards against cycles - a submodule URL already
+// scanned anywhere in this call tree is skipped rather than re-cloned - and
+// depth is checked against maxSubmoduleDepth before recursing further.
+// s.concurrency (the same semaphore ScanRepo's callers already share) is
+// acquired around each submodule's update+scan, so fanning out into many
+// submodules can't exceed the configured concurrency.
+func (s *Git) scanSubmodules(ctx context.Context, repo *git.Repository, repoPath string, scanOptions *ScanOptions, chunksChan chan *sources.Chunk, seen map[string]bool, depth int) error {
+	if depth >= maxSubmoduleDepth {
+		log.WithField("repo", repoPath).Warn("max submodule depth reached, not recursing further")
+		return nil
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		// A bare mirror (e.g. from MirrorCache) has no worktree to read
+		// .gitmodules from, so it has no submodules to scan either.
+		return nil
+	}
+
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return fmt.Errorf("reading submodules at %s: %w", repoPath, err)
+	}
+
+	for _, sub := range submodules {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		cfg := sub.Config()
+		if seen[cfg.URL] {
+			log.WithField("submodule", cfg.URL).Debug("skipping already-scanned submodule (cycle)")
+			continue
+		}
+		seen[cfg.URL] = true
+
+		if err := s.scanOneSubmodule(ctx, sub, repoPath, scanOptions, chunksChan, seen, depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Git) scanOneSubmodule(ctx context.Context, sub *git.Submodule, parentPath string, scanOptions *ScanOptions, chunksChan chan *sources.Chunk, seen map[string]bool, depth int) error {
+	if err := s.concurrency.Acquire(ctx, 1); err != nil {
+		return err
+	}
+	defer s.concurrency.Release(1)
+
+	cfg := sub.Config()
+	subPath := filepath.Join(parentPath, cfg.Path)
+
+	if err := sub.Update(&git.SubmoduleUpdateOptions{Init: true}); err != nil {
+		log.WithError(err).WithField("submodule", cfg.URL).Warn("unable to update submodule, skipping")
+		return nil
+	}
+
+	subRepo, err := sub.Repository()
+	if err != nil {
+		log.WithError(err).WithField("submodule", cfg.URL).Warn("unable to open submodule repository, skipping")
+		return nil
+	}
+
+	log.WithField("submodule", cfg.URL).WithField("path", cfg.Path).Debug("scanning submodule")
+	// Scan the submodule's own commits/unstaged changes directly, rather
+	// than through ScanRepo - ScanRepo's own scanOptions.Submodules branch
+	// would otherwise start a fresh seen map and depth counter for this
+	// submodule's children, defeating both the cycle guard and the depth
+	// limit carried through this call tree.
+	if err := s.ScanCommits(ctx, subRepo, subPath, scanOptions, chunksChan); err != nil {
+		return fmt.Errorf("scanning submodule %s (%s): %w", cfg.Path, cfg.URL, err)
+	}
+	if err := s.ScanUnstaged(ctx, subRepo, subPath, scanOptions, chunksChan); err != nil {
+		log.WithError(err).WithField("submodule", cfg.URL).Error("error scanning submodule's unstaged changes")
+	}
+
+	return s.scanSubmodules(ctx, subRepo, subPath, scanOptions, chunksChan, seen, depth+1)
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// cloneRepo clones repoURI using whichever credential type s.conn carries.
+// It's the same per-credential switch Chunks' three repository loops
+// already duplicated; factoring it out here lets scanRepoOnce and Watch
+// share one copy of it.
+func (s *Source) cloneRepo(repoURI string) (string, *git.Repository, error) {
+	switch cred := s.conn.GetCredential().(type) {
+	case *sourcespb.Git_BasicAuth:
+		return CloneRepoUsingToken(cred.BasicAuth.Password, repoURI, cred.BasicAuth.Username)
+	case *sourcespb.Git_Unauthenticated:
+		return CloneRepoUsingUnauthenticated(repoURI)
+	case *sourcespb.Git_SshAuth:
+		return CloneRepoUsingSSH(repoURI)
+	default:
+		return "", nil, fmt.Errorf("invalid connection type for git source")
+	}
+}
+
+// scanRepoOnce clones repoURI, scans it once with scanOptions, and removes
+// the clone afterward. This is the "clone -> ScanRepo -> RemoveAll" block
+// every branch of Chunks' per-repository loop already repeats; pulling it
+// into one helper is also what Watch needs to scan a repository without
+// the removal step it doesn't want.
+func (s *Source) scanRepoOnce(ctx context.Context, repoURI string, scanOptions *ScanOptions, chunksChan chan *sources.Chunk) error {
+	path, repo, err := s.cloneRepo(repoURI)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(path)
+
+	return s.git.ScanRepo(ctx, repo, path, scanOptions, chunksChan)
+}
+
+// Watch turns this source into an always-on daemon: instead of cloning,
+// scanning once, and tearing the clone down (what Chunks does), it keeps a
+// persistent mirror of every configured repository open via cache, and
+// rescans each one on a timer, only chunking commits that arrived since the
+// previous cycle. It runs until ctx is canceled.
+func (s *Source) Watch(ctx context.Context, cache *MirrorCache, interval time.Duration, chunksChan chan *sources.Chunk) error {
+	if interval <= 0 {
+		return fmt.Errorf("watch interval must be positive, got %s", interval)
+	}
+
+	s.watchOnce(ctx, cache, chunksChan)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.watchOnce(ctx, cache, chunksChan)
+		}
+	}
+}
+
+// watchOnce syncs every configured repository's mirror with its remote and
+// scans whatever's new since the mirror's last recorded HEAD, recording the
+// new HEAD afterward so the next cycle only looks at what's new again. A
+// single repository's failure is logged and doesn't stop the others.
+func (s *Source) watchOnce(ctx context.Context, cache *MirrorCache, chunksChan chan *sources.Chunk) {
+	for _, repoURI := range s.conn.Repositories {
+		if len(repoURI) == 0 {
+			continue
+		}
+
+		if err := s.watchRepoOnce(ctx, cache, repoURI, chunksChan); err != nil {
+			log.WithError(err).WithField("repo", repoURI).Error("error watching repository")
+		}
+	}
+}
+
+func (s *Source) watchRepoOnce(ctx context.Context, cache *MirrorCache, repoURI string, chunksChan chan *sources.Chunk) error {
+	hoster, owner, repoName, err := splitRepoURI(repoURI)
+	if err != nil {
+		return fmt.Errorf("parsing repository URI: %w", err)
+	}
+
+	mirrorPath, err := cache.Sync(hoster, owner, repoName, repoURI)
+	if err != nil {
+		return fmt.Errorf("syncing mirror: %w", err)
+	}
+
+	repo, err := RepoFromPath(mirrorPath)
+	if err != nil {
+		return fmt.Errorf("opening mirror: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("resolving mirror HEAD: %w", err)
+	}
+
+	baseHash, _ := cache.LastScanned(mirrorPath, "HEAD")
+
+	if err := s.git.ScanRepo(ctx, repo, mirrorPath, NewScanOptions(ScanOptionBaseHash(baseHash)), chunksChan); err != nil {
+		return fmt.Errorf("scanning mirror: %w", err)
+	}
+
+	return cache.SetLastScanned(mirrorPath, "HEAD", head.Hash().String())
+}
+
+// splitRepoURI breaks a repository URL into the hoster/owner/repo triple
+// MirrorCache's structured layout is keyed on, e.g.
+// https://github.com/trufflesecurity/trufflehog -> ("github.com",
+// "trufflesecurity", "trufflehog").
+func splitRepoURI(repoURI string) (hoster, owner, repo string, err error) {
+	u, err := url.Parse(repoURI)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", "", fmt.Errorf("can't determine owner/repo from %q", repoURI)
+	}
+
+	owner = parts[len(parts)-2]
+	repo = strings.TrimSuffix(parts[len(parts)-1], ".git")
+	return u.Host, owner, repo, nil
+}
@@ -0,0 +1,146 @@
+package gitfilter
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// attrRule is one compiled line of a .gitattributes file: a pattern paired
+// with the attribute names it sets (e.g. "linguist-generated") or unsets
+// (a leading "-", e.g. "-linguist-generated").
+type attrRule struct {
+	pattern *Pattern
+	set     map[string]bool // attribute name -> true (set) or false (unset)
+}
+
+// AttributeStack resolves whether a path carries a given .gitattributes
+// attribute, following the same ancestor-aware, root-to-leaf precedence
+// order as Stack - a deeper .gitattributes file's rule for a path wins over
+// a shallower one's. It exists alongside Stack, rather than folded into it,
+// because .gitattributes and .gitignore have different file names and
+// per-line syntax despite sharing the same pattern dialect for the part
+// before the attribute list.
+type AttributeStack struct {
+	repoRoot string
+	rules    map[string][]attrRule // directory -> that directory's own rules
+	chain    map[string][]attrRule // directory -> its rules plus every ancestor's, in precedence order
+}
+
+// NewAttributeStack builds an AttributeStack rooted at repoRoot.
+func NewAttributeStack(repoRoot string) *AttributeStack {
+	return &AttributeStack{
+		repoRoot: repoRoot,
+		rules:    make(map[string][]attrRule),
+		chain:    make(map[string][]attrRule),
+	}
+}
+
+// HasAttribute reports whether relPath (slash-separated, relative to the
+// repo root) has attr set, per the nearest rule that mentions it.
+func (s *AttributeStack) HasAttribute(relPath, attr string) bool {
+	dir := filepath.ToSlash(filepath.Dir(relPath))
+	if dir == "." {
+		dir = ""
+	}
+	var segs []string
+	if relPath != "" {
+		segs = strings.Split(relPath, "/")
+	}
+
+	result := false
+	for _, rule := range s.chainFor(dir) {
+		if rule.pattern.Match(segs, false) == Exclude {
+			if v, ok := rule.set[attr]; ok {
+				result = v
+			}
+		}
+	}
+	return result
+}
+
+func (s *AttributeStack) chainFor(dir string) []attrRule {
+	if chain, ok := s.chain[dir]; ok {
+		return chain
+	}
+
+	var chain []attrRule
+	if dir != "" {
+		parent := filepath.ToSlash(filepath.Dir(dir))
+		if parent == "." {
+			parent = ""
+		}
+		chain = append(chain, s.chainFor(parent)...)
+	}
+
+	var domain []string
+	if dir != "" {
+		domain = strings.Split(dir, "/")
+	}
+	chain = append(chain, s.rulesFor(dir, domain)...)
+
+	s.chain[dir] = chain
+	return chain
+}
+
+func (s *AttributeStack) rulesFor(dir string, domain []string) []attrRule {
+	if rules, ok := s.rules[dir]; ok {
+		return rules
+	}
+
+	path := filepath.Join(s.repoRoot, filepath.FromSlash(dir), ".gitattributes")
+	f, err := os.Open(path)
+	if err != nil {
+		s.rules[dir] = nil
+		return nil
+	}
+	defer f.Close()
+
+	var rules []attrRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseAttrLine(scanner.Text(), domain); ok {
+			rules = append(rules, rule)
+		}
+	}
+	s.rules[dir] = rules
+	return rules
+}
+
+// parseAttrLine compiles one .gitattributes line - "<pattern> <attr>
+// [<attr>...]", where an attribute prefixed with "-" is explicitly unset -
+// into an attrRule scoped to domain. It returns ok=false for a blank line,
+// a comment, or a pattern with no attributes.
+func parseAttrLine(line string, domain []string) (attrRule, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return attrRule{}, false
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return attrRule{}, false
+	}
+
+	p := ParsePattern(fields[0], domain)
+	if p == nil {
+		return attrRule{}, false
+	}
+	// Attribute patterns, unlike .gitignore ones, always match regardless
+	// of any leading "!" or trailing "/" semantics - ParsePattern's negate
+	// flag doesn't apply here, so clear it rather than have Match return
+	// Include for a path's attribute rule.
+	p.negate = false
+
+	set := make(map[string]bool, len(fields)-1)
+	for _, attr := range fields[1:] {
+		if strings.HasPrefix(attr, "-") {
+			set[attr[1:]] = false
+		} else {
+			set[attr] = true
+		}
+	}
+
+	return attrRule{pattern: p, set: set}, true
+}
@@ -0,0 +1,191 @@
+// Package gitfilter compiles .gitignore-style exclude patterns and
+// .gitattributes generated/ignore markers into matchers that git sources
+// can consult before a path's content is ever chunked, so scanning a large
+// monorepo can skip its vendor/generated directories the same way `git`
+// itself would, instead of requiring hand-maintained exclude regexes.
+package gitfilter
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchResult is the outcome of testing a path against a Pattern or
+// Matcher.
+type MatchResult int
+
+const (
+	// NoMatch means nothing decided the path's fate; the caller should
+	// fall back to whatever default applies (usually: don't exclude it).
+	NoMatch MatchResult = iota
+	// Exclude means a pattern matched and the path should be skipped.
+	Exclude
+	// Include means a negated ("!") pattern matched, overriding an
+	// earlier Exclude from a less specific pattern.
+	Include
+)
+
+// Pattern is one compiled line from a .gitignore-style file, following the
+// rules documented in gitignore(5): a leading "!" negates (re-includes) a
+// path an earlier pattern excluded, a pattern containing a "/" anywhere
+// but its last character is anchored to its domain instead of matching at
+// any depth beneath it, a trailing "/" only matches directories, and "**"
+// matches across any number of path segments.
+type Pattern struct {
+	domain   []string // path, relative to the repo root, of the directory this pattern was declared in
+	segments []string // the pattern's path segments; "**" is kept literally
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ParsePattern compiles line - one line from a .gitignore-style file - into
+// a Pattern scoped to domain, the path (relative to the repo root, split
+// on "/") of the directory the file was read from. It returns nil for a
+// blank line or a full-line comment, neither of which is a pattern.
+func ParsePattern(line string, domain []string) *Pattern {
+	line = strings.TrimRight(line, " \t")
+	if line == "" || line[0] == '#' {
+		return nil
+	}
+
+	negate := false
+	if strings.HasPrefix(line, "!") {
+		negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if line == "" {
+		return nil
+	}
+
+	dirOnly := false
+	if strings.HasSuffix(line, "/") {
+		dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if line == "" {
+		return nil
+	}
+
+	// A slash anywhere but trailing (already stripped above) anchors the
+	// pattern to domain; a pattern with no interior slash may match
+	// starting at any depth beneath domain.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	return &Pattern{
+		domain:   domain,
+		segments: strings.Split(line, "/"),
+		negate:   negate,
+		dirOnly:  dirOnly,
+		anchored: anchored,
+	}
+}
+
+// Match reports whether p applies to path (the full path from the repo
+// root, as returned by strings.Split(relPath, "/")) and isDir (whether
+// that path names a directory). It returns NoMatch if path isn't under
+// p's domain, or no suffix of it (for an unanchored pattern) matches p's
+// segments.
+func (p *Pattern) Match(path []string, isDir bool) MatchResult {
+	if len(path) < len(p.domain) {
+		return NoMatch
+	}
+	for i, d := range p.domain {
+		if path[i] != d {
+			return NoMatch
+		}
+	}
+	rel := path[len(p.domain):]
+
+	if !p.matchesRel(rel) {
+		return NoMatch
+	}
+	if p.dirOnly && !isDir {
+		return NoMatch
+	}
+	if p.negate {
+		return Include
+	}
+	return Exclude
+}
+
+func (p *Pattern) matchesRel(rel []string) bool {
+	if p.anchored {
+		return matchSegments(p.segments, rel)
+	}
+	// Unanchored: the pattern may match starting at any segment of rel,
+	// e.g. "*.log" excludes build/out.log as readily as out.log.
+	for start := 0; start <= len(rel); start++ {
+		if matchSegments(p.segments, rel[start:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments reports whether pattern matches rel exactly, component by
+// component via path.Match, treating a literal "**" component as matching
+// any number (including zero) of rel's remaining components.
+func matchSegments(pattern, rel []string) bool {
+	if len(pattern) == 0 {
+		return len(rel) == 0
+	}
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return true
+		}
+		for i := 0; i <= len(rel); i++ {
+			if matchSegments(pattern[1:], rel[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(rel) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], rel[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], rel[1:])
+}
+
+// ParsePatterns compiles every line of content into Patterns scoped to
+// domain, skipping blank lines and comments.
+func ParsePatterns(content string, domain []string) []*Pattern {
+	var patterns []*Pattern
+	for _, line := range strings.Split(content, "\n") {
+		if p := ParsePattern(line, domain); p != nil {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// Matcher evaluates a path against an ordered list of Patterns, the last
+// one to match winning - mirroring git's own precedence, where a
+// later-declared pattern (or one from a deeper, more specific .gitignore
+// file appended after its ancestors') overrides an earlier one.
+type Matcher struct {
+	patterns []*Pattern
+}
+
+// NewMatcher builds a Matcher over patterns, in the order they should be
+// evaluated (least to most specific).
+func NewMatcher(patterns []*Pattern) *Matcher {
+	return &Matcher{patterns: patterns}
+}
+
+// Match reports the accumulated verdict of m's patterns for path/isDir.
+func (m *Matcher) Match(path []string, isDir bool) MatchResult {
+	result := NoMatch
+	for _, p := range m.patterns {
+		if r := p.Match(path, isDir); r != NoMatch {
+			result = r
+		}
+	}
+	return result
+}
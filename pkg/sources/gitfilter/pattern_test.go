@@ -0,0 +1,119 @@
+package gitfilter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPatternMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		domain  string
+		path    string
+		isDir   bool
+		want    MatchResult
+	}{
+		{
+			name:    "simple file match",
+			pattern: "secrets.txt",
+			path:    "secrets.txt",
+			want:    Exclude,
+		},
+		{
+			name:    "unanchored matches at any depth",
+			pattern: "*.log",
+			path:    "build/out/run.log",
+			want:    Exclude,
+		},
+		{
+			name:    "anchored only matches at domain",
+			pattern: "/vendor",
+			path:    "pkg/vendor",
+			want:    NoMatch,
+		},
+		{
+			name:    "anchored matches at domain root",
+			pattern: "/vendor",
+			path:    "vendor",
+			isDir:   true,
+			want:    Exclude,
+		},
+		{
+			name:    "dir-only pattern doesn't match a file",
+			pattern: "build/",
+			path:    "build",
+			isDir:   false,
+			want:    NoMatch,
+		},
+		{
+			name:    "dir-only pattern matches a directory",
+			pattern: "build/",
+			path:    "build",
+			isDir:   true,
+			want:    Exclude,
+		},
+		{
+			name:    "double star matches any depth",
+			pattern: "**/testdata/**",
+			path:    "pkg/foo/testdata/fixtures/a.json",
+			want:    Exclude,
+		},
+		{
+			name:    "negation re-includes",
+			pattern: "!important.log",
+			path:    "important.log",
+			want:    Include,
+		},
+		{
+			name:    "domain scopes an anchored pattern",
+			pattern: "/vendor",
+			domain:  "pkg",
+			path:    "pkg/vendor",
+			isDir:   true,
+			want:    Exclude,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var domain []string
+			if tt.domain != "" {
+				domain = strings.Split(tt.domain, "/")
+			}
+			p := ParsePattern(tt.pattern, domain)
+			if p == nil {
+				t.Fatalf("ParsePattern(%q) = nil", tt.pattern)
+			}
+			got := p.Match(strings.Split(tt.path, "/"), tt.isDir)
+			if got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherLastMatchWins(t *testing.T) {
+	patterns := ParsePatterns("*.log\n!important.log\n", nil)
+	m := NewMatcher(patterns)
+
+	if got := m.Match([]string{"debug.log"}, false); got != Exclude {
+		t.Errorf("debug.log: got %v, want Exclude", got)
+	}
+	if got := m.Match([]string{"important.log"}, false); got != Include {
+		t.Errorf("important.log: got %v, want Include", got)
+	}
+	if got := m.Match([]string{"main.go"}, false); got != NoMatch {
+		t.Errorf("main.go: got %v, want NoMatch", got)
+	}
+}
+
+func TestParsePatternSkipsCommentsAndBlankLines(t *testing.T) {
+	for _, line := range []string{"", "   ", "# a comment"} {
+		if p := ParsePattern(line, nil); p != nil {
+			t.Errorf("ParsePattern(%q) = %v, want nil", line, p)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package gitfilter
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Stack resolves a path's final ignore verdict the way `git` itself does:
+// by consulting, in ascending precedence, the global excludes file,
+// $GIT_DIR/info/exclude, the repo root .gitignore, and every .gitignore
+// between the repo root and the path's own directory - caching the
+// compiled Matcher for each directory it visits, since the same directory
+// is typically asked about many times as a commit's file list is walked.
+type Stack struct {
+	repoRoot string
+	gitDir   string
+
+	base    []*Pattern // global excludes + info/exclude, domain-less (repo root)
+	matcher map[string]*Matcher
+}
+
+// NewStack builds a Stack rooted at repoRoot (the working tree root, as
+// passed to RepoPath/RepoPathNative). gitDir is the repository's .git
+// directory, used to find info/exclude; it's usually filepath.Join(repoRoot,
+// ".git").
+func NewStack(repoRoot, gitDir string) *Stack {
+	s := &Stack{
+		repoRoot: repoRoot,
+		gitDir:   gitDir,
+		matcher:  make(map[string]*Matcher),
+	}
+	s.base = append(s.base, loadPatternFile(globalExcludesFile(), nil)...)
+	s.base = append(s.base, loadPatternFile(filepath.Join(gitDir, "info", "exclude"), nil)...)
+	return s
+}
+
+// Match reports the ignore verdict for relPath (slash-separated, relative
+// to the repo root) given isDir, consulting every ancestor .gitignore of
+// relPath's directory in root-to-leaf order so a more specific file's
+// patterns take precedence, matching git's own resolution order.
+func (s *Stack) Match(relPath string, isDir bool) MatchResult {
+	dir := filepath.ToSlash(filepath.Dir(relPath))
+	if dir == "." {
+		dir = ""
+	}
+	m := s.matcherFor(dir)
+	var segs []string
+	if relPath != "" {
+		segs = strings.Split(relPath, "/")
+	}
+	return m.Match(segs, isDir)
+}
+
+// matcherFor returns the (cached) Matcher covering dir and every ancestor
+// up to the repo root, building and caching it on first use.
+func (s *Stack) matcherFor(dir string) *Matcher {
+	if m, ok := s.matcher[dir]; ok {
+		return m
+	}
+
+	var patterns []*Pattern
+	if dir == "" {
+		patterns = append(patterns, s.base...)
+	} else {
+		parent := filepath.ToSlash(filepath.Dir(dir))
+		if parent == "." {
+			parent = ""
+		}
+		patterns = append(patterns, s.matcherFor(parent).patterns...)
+	}
+
+	var domain []string
+	if dir != "" {
+		domain = strings.Split(dir, "/")
+	}
+	patterns = append(patterns, loadPatternFile(filepath.Join(s.repoRoot, filepath.FromSlash(dir), ".gitignore"), domain)...)
+
+	m := NewMatcher(patterns)
+	s.matcher[dir] = m
+	return m
+}
+
+// loadPatternFile reads and compiles path as a .gitignore-style file,
+// returning nil (not an error) if it doesn't exist - the overwhelmingly
+// common case for any given directory.
+func loadPatternFile(path string, domain []string) []*Pattern {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return ParsePatterns(strings.Join(lines, "\n"), domain)
+}
+
+// globalExcludesFile resolves git's core.excludesFile: the path set in
+// $HOME/.gitconfig, or - absent that - $XDG_CONFIG_HOME/git/ignore (falling
+// back to ~/.config/git/ignore), exactly as `git` itself falls back.
+func globalExcludesFile() string {
+	if cfg := excludesFileFromConfig(filepath.Join(homeDir(), ".gitconfig")); cfg != "" {
+		return expandHome(cfg)
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git", "ignore")
+	}
+	return filepath.Join(homeDir(), ".config", "git", "ignore")
+}
+
+// excludesFileFromConfig scans a gitconfig-format file for core.excludesFile
+// under the [core] section, returning "" if it isn't set.
+func excludesFileFromConfig(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inCore := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "["):
+			inCore = strings.EqualFold(line, "[core]")
+		case inCore && strings.HasPrefix(line, "excludesfile"):
+			if idx := strings.Index(line, "="); idx != -1 {
+				return strings.TrimSpace(line[idx+1:])
+			}
+		}
+	}
+	return ""
+}
+
+func homeDir() string {
+	if h, err := os.UserHomeDir(); err == nil {
+		return h
+	}
+	return ""
+}
+
+// expandHome expands a leading "~/" in path to the current user's home
+// directory, the one shell expansion core.excludesFile values commonly rely
+// on that raw os.Open wouldn't otherwise honor.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	return filepath.Join(homeDir(), path[2:])
+}
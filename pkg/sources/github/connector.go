@@ -18,6 +18,11 @@ type Connector interface {
 	APIClient() *github.Client
 	// Clone clones a repository using the configured authentication information.
 	Clone(ctx context.Context, repoURL string) (string, *gogit.Repository, error)
+	// InstallationID returns the GitHub App installation this connector
+	// authenticates as, or 0 for a connector not scoped to one, so callers
+	// can attribute API calls (metrics, logging) to an installation in a
+	// multi-org enterprise scan.
+	InstallationID() int64
 }
 
 func NewConnector(
@@ -30,6 +35,10 @@ func NewConnector(
 	case *sourcespb.GitHub_GithubApp:
 		log.RedactGlobally(cred.GithubApp.GetPrivateKey())
 		return newAppConnector(apiEndpoint, cred.GithubApp)
+	case *sourcespb.GitHub_GithubAppInstallationToken:
+		log.RedactGlobally(cred.GithubAppInstallationToken.GetPrivateKey())
+		log.RedactGlobally(cred.GithubAppInstallationToken.GetToken())
+		return newInstallationTokenConnector(apiEndpoint, cred.GithubAppInstallationToken, handleRateLimit)
 	case *sourcespb.GitHub_BasicAuth:
 		log.RedactGlobally(cred.BasicAuth.GetPassword())
 		return newBasicAuthConnector(apiEndpoint, cred.BasicAuth)
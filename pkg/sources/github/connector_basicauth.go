@@ -45,3 +45,6 @@ func (c *basicAuthConnector) ApiClient() *github.Client {
 func (c *basicAuthConnector) Clone(ctx context.Context, repoURL string) (string, *gogit.Repository, error) {
 	return git.CloneRepoUsingToken(ctx, c.password, repoURL, c.username)
 }
+
+// InstallationID returns 0: basic auth isn't scoped to a GitHub App installation.
+func (c *basicAuthConnector) InstallationID() int64 { return 0 }
@@ -0,0 +1,213 @@
+package github
+
+import (
+	stdcontext "context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-github/v67/github"
+	"golang.org/x/time/rate"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/credentialspb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/git"
+)
+
+// installationTokenRefreshWindow is how far ahead of expiry an installation
+// token is refreshed. GitHub mints installation tokens with a one-hour
+// lifetime, so refreshing a few minutes early comfortably covers the time a
+// long clone or a slow API call spends holding onto the token.
+const installationTokenRefreshWindow = 5 * time.Minute
+
+// installationRateLimiters holds one token bucket per GitHub App
+// installation ID, shared across every installationTokenConnector for that
+// installation in this process. Without this, an enterprise scan that walks
+// many installations one after another would let a single installation's
+// secondary rate limit throttle handleRateLimit callers for every other
+// installation, since they'd otherwise share one undifferentiated limiter.
+var (
+	installationRateLimitersMu sync.Mutex
+	installationRateLimiters   = map[int64]*rate.Limiter{}
+)
+
+func installationRateLimiter(installationID int64) *rate.Limiter {
+	installationRateLimitersMu.Lock()
+	defer installationRateLimitersMu.Unlock()
+
+	limiter, ok := installationRateLimiters[installationID]
+	if !ok {
+		// Unthrottled until handleRateLimit observes a secondary rate limit
+		// response for this installation and tightens it.
+		limiter = rate.NewLimiter(rate.Inf, 1)
+		installationRateLimiters[installationID] = limiter
+	}
+	return limiter
+}
+
+// installationTokenConnector authenticates as a GitHub App installation
+// using a token minted elsewhere and handed to this connector directly,
+// rather than this process minting its first token itself. It refreshes the
+// token on its own via the App's JWT once the provided one is close to
+// expiring, and rate-limits itself against other connectors for the same
+// installation rather than per apiEndpoint.
+type installationTokenConnector struct {
+	apiClient *github.Client
+
+	appID          int64
+	installationID int64
+	privateKey     []byte
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+
+	limiter *rate.Limiter
+
+	handleRateLimit func(ctx context.Context, errIn error, reporters ...errorReporter) bool
+}
+
+var _ Connector = (*installationTokenConnector)(nil)
+
+func newInstallationTokenConnector(
+	apiEndpoint string,
+	cred *credentialspb.GitHubAppInstallationToken,
+	handleRateLimit func(ctx context.Context, errIn error, reporters ...errorReporter) bool,
+) (*installationTokenConnector, error) {
+	c := &installationTokenConnector{
+		appID:           cred.GetAppId(),
+		installationID:  cred.GetInstallationId(),
+		privateKey:      []byte(cred.GetPrivateKey()),
+		token:           cred.GetToken(),
+		expiresAt:       time.Unix(cred.GetExpiresAt(), 0),
+		limiter:         installationRateLimiter(cred.GetInstallationId()),
+		handleRateLimit: handleRateLimit,
+	}
+
+	httpClient := common.RetryableHTTPClientTimeout(60)
+	httpClient.Transport = &installationTokenTransport{connector: c, base: httpClient.Transport}
+
+	apiClient, err := createGitHubClient(httpClient, apiEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not create API client: %w", err)
+	}
+	c.apiClient = apiClient
+
+	return c, nil
+}
+
+func (c *installationTokenConnector) APIClient() *github.Client { return c.apiClient }
+
+// InstallationID returns the GitHub App installation this connector
+// authenticates as, so callers attributing API calls (e.g. per-installation
+// metrics or logging in a multi-org enterprise scan) don't have to thread it
+// through separately.
+func (c *installationTokenConnector) InstallationID() int64 { return c.installationID }
+
+func (c *installationTokenConnector) Clone(ctx context.Context, repoURL string) (string, *gogit.Repository, error) {
+	token, err := c.tokenFor(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	// GitHub App installation tokens authenticate git operations as the
+	// "x-access-token" user, regardless of which account installed the app.
+	return git.CloneRepoUsingToken(ctx, token, repoURL, "x-access-token")
+}
+
+// tokenFor returns the connector's current installation token, minting a
+// fresh one via the App's JWT first if the current one is within
+// installationTokenRefreshWindow of expiring. It takes the plain stdlib
+// context, rather than this package's richer one, so it can be called from
+// installationTokenTransport.RoundTrip, which only ever has an *http.Request's
+// stdlib context available.
+func (c *installationTokenConnector) tokenFor(ctx stdcontext.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Until(c.expiresAt) > installationTokenRefreshWindow {
+		return c.token, nil
+	}
+
+	token, expiresAt, err := mintInstallationToken(ctx, c.appID, c.installationID, c.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("could not refresh installation token for installation %d: %w", c.installationID, err)
+	}
+	c.token = token
+	c.expiresAt = expiresAt
+	return c.token, nil
+}
+
+// mintInstallationToken signs a short-lived App JWT with privateKey and
+// exchanges it for a new installation access token.
+func mintInstallationToken(ctx stdcontext.Context, appID, installationID int64, privateKey []byte) (string, time.Time, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("could not parse app private key: %w", err)
+	}
+
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(now.Add(-30 * time.Second)), // tolerate clock drift
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),   // GitHub caps App JWTs at 10 minutes
+		Issuer:    fmt.Sprintf("%d", appID),
+	}
+	appJWT, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("could not sign app jwt: %w", err)
+	}
+
+	jwtClient, err := createGitHubClient(&http.Client{Transport: &bearerTokenTransport{token: appJWT}}, cloudEndpoint)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	installToken, _, err := jwtClient.Apps.CreateInstallationToken(ctx, installationID, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("could not create installation token: %w", err)
+	}
+
+	return installToken.GetToken(), installToken.GetExpiresAt().Time, nil
+}
+
+// bearerTokenTransport injects a fixed bearer token into every request; used
+// to authenticate the one API call (minting an installation token) that must
+// be made with the App's JWT rather than an installation token.
+type bearerTokenTransport struct{ token string }
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(req2)
+}
+
+// installationTokenTransport injects the connector's current installation
+// token into every request and applies that installation's shared rate
+// limiter before handing off to base.
+type installationTokenTransport struct {
+	connector *installationTokenConnector
+	base      http.RoundTripper
+}
+
+func (t *installationTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.connector.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	token, err := t.connector.tokenFor(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", "Bearer "+token)
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req2)
+}
@@ -2,11 +2,14 @@ package gitlab
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -43,6 +46,22 @@ type Source struct {
 	repos       []string
 	ignoreRepos []string
 
+	groups           []string
+	users            []string
+	includeSubgroups bool
+	includeProjects  []string
+
+	depth        int
+	since        time.Time
+	singleBranch string
+
+	includeMRComments    bool
+	includeIssueComments bool
+	includeSnippets      bool
+
+	scanCICDVariables bool
+	scanPackages      bool
+
 	useCustomContentWriter bool
 	git                    *git.Git
 	scanOptions            *git.ScanOptions
@@ -96,6 +115,20 @@ func (s *Source) Init(_ context.Context, name string, jobId sources.JobID, sourc
 	s.repos = conn.Repositories
 	s.ignoreRepos = conn.IgnoreRepos
 	s.url = conn.Endpoint
+	s.includeMRComments = conn.IncludeMrComments
+	s.includeIssueComments = conn.IncludeIssueComments
+	s.includeSnippets = conn.IncludeSnippets
+	s.scanCICDVariables = conn.ScanCicdVariables
+	s.scanPackages = conn.ScanPackages
+	s.groups = conn.Groups
+	s.users = conn.Users
+	s.includeSubgroups = conn.IncludeSubgroups
+	s.includeProjects = conn.IncludeProjects
+	s.depth = int(conn.Depth)
+	s.singleBranch = conn.SingleBranch
+	if conn.Since != nil {
+		s.since = conn.Since.AsTime()
+	}
 
 	if conn.Endpoint != "" && !strings.HasSuffix(s.url, "/") {
 		s.url = s.url + "/"
@@ -196,7 +229,29 @@ func (s *Source) Chunks(ctx context.Context, chunksChan chan *sources.Chunk, _ .
 	// We must sort the repos so we can resume later if necessary.
 	slices.Sort(s.repos)
 
-	return s.scanRepos(ctx, chunksChan)
+	if err := s.scanRepos(ctx, apiClient, chunksChan); err != nil {
+		return err
+	}
+
+	if s.includeMRComments || s.includeIssueComments || s.includeSnippets {
+		if err := s.scanSocialArtifacts(ctx, apiClient, chunksChan); err != nil {
+			ctx.Logger().Error(err, "error scanning GitLab merge request/issue/snippet artifacts")
+		}
+	}
+
+	if s.scanCICDVariables {
+		if err := s.scanCICDVariablesFor(ctx, apiClient, chunksChan); err != nil {
+			ctx.Logger().Error(err, "error scanning GitLab CI/CD variables")
+		}
+	}
+
+	if s.scanPackages {
+		if err := s.scanPackagesFor(ctx, apiClient, chunksChan); err != nil {
+			ctx.Logger().Error(err, "error scanning GitLab package registry")
+		}
+	}
+
+	return nil
 }
 
 func (s *Source) Validate(ctx context.Context) []error {
@@ -311,9 +366,44 @@ func (s *Source) basicAuthSuccessful(apiClient *gitlab.Client) bool {
 	return false
 }
 
-// getAllProjects enumerates all GitLab projects using the provided API client.
-// The reporter, if provided, is only used for reporting errors that would only
-// otherwise be logged.
+const (
+	projectOrderBy         = "last_activity_at"
+	projectPaginationLimit = 100 // Default is 20, max is 100.
+)
+
+// rateLimited reports whether res represents a GitLab rate-limit response
+// (HTTP 429), and if so, blocks for the duration res's Retry-After header
+// indicates (or a conservative default, if the header is missing or
+// unparseable) before returning. Callers should retry the same page
+// without advancing their pagination cursor when this returns true.
+func rateLimited(ctx context.Context, res *gitlab.Response) bool {
+	if res == nil || res.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	wait := defaultRateLimitBackoff
+	if retryAfter := res.Header.Get("Retry-After"); retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil && secs > 0 {
+			wait = time.Duration(secs) * time.Second
+		}
+	}
+	ctx.Logger().V(2).Info("rate limited by GitLab, waiting before retrying page", "wait", wait)
+	select {
+	case <-ctx.Done():
+	case <-time.After(wait):
+	}
+	return true
+}
+
+// defaultRateLimitBackoff is used when a 429 response carries no (or an
+// unparseable) Retry-After header.
+const defaultRateLimitBackoff = 30 * time.Second
+
+// getAllProjects enumerates the projects s is scoped to scan: when Groups
+// or Users are configured, only those groups'/users' projects (plus, for a
+// group, its subgroups' projects when IncludeSubgroups is set); otherwise
+// every project the authenticated user can see, the behavior this method
+// has always had. Either way, the result is filtered down to
+// s.includeProjects (a glob allowlist) when any were configured.
 func (s *Source) getAllProjects(ctx context.Context, apiClient *gitlab.Client, reporter sources.UnitReporter) ([]*gitlab.Project, error) {
 	reportErr := func(ctx context.Context, err error) error {
 		ctx.Logger().Error(err, "getAllProjects error")
@@ -322,39 +412,72 @@ func (s *Source) getAllProjects(ctx context.Context, apiClient *gitlab.Client, r
 		}
 		return nil
 	}
-	// Projects without repo will get user projects, groups projects, and subgroup projects.
+
+	var (
+		projects []*gitlab.Project
+		err      error
+	)
+	if len(s.groups) > 0 || len(s.users) > 0 {
+		projects, err = s.getScopedProjects(ctx, apiClient, reportErr)
+	} else {
+		projects, err = s.getAllVisibleProjects(ctx, apiClient, reportErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.includeProjects) > 0 {
+		includeProject := buildIgnorer(s.includeProjects, func(err error, pattern string) {
+			ctx.Logger().Error(err, "could not compile include project glob", "glob", pattern)
+		})
+		filtered := projects[:0]
+		for _, p := range projects {
+			if includeProject(p.PathWithNamespace) {
+				filtered = append(filtered, p)
+			}
+		}
+		projects = filtered
+	}
+
+	projectsWithNamespace := make([]string, len(projects))
+	for i, p := range projects {
+		projectsWithNamespace[i] = p.NameWithNamespace
+	}
+	ctx.Logger().Info("Enumerated GitLab projects", "count", len(projects))
+	ctx.Logger().V(2).Info("Enumerated GitLab projects", "projects", projectsWithNamespace)
+
+	return projects, nil
+}
+
+// getAllVisibleProjects is getAllProjects' original, unscoped behavior:
+// the authenticated user's own projects, plus every group (and, since
+// IncludeSubGroups is always set here, subgroup) visible to them.
+func (s *Source) getAllVisibleProjects(ctx context.Context, apiClient *gitlab.Client, reportErr func(context.Context, error) error) ([]*gitlab.Project, error) {
 	user, _, err := apiClient.Users.CurrentUser()
 	if err != nil {
 		return nil, fmt.Errorf("unable to authenticate using %s: %w", s.authMethod, err)
 	}
 
 	uniqueProjects := make(map[int]*gitlab.Project)
-	var (
-		projects              []*gitlab.Project
-		projectsWithNamespace []string
-	)
-
-	// Used to filter out duplicate projects.
+	var projects []*gitlab.Project
 	processProjects := func(projList []*gitlab.Project) {
 		for _, proj := range projList {
 			if _, exists := uniqueProjects[proj.ID]; !exists {
 				uniqueProjects[proj.ID] = proj
 				projects = append(projects, proj)
-				projectsWithNamespace = append(projectsWithNamespace, proj.NameWithNamespace)
 			}
 		}
 	}
 
-	const (
-		orderBy         = "last_activity_at"
-		paginationLimit = 100 // Default is 20, max is 100.
-	)
-	listOpts := gitlab.ListOptions{PerPage: paginationLimit}
+	listOpts := gitlab.ListOptions{PerPage: projectPaginationLimit}
 
-	projectQueryOptions := &gitlab.ListProjectsOptions{OrderBy: gitlab.Ptr(orderBy), ListOptions: listOpts}
+	projectQueryOptions := &gitlab.ListProjectsOptions{OrderBy: gitlab.Ptr(projectOrderBy), ListOptions: listOpts}
 	for {
 		userProjects, res, err := apiClient.Projects.ListUserProjects(user.ID, projectQueryOptions)
 		if err != nil {
+			if rateLimited(ctx, res) {
+				continue
+			}
 			err = fmt.Errorf("received error on listing user projects: %w", err)
 			if err := reportErr(ctx, err); err != nil {
 				return nil, err
@@ -383,6 +506,9 @@ func (s *Source) getAllProjects(ctx context.Context, apiClient *gitlab.Client, r
 	for {
 		groupList, res, err := apiClient.Groups.ListGroups(&listGroupsOptions)
 		if err != nil {
+			if rateLimited(ctx, res) {
+				continue
+			}
 			err = fmt.Errorf("received error on listing groups, you probably don't have permissions to do that: %w", err)
 			if err := reportErr(ctx, err); err != nil {
 				return nil, err
@@ -397,37 +523,155 @@ func (s *Source) getAllProjects(ctx context.Context, apiClient *gitlab.Client, r
 	}
 
 	for _, group := range groups {
-		listGroupProjectOptions := &gitlab.ListGroupProjectsOptions{
-			ListOptions:      listOpts,
-			OrderBy:          gitlab.Ptr(orderBy),
-			IncludeSubGroups: gitlab.Ptr(true),
+		groupProjects, err := s.listGroupProjects(ctx, apiClient, group.ID, group.FullPath, true, reportErr)
+		if err != nil {
+			return nil, err
+		}
+		processProjects(groupProjects)
+	}
+
+	return projects, nil
+}
+
+// getScopedProjects enumerates only the configured s.groups and s.users,
+// rather than every project visible to the authenticated user.
+func (s *Source) getScopedProjects(ctx context.Context, apiClient *gitlab.Client, reportErr func(context.Context, error) error) ([]*gitlab.Project, error) {
+	uniqueProjects := make(map[int]*gitlab.Project)
+	var projects []*gitlab.Project
+	processProjects := func(projList []*gitlab.Project) {
+		for _, proj := range projList {
+			if _, exists := uniqueProjects[proj.ID]; !exists {
+				uniqueProjects[proj.ID] = proj
+				projects = append(projects, proj)
+			}
+		}
+	}
+
+	for _, groupPath := range s.groups {
+		group, _, err := apiClient.Groups.GetGroup(groupPath, nil)
+		if err != nil {
+			err = fmt.Errorf("could not resolve configured GitLab group %q: %w", groupPath, err)
+			if err := reportErr(ctx, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		groupProjects, err := s.listGroupProjects(ctx, apiClient, group.ID, group.FullPath, s.includeSubgroups, reportErr)
+		if err != nil {
+			return nil, err
+		}
+		processProjects(groupProjects)
+
+		if !s.includeSubgroups {
+			continue
+		}
+		descendants, err := s.listDescendantGroups(ctx, apiClient, group.ID, reportErr)
+		if err != nil {
+			return nil, err
+		}
+		for _, descendant := range descendants {
+			descendantProjects, err := s.listGroupProjects(ctx, apiClient, descendant.ID, descendant.FullPath, false, reportErr)
+			if err != nil {
+				return nil, err
+			}
+			processProjects(descendantProjects)
 		}
+	}
+
+	for _, username := range s.users {
+		userList, _, err := apiClient.Users.ListUsers(&gitlab.ListUsersOptions{Username: gitlab.Ptr(username)})
+		if err != nil || len(userList) == 0 {
+			err = fmt.Errorf("could not resolve configured GitLab user %q: %w", username, err)
+			if err := reportErr(ctx, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		listOpts := gitlab.ListOptions{PerPage: projectPaginationLimit}
+		opts := &gitlab.ListProjectsOptions{OrderBy: gitlab.Ptr(projectOrderBy), ListOptions: listOpts}
 		for {
-			grpPrjs, res, err := apiClient.Groups.ListGroupProjects(group.ID, listGroupProjectOptions)
+			userProjects, res, err := apiClient.Projects.ListUserProjects(userList[0].ID, opts)
 			if err != nil {
-				err = fmt.Errorf(
-					"received error on listing group projects for %q, you probably don't have permissions to do that: %w",
-					group.FullPath, err,
-				)
+				if rateLimited(ctx, res) {
+					continue
+				}
+				err = fmt.Errorf("received error on listing projects for user %q: %w", username, err)
 				if err := reportErr(ctx, err); err != nil {
 					return nil, err
 				}
 				break
 			}
-			processProjects(grpPrjs)
-			listGroupProjectOptions.Page = res.NextPage
+			processProjects(userProjects)
+			opts.Page = res.NextPage
 			if res.NextPage == 0 {
 				break
 			}
 		}
 	}
 
-	ctx.Logger().Info("Enumerated GitLab projects", "count", len(projects))
-	ctx.Logger().V(2).Info("Enumerated GitLab projects", "projects", projectsWithNamespace)
+	return projects, nil
+}
 
+// listGroupProjects pages through a single group's projects.
+func (s *Source) listGroupProjects(ctx context.Context, apiClient *gitlab.Client, groupID int, groupPath string, includeSubGroups bool, reportErr func(context.Context, error) error) ([]*gitlab.Project, error) {
+	var projects []*gitlab.Project
+	opts := &gitlab.ListGroupProjectsOptions{
+		ListOptions:      gitlab.ListOptions{PerPage: projectPaginationLimit},
+		OrderBy:          gitlab.Ptr(projectOrderBy),
+		IncludeSubGroups: gitlab.Ptr(includeSubGroups),
+	}
+	for {
+		grpPrjs, res, err := apiClient.Groups.ListGroupProjects(groupID, opts)
+		if err != nil {
+			if rateLimited(ctx, res) {
+				continue
+			}
+			err = fmt.Errorf(
+				"received error on listing group projects for %q, you probably don't have permissions to do that: %w",
+				groupPath, err,
+			)
+			if err := reportErr(ctx, err); err != nil {
+				return nil, err
+			}
+			break
+		}
+		projects = append(projects, grpPrjs...)
+		opts.Page = res.NextPage
+		if res.NextPage == 0 {
+			break
+		}
+	}
 	return projects, nil
 }
 
+// listDescendantGroups pages through every subgroup beneath groupID, at
+// any depth.
+func (s *Source) listDescendantGroups(ctx context.Context, apiClient *gitlab.Client, groupID int, reportErr func(context.Context, error) error) ([]*gitlab.Group, error) {
+	var descendants []*gitlab.Group
+	opts := &gitlab.ListDescendantGroupsOptions{ListOptions: gitlab.ListOptions{PerPage: projectPaginationLimit}}
+	for {
+		groups, res, err := apiClient.Groups.ListDescendantGroups(groupID, opts)
+		if err != nil {
+			if rateLimited(ctx, res) {
+				continue
+			}
+			err = fmt.Errorf("received error on listing descendant groups: %w", err)
+			if err := reportErr(ctx, err); err != nil {
+				return nil, err
+			}
+			break
+		}
+		descendants = append(descendants, groups...)
+		opts.Page = res.NextPage
+		if res.NextPage == 0 {
+			break
+		}
+	}
+	return descendants, nil
+}
+
 // getReposFromGitlab enumerates all repositories of all projects in GitLab
 // using the provided API client. Ignored repositories will be skipped. The
 // reporter, if provided, is used for reporting errors that would only
@@ -476,7 +720,7 @@ func (s *Source) getReposFromGitlab(
 	return repos, nil
 }
 
-func (s *Source) scanRepos(ctx context.Context, chunksChan chan *sources.Chunk) error {
+func (s *Source) scanRepos(ctx context.Context, apiClient *gitlab.Client, chunksChan chan *sources.Chunk) error {
 	// If there is resume information available, limit this scan to only the repos that still need scanning.
 	reposToScan, progressIndexOffset := sources.FilterReposToResume(s.repos, s.GetProgress().EncodedResumeInfo)
 	s.repos = reposToScan
@@ -498,6 +742,16 @@ func (s *Source) scanRepos(ctx context.Context, chunksChan chan *sources.Chunk)
 				return nil
 			}
 
+			if !s.since.IsZero() {
+				stale, err := s.projectPredatesSince(apiClient, repoURL)
+				if err != nil {
+					logger.V(2).Info("could not check project activity against --since, scanning anyway", "error", err)
+				} else if stale {
+					logger.V(2).Info("skipping repo: last activity predates --since")
+					return nil
+				}
+			}
+
 			s.setProgressCompleteWithRepo(i, progressIndexOffset, repoURL)
 			// Ensure the repo is removed from the resume info after being scanned.
 			defer func(s *Source) {
@@ -506,11 +760,13 @@ func (s *Source) scanRepos(ctx context.Context, chunksChan chan *sources.Chunk)
 				s.resumeInfoSlice = sources.RemoveRepoFromResumeInfo(s.resumeInfoSlice, repoURL)
 			}(s)
 
+			cloneArgs := git.ShallowCloneArgs(s.depth, s.singleBranch)
+
 			var path string
 			var repo *gogit.Repository
 			var err error
 			if s.authMethod == "UNAUTHENTICATED" {
-				path, repo, err = git.CloneRepoUsingUnauthenticated(ctx, repoURL)
+				path, repo, err = git.CloneRepoUsingUnauthenticated(ctx, repoURL, cloneArgs...)
 			} else {
 				// If a username is not provided we need to use a default one in order to clone a private repo.
 				// Not setting "placeholder" as s.user on purpose in case any downstream services rely on a "" value for s.user.
@@ -518,7 +774,7 @@ func (s *Source) scanRepos(ctx context.Context, chunksChan chan *sources.Chunk)
 				if user == "" {
 					user = "placeholder"
 				}
-				path, repo, err = git.CloneRepoUsingToken(ctx, s.token, repoURL, user)
+				path, repo, err = git.CloneRepoUsingToken(ctx, s.token, repoURL, user, cloneArgs...)
 			}
 			if err != nil {
 				scanErrs.Add(err)
@@ -563,6 +819,23 @@ func (s *Source) setProgressCompleteWithRepo(index int, offset int, repoURL stri
 	s.SetProgressComplete(index+offset, len(s.repos)+offset, fmt.Sprintf("Repo: %s", repoURL), encodedResumeInfo)
 }
 
+// projectPredatesSince reports whether repoURL's GitLab project has had no
+// activity since s.since, so scanRepos can skip cloning it entirely.
+func (s *Source) projectPredatesSince(apiClient *gitlab.Client, repoURL string) (bool, error) {
+	path, err := projectPathFromRepoURL(repoURL)
+	if err != nil {
+		return false, err
+	}
+	project, _, err := apiClient.Projects.GetProject(path, nil)
+	if err != nil {
+		return false, err
+	}
+	if project.LastActivityAt == nil {
+		return false, nil
+	}
+	return project.LastActivityAt.Before(s.since), nil
+}
+
 func (s *Source) WithScanOptions(scanOptions *git.ScanOptions) {
 	s.scanOptions = scanOptions
 }
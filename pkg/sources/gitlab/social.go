@@ -0,0 +1,234 @@
+package gitlab
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/source_metadatapb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sanitizer"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// scanSocialArtifacts scans non-git GitLab artifacts that commonly leak
+// secrets - merge request descriptions/comments, issue descriptions/
+// comments, and project snippets - the GitLab-side parity gap against the
+// GitHub source's IncludeIssueComments/IncludePullRequestComments/
+// IncludeGistComments. Each enabled kind of artifact is scanned across
+// every project s is configured to scan.
+func (s *Source) scanSocialArtifacts(ctx context.Context, apiClient *gitlab.Client, chunksChan chan *sources.Chunk) error {
+	projects, err := s.projectsForSocialScan(ctx, apiClient)
+	if err != nil {
+		return fmt.Errorf("resolving projects for merge request/issue/snippet scan: %w", err)
+	}
+
+	for _, project := range projects {
+		if common.IsDone(ctx) {
+			return nil
+		}
+
+		if s.includeMRComments {
+			if err := s.scanMergeRequests(ctx, apiClient, project, chunksChan); err != nil {
+				ctx.Logger().Error(err, "error scanning merge requests", "project", project.PathWithNamespace)
+			}
+		}
+		if s.includeIssueComments {
+			if err := s.scanIssues(ctx, apiClient, project, chunksChan); err != nil {
+				ctx.Logger().Error(err, "error scanning issues", "project", project.PathWithNamespace)
+			}
+		}
+		if s.includeSnippets {
+			if err := s.scanSnippets(ctx, apiClient, project, chunksChan); err != nil {
+				ctx.Logger().Error(err, "error scanning snippets", "project", project.PathWithNamespace)
+			}
+		}
+	}
+
+	return nil
+}
+
+// projectsForSocialScan resolves the gitlab.Project objects to scan
+// merge requests/issues/snippets from. When repos were explicitly
+// configured, each one is resolved back to its GitLab project by path;
+// otherwise every project s would normally clone is reused.
+func (s *Source) projectsForSocialScan(ctx context.Context, apiClient *gitlab.Client) ([]*gitlab.Project, error) {
+	if len(s.repos) == 0 {
+		return s.getAllProjects(ctx, apiClient, nil)
+	}
+
+	var projects []*gitlab.Project
+	for _, repoURL := range s.repos {
+		path, err := projectPathFromRepoURL(repoURL)
+		if err != nil {
+			ctx.Logger().Error(err, "could not determine GitLab project path from repo URL", "repo", repoURL)
+			continue
+		}
+		project, _, err := apiClient.Projects.GetProject(path, nil)
+		if err != nil {
+			ctx.Logger().Error(err, "could not look up GitLab project", "path", path)
+			continue
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// projectPathFromRepoURL recovers a GitLab "namespace/project" path from a
+// clone URL like "https://gitlab.com/org/repo.git".
+func projectPathFromRepoURL(repoURL string) (string, error) {
+	trimmed := strings.TrimSuffix(repoURL, ".git")
+	idx := strings.Index(trimmed, "://")
+	if idx == -1 {
+		return "", fmt.Errorf("not a URL: %q", repoURL)
+	}
+	rest := trimmed[idx+len("://"):]
+	slash := strings.Index(rest, "/")
+	if slash == -1 || slash == len(rest)-1 {
+		return "", fmt.Errorf("no project path in URL: %q", repoURL)
+	}
+	return rest[slash+1:], nil
+}
+
+const socialPaginationLimit = 100 // Default is 20, max is 100.
+
+func (s *Source) scanMergeRequests(ctx context.Context, apiClient *gitlab.Client, project *gitlab.Project, chunksChan chan *sources.Chunk) error {
+	opts := &gitlab.ListProjectMergeRequestsOptions{ListOptions: gitlab.ListOptions{PerPage: socialPaginationLimit}}
+	for {
+		mrs, res, err := apiClient.MergeRequests.ListProjectMergeRequests(project.ID, opts)
+		if err != nil {
+			return fmt.Errorf("listing merge requests: %w", err)
+		}
+
+		for _, mr := range mrs {
+			s.chunkSocialText(project, mr.WebURL, mr.Author.Username, mr.UpdatedAt, mr.Description, chunksChan)
+
+			noteOpts := &gitlab.ListMergeRequestNotesOptions{PerPage: socialPaginationLimit}
+			for {
+				notes, noteRes, err := apiClient.Notes.ListMergeRequestNotes(project.ID, mr.IID, noteOpts)
+				if err != nil {
+					ctx.Logger().Error(err, "error listing merge request notes", "project", project.PathWithNamespace, "mr", mr.IID)
+					break
+				}
+				for _, note := range notes {
+					link := fmt.Sprintf("%s#note_%d", mr.WebURL, note.ID)
+					s.chunkSocialText(project, link, note.Author.Username, note.UpdatedAt, note.Body, chunksChan)
+				}
+				if noteRes.NextPage == 0 {
+					break
+				}
+				noteOpts.Page = noteRes.NextPage
+			}
+		}
+
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return nil
+}
+
+func (s *Source) scanIssues(ctx context.Context, apiClient *gitlab.Client, project *gitlab.Project, chunksChan chan *sources.Chunk) error {
+	opts := &gitlab.ListProjectIssuesOptions{ListOptions: gitlab.ListOptions{PerPage: socialPaginationLimit}}
+	for {
+		issues, res, err := apiClient.Issues.ListProjectIssues(project.ID, opts)
+		if err != nil {
+			return fmt.Errorf("listing issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			s.chunkSocialText(project, issue.WebURL, issue.Author.Username, issue.UpdatedAt, issue.Description, chunksChan)
+
+			noteOpts := &gitlab.ListIssueNotesOptions{PerPage: socialPaginationLimit}
+			for {
+				notes, noteRes, err := apiClient.Notes.ListIssueNotes(project.ID, issue.IID, noteOpts)
+				if err != nil {
+					ctx.Logger().Error(err, "error listing issue notes", "project", project.PathWithNamespace, "issue", issue.IID)
+					break
+				}
+				for _, note := range notes {
+					link := fmt.Sprintf("%s#note_%d", issue.WebURL, note.ID)
+					s.chunkSocialText(project, link, note.Author.Username, note.UpdatedAt, note.Body, chunksChan)
+				}
+				if noteRes.NextPage == 0 {
+					break
+				}
+				noteOpts.Page = noteRes.NextPage
+			}
+		}
+
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return nil
+}
+
+func (s *Source) scanSnippets(ctx context.Context, apiClient *gitlab.Client, project *gitlab.Project, chunksChan chan *sources.Chunk) error {
+	opts := &gitlab.ListProjectSnippetsOptions{PerPage: socialPaginationLimit}
+	for {
+		snippets, res, err := apiClient.ProjectSnippets.ListSnippets(project.ID, opts)
+		if err != nil {
+			return fmt.Errorf("listing snippets: %w", err)
+		}
+
+		for _, snippet := range snippets {
+			content, _, err := apiClient.ProjectSnippets.SnippetContent(project.ID, snippet.ID)
+			if err != nil {
+				ctx.Logger().Error(err, "error fetching snippet content", "project", project.PathWithNamespace, "snippet", snippet.ID)
+				continue
+			}
+			s.chunkSocialText(project, snippet.WebURL, snippet.Author.Username, snippet.UpdatedAt, string(content), chunksChan)
+		}
+
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return nil
+}
+
+// chunkSocialText emits a single chunk for a piece of non-git GitLab
+// content (a description, a comment, a snippet's body), tagged with a
+// source_metadatapb.Gitlab carrying link as a stable URL back to the
+// artifact it came from.
+func (s *Source) chunkSocialText(project *gitlab.Project, link, author string, updatedAt *time.Time, body string, chunksChan chan *sources.Chunk) {
+	if strings.TrimSpace(body) == "" {
+		return
+	}
+
+	metadata := &source_metadatapb.MetaData{
+		Data: &source_metadatapb.MetaData_Gitlab{
+			Gitlab: &source_metadatapb.Gitlab{
+				Repository: sanitizer.UTF8(project.PathWithNamespace),
+				Link:       link,
+				Email:      sanitizer.UTF8(author),
+				Timestamp:  formatGitlabTime(updatedAt),
+			},
+		},
+	}
+
+	chunksChan <- &sources.Chunk{
+		SourceName:     s.name,
+		SourceID:       s.sourceID,
+		SourceType:     s.Type(),
+		SourceMetadata: metadata,
+		Data:           []byte(body),
+		Verify:         s.verify,
+	}
+}
+
+// formatGitlabTime renders t in the same format git.go's commit timestamps
+// use, or "" if t is nil.
+func formatGitlabTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return sanitizer.UTF8(t.String())
+}
@@ -0,0 +1,130 @@
+package gitlab
+
+import (
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/source_metadatapb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sanitizer"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// scanCICDVariablesFor scans every enumerated project's (and, for any
+// configured group, that group's) CI/CD variables - including unmasked,
+// unprotected ones, which commonly carry long-lived credentials that
+// never show up in git history at all.
+func (s *Source) scanCICDVariablesFor(ctx context.Context, apiClient *gitlab.Client, chunksChan chan *sources.Chunk) error {
+	projects, err := s.projectsForSocialScan(ctx, apiClient)
+	if err != nil {
+		return fmt.Errorf("resolving projects for CI/CD variable scan: %w", err)
+	}
+
+	ignoreRepo := buildIgnorer(s.ignoreRepos, func(err error, pattern string) {
+		ctx.Logger().Error(err, "could not compile ignore repo glob", "glob", pattern)
+	})
+
+	for _, project := range projects {
+		if common.IsDone(ctx) {
+			return nil
+		}
+		if ignoreRepo(project.PathWithNamespace) {
+			continue
+		}
+		if err := s.scanProjectVariables(ctx, apiClient, project, chunksChan); err != nil {
+			ctx.Logger().Error(err, "error scanning project CI/CD variables", "project", project.PathWithNamespace)
+		}
+	}
+
+	for _, groupPath := range s.groups {
+		if common.IsDone(ctx) {
+			return nil
+		}
+		if ignoreRepo(groupPath) {
+			continue
+		}
+		if err := s.scanGroupVariables(ctx, apiClient, groupPath, chunksChan); err != nil {
+			ctx.Logger().Error(err, "error scanning group CI/CD variables", "group", groupPath)
+		}
+	}
+
+	return nil
+}
+
+func (s *Source) scanProjectVariables(ctx context.Context, apiClient *gitlab.Client, project *gitlab.Project, chunksChan chan *sources.Chunk) error {
+	opts := &gitlab.ListProjectVariablesOptions{PerPage: socialPaginationLimit}
+	for {
+		variables, res, err := apiClient.ProjectVariables.ListVariables(project.ID, opts)
+		if err != nil {
+			if rateLimited(ctx, res) {
+				continue
+			}
+			return fmt.Errorf("listing project variables: %w", err)
+		}
+
+		for _, v := range variables {
+			s.chunkCICDVariable(project.PathWithNamespace, v.Key, v.EnvironmentScope, "project", v.Value, chunksChan)
+		}
+
+		if res == nil || res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return nil
+}
+
+func (s *Source) scanGroupVariables(ctx context.Context, apiClient *gitlab.Client, groupPath string, chunksChan chan *sources.Chunk) error {
+	opts := &gitlab.ListGroupVariablesOptions{PerPage: socialPaginationLimit}
+	for {
+		variables, res, err := apiClient.GroupVariables.ListVariables(groupPath, opts)
+		if err != nil {
+			if rateLimited(ctx, res) {
+				continue
+			}
+			return fmt.Errorf("listing group variables: %w", err)
+		}
+
+		for _, v := range variables {
+			s.chunkCICDVariable(groupPath, v.Key, v.EnvironmentScope, "group", v.Value, chunksChan)
+		}
+
+		if res == nil || res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return nil
+}
+
+// chunkCICDVariable emits a single chunk for one CI/CD variable's value,
+// tagged with enough metadata (variable key, environment scope, and
+// whether it came from a project or a group) to act on a finding without
+// re-querying GitLab.
+func (s *Source) chunkCICDVariable(repository, key, environment, scope, value string, chunksChan chan *sources.Chunk) {
+	if value == "" {
+		return
+	}
+
+	metadata := &source_metadatapb.MetaData{
+		Data: &source_metadatapb.MetaData_Gitlab{
+			Gitlab: &source_metadatapb.Gitlab{
+				Repository:  sanitizer.UTF8(repository),
+				VariableKey: sanitizer.UTF8(key),
+				Environment: sanitizer.UTF8(environment),
+				Scope:       scope,
+			},
+		},
+	}
+
+	chunksChan <- &sources.Chunk{
+		SourceName:     s.name,
+		SourceID:       s.sourceID,
+		SourceType:     s.Type(),
+		SourceMetadata: metadata,
+		Data:           []byte(value),
+		Verify:         s.verify,
+	}
+}
@@ -0,0 +1,141 @@
+package journald
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/go-errors/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/source_metadatapb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sanitizer"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+// SourceType is the type of this source, used for matching source types in
+// configuration and job input.
+const SourceType = sourcespb.SourceType_SOURCE_TYPE_JOURNALD
+
+// Source scans structured log entries emitted by the local systemd journal. It
+// shells out to `journalctl`, the same way the git source shells out to `git`, rather
+// than linking against libsystemd, so it works in any environment with the CLI
+// installed.
+type Source struct {
+	name     string
+	sourceId int64
+	jobId    int64
+	verify   bool
+	conn     *sourcespb.Journald
+	sources.Progress
+}
+
+// Ensure the Source satisfies the interface at compile time.
+var _ sources.Source = (*Source)(nil)
+
+// Type returns the type of source.
+func (s *Source) Type() sourcespb.SourceType { return SourceType }
+
+func (s *Source) SourceID() int64 { return s.sourceId }
+
+func (s *Source) JobID() int64 { return s.jobId }
+
+// Init returns an initialized journald source.
+func (s *Source) Init(aCtx context.Context, name string, jobId, sourceId int64, verify bool, connection *anypb.Any, _ int) error {
+	s.name = name
+	s.sourceId = sourceId
+	s.jobId = jobId
+	s.verify = verify
+
+	var conn sourcespb.Journald
+	if err := anypb.UnmarshalTo(connection, &conn, proto.UnmarshalOptions{}); err != nil {
+		return errors.WrapPrefix(err, "error unmarshalling connection", 0)
+	}
+	s.conn = &conn
+
+	return nil
+}
+
+// Chunks emits chunks of bytes over a channel. Each journal entry becomes its own
+// chunk so that a secret logged in one line doesn't get lost in the noise of
+// surrounding, unrelated entries.
+func (s *Source) Chunks(ctx context.Context, chunksChan chan *sources.Chunk) error {
+	args := []string{"-o", "json"}
+	if unit := s.conn.GetUnit(); unit != "" {
+		args = append(args, "-u", unit)
+	}
+	if since := s.conn.GetSince(); since != "" {
+		args = append(args, "--since", since)
+	}
+	if s.conn.GetFollow() {
+		args = append(args, "--follow")
+	}
+
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("could not open journalctl stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not start journalctl: %w", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	// journald entries (e.g. embedded coredumps) can be much larger than bufio's
+	// default 64KiB token limit.
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var entryCount int
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			ctx.Logger().V(2).Info("skipping unparseable journald entry", "error", err)
+			continue
+		}
+
+		unit, _ := entry["_SYSTEMD_UNIT"].(string)
+		message, _ := entry["MESSAGE"].(string)
+		timestamp, _ := entry["__REALTIME_TIMESTAMP"].(string)
+
+		chunk := &sources.Chunk{
+			SourceType: s.Type(),
+			SourceName: s.name,
+			SourceID:   s.sourceId,
+			JobID:      s.jobId,
+			SourceMetadata: &source_metadatapb.MetaData{
+				Data: &source_metadatapb.MetaData_Journald{
+					Journald: &source_metadatapb.Journald{
+						Unit:      sanitizer.UTF8(unit),
+						Timestamp: sanitizer.UTF8(timestamp),
+					},
+				},
+			},
+			Data:   []byte(message),
+			Verify: s.verify,
+		}
+
+		select {
+		case chunksChan <- chunk:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		entryCount++
+		s.SetProgressComplete(entryCount, entryCount+1, fmt.Sprintf("%d journal entries scanned", entryCount), "")
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading journalctl output: %w", err)
+	}
+
+	return cmd.Wait()
+}
@@ -4,10 +4,22 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
 )
 
 var subRe = regexp.MustCompile(`\{\{[^{}]+\}\}`)
 
+const (
+	// defaultMaxSubstitutionDepth bounds how many levels of nested {{var}}
+	// expansion buildSubstitution will follow before giving up on a branch.
+	defaultMaxSubstitutionDepth = 10
+	// defaultMaxSubstitutionCombos bounds how many distinct expansions
+	// buildSubstitution will accumulate, to avoid OOMing on variables with
+	// many values across environments/collections.
+	defaultMaxSubstitutionCombos = 10_000
+)
+
 type VariableInfo struct {
 	value    string
 	Metadata Metadata
@@ -15,12 +27,42 @@ type VariableInfo struct {
 
 type Substitution struct {
 	variables map[string][]VariableInfo
+
+	maxDepth  int
+	maxCombos int
+}
+
+// SubstitutionOption configures limits on a Substitution.
+type SubstitutionOption func(*Substitution)
+
+// WithMaxSubstitutionDepth overrides the default max recursion depth used
+// when expanding {{var}} tokens.
+func WithMaxSubstitutionDepth(depth int) SubstitutionOption {
+	return func(sub *Substitution) {
+		sub.maxDepth = depth
+	}
+}
+
+// WithMaxSubstitutionCombos overrides the default cap on the number of
+// distinct expansions collected for a single piece of data.
+func WithMaxSubstitutionCombos(max int) SubstitutionOption {
+	return func(sub *Substitution) {
+		sub.maxCombos = max
+	}
 }
 
-func NewSubstitution() *Substitution {
-	return &Substitution{
+func NewSubstitution(opts ...SubstitutionOption) *Substitution {
+	sub := &Substitution{
 		variables: make(map[string][]VariableInfo),
+		maxDepth:  defaultMaxSubstitutionDepth,
+		maxCombos: defaultMaxSubstitutionCombos,
 	}
+
+	for _, opt := range opts {
+		opt(sub)
+	}
+
+	return sub
 }
 
 func (sub *Substitution) add(metadata Metadata, key string, value string) {
@@ -39,11 +81,11 @@ func (s *Source) keywordCombinations(str string) string {
 	return data
 }
 
-func (s *Source) buildSubstitueSet(metadata Metadata, data string) []string {
+func (s *Source) buildSubstitueSet(ctx context.Context, metadata Metadata, data string) []string {
 	var ret []string
 	combos := make(map[string]struct{})
 
-	s.buildSubstitution(data, metadata, &combos)
+	s.buildSubstitution(ctx, data, metadata, &combos, make(map[string]struct{}), 0)
 
 	for combo := range combos {
 		ret = append(ret, s.keywordCombinations(combo))
@@ -55,22 +97,69 @@ func (s *Source) buildSubstitueSet(metadata Metadata, data string) []string {
 	return ret
 }
 
-func (s *Source) buildSubstitution(data string, metadata Metadata, combos *map[string]struct{}) {
+// buildSubstitution recursively replaces {{var}} tokens in data with values
+// from s.sub.variables, re-scanning the result for further substitutions
+// until none remain.
+//
+// visited tracks the variable keys already being expanded on the current
+// recursion path: if expanding a key would require expanding itself again,
+// directly or transitively, that occurrence is left as-is rather than
+// recursed into, which breaks both self-referential (A={{A}}) and
+// mutually-referential (A={{B}}, B={{A}}) cycles. depth bounds how many
+// nested expansions are followed overall, and combos is capped at
+// s.sub.maxCombos to avoid combinatorial blow-up from many variables each
+// having many values.
+func (s *Source) buildSubstitution(ctx context.Context, data string, metadata Metadata, combos *map[string]struct{}, visited map[string]struct{}, depth int) {
+	if len(*combos) >= s.sub.maxCombos {
+		return
+	}
+
+	if depth >= s.sub.maxDepth {
+		ctx.Logger().V(2).Info("postman substitution: max recursion depth reached, truncating", "max_depth", s.sub.maxDepth)
+		(*combos)[data] = struct{}{}
+		return
+	}
+
 	matches := removeDuplicateStr(subRe.FindAllString(data, -1))
+
+	expanded := false
 	for _, match := range matches {
-		if slices, ok := s.sub.variables[strings.Trim(match, "{}")]; ok {
-			for _, slice := range slices {
-				if slice.Metadata.CollectionInfo.PostmanID != "" && slice.Metadata.CollectionInfo.PostmanID != metadata.CollectionInfo.PostmanID {
-					continue
-				}
-				d := strings.ReplaceAll(data, match, slice.value)
-				s.buildSubstitution(d, metadata, combos)
+		key := strings.Trim(match, "{}")
+		if _, cycle := visited[key]; cycle {
+			// key is already being expanded higher up this recursion path;
+			// treat this occurrence as terminal rather than looping forever.
+			continue
+		}
+
+		slices, ok := s.sub.variables[key]
+		if !ok {
+			continue
+		}
+
+		childVisited := make(map[string]struct{}, len(visited)+1)
+		for k := range visited {
+			childVisited[k] = struct{}{}
+		}
+		childVisited[key] = struct{}{}
+
+		for _, slice := range slices {
+			if slice.Metadata.CollectionInfo.PostmanID != "" && slice.Metadata.CollectionInfo.PostmanID != metadata.CollectionInfo.PostmanID {
+				continue
+			}
+			expanded = true
+			d := strings.ReplaceAll(data, match, slice.value)
+			s.buildSubstitution(ctx, d, metadata, combos, childVisited, depth+1)
+
+			if len(*combos) >= s.sub.maxCombos {
+				ctx.Logger().V(2).Info("postman substitution: max combinations reached, truncating", "max_combos", s.sub.maxCombos)
+				return
 			}
 		}
 	}
 
-	if len(matches) == 0 {
-		// add to combos
+	if !expanded {
+		// either there were no {{var}} tokens left, or every remaining one
+		// was a cycle or had no known value: this branch is terminal.
 		(*combos)[data] = struct{}{}
 	}
 }
@@ -0,0 +1,65 @@
+package postman
+
+import (
+	"testing"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+)
+
+func TestBuildSubstitueSet_SelfReferential(t *testing.T) {
+	sub := NewSubstitution()
+	sub.add(Metadata{}, "A", "prefix-{{A}}-suffix")
+
+	s := &Source{sub: sub}
+	ctx := context.Background()
+
+	got := s.buildSubstitueSet(ctx, Metadata{}, "{{A}}")
+	if len(got) == 0 {
+		t.Fatal("expected at least one substitution, got none")
+	}
+}
+
+func TestBuildSubstitueSet_MutuallyReferential(t *testing.T) {
+	sub := NewSubstitution()
+	sub.add(Metadata{}, "A", "{{B}}")
+	sub.add(Metadata{}, "B", "{{A}}")
+
+	s := &Source{sub: sub}
+	ctx := context.Background()
+
+	got := s.buildSubstitueSet(ctx, Metadata{}, "{{A}}")
+	if len(got) == 0 {
+		t.Fatal("expected at least one substitution, got none")
+	}
+}
+
+func TestBuildSubstitueSet_FanOutRespectsMaxCombos(t *testing.T) {
+	sub := NewSubstitution(WithMaxSubstitutionCombos(5))
+	for i := 0; i < 50; i++ {
+		sub.add(Metadata{}, "A", string(rune('a'+i%26)))
+	}
+
+	s := &Source{sub: sub}
+	ctx := context.Background()
+
+	got := s.buildSubstitueSet(ctx, Metadata{}, "{{A}}")
+	if len(got) > 5 {
+		t.Fatalf("expected at most 5 combinations, got %d", len(got))
+	}
+}
+
+func TestBuildSubstitueSet_RespectsMaxDepth(t *testing.T) {
+	sub := NewSubstitution(WithMaxSubstitutionDepth(2))
+	sub.add(Metadata{}, "A", "{{B}}")
+	sub.add(Metadata{}, "B", "{{C}}")
+	sub.add(Metadata{}, "C", "{{D}}")
+	sub.add(Metadata{}, "D", "leaf")
+
+	s := &Source{sub: sub}
+	ctx := context.Background()
+
+	got := s.buildSubstitueSet(ctx, Metadata{}, "{{A}}")
+	if len(got) == 0 {
+		t.Fatal("expected at least one substitution, got none")
+	}
+}
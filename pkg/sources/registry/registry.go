@@ -0,0 +1,225 @@
+// Package registry implements a source that discovers and scans every image
+// in a container registry (Docker Registry HTTP API v2), rather than
+// requiring callers to list images up front as the docker source does.
+package registry
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources/docker"
+)
+
+// SourceType is the type of this source, used for matching source types in
+// configuration and job input.
+const SourceType = sourcespb.SourceType_SOURCE_TYPE_REGISTRY
+
+type Source struct {
+	name     string
+	sourceId int64
+	jobId    int64
+	verify   bool
+	conn     sourcespb.Registry
+	sources.Progress
+}
+
+// Ensure the Source satisfies the interface at compile time.
+var _ sources.Source = (*Source)(nil)
+
+// Type returns the type of source.
+// It is used for matching source types in configuration and job input.
+func (s *Source) Type() sourcespb.SourceType { return SourceType }
+
+func (s *Source) SourceID() int64 {
+	return s.sourceId
+}
+
+func (s *Source) JobID() int64 {
+	return s.jobId
+}
+
+// Init initializes the source.
+func (s *Source) Init(_ context.Context, name string, jobId, sourceId int64, verify bool, connection *anypb.Any, _ int) error {
+	s.name = name
+	s.sourceId = sourceId
+	s.jobId = jobId
+	s.verify = verify
+
+	if err := anypb.UnmarshalTo(connection, &s.conn, proto.UnmarshalOptions{}); err != nil {
+		return fmt.Errorf("error unmarshalling connection: %w", err)
+	}
+
+	return nil
+}
+
+// Chunks enumerates every repository in the registry via the catalog API,
+// lists each repository's tags, applies the configured include/exclude
+// globs, and scans each resulting image:tag through the docker source.
+func (s *Source) Chunks(ctx context.Context, chunksChan chan *sources.Chunk) error {
+	reg, err := name.NewRegistry(registryHost(s.conn.GetUrl()))
+	if err != nil {
+		return fmt.Errorf("invalid registry url %q: %w", s.conn.GetUrl(), err)
+	}
+
+	opts, err := s.remoteOpts()
+	if err != nil {
+		return err
+	}
+
+	repos, err := remote.Catalog(ctx, reg, opts...)
+	if err != nil {
+		return fmt.Errorf("could not list repositories: %w", err)
+	}
+
+	concurrency := int(s.conn.GetConcurrency())
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var wg errgroup.Group
+	wg.SetLimit(concurrency)
+
+	for _, repoName := range repos {
+		if !matchesFilters(repoName, s.conn.GetRepoIncludes(), s.conn.GetRepoExcludes()) {
+			continue
+		}
+		repoName := repoName
+
+		wg.Go(func() error {
+			return s.scanRepo(ctx, reg, repoName, opts, chunksChan)
+		})
+	}
+
+	return wg.Wait()
+}
+
+// scanRepo lists repoName's tags, filters them, and scans each surviving
+// image:tag through the docker source.
+func (s *Source) scanRepo(ctx context.Context, reg name.Registry, repoName string, opts []remote.Option, chunksChan chan *sources.Chunk) error {
+	repo := reg.Repo(repoName)
+
+	tags, err := remote.List(repo, opts...)
+	if err != nil {
+		ctx.Logger().Error(err, "could not list tags for repository", "repository", repoName)
+		return nil
+	}
+
+	var images []string
+	for _, tag := range tags {
+		if !matchesFilters(tag, s.conn.GetTagIncludes(), s.conn.GetTagExcludes()) {
+			continue
+		}
+		images = append(images, fmt.Sprintf("%s/%s:%s", reg.Name(), repoName, tag))
+	}
+
+	if len(images) == 0 {
+		return nil
+	}
+
+	dockerSource, connAny, err := s.dockerSourceFor(images)
+	if err != nil {
+		return err
+	}
+
+	if err := dockerSource.Init(ctx, s.name, s.jobId, s.sourceId, s.verify, connAny, 1); err != nil {
+		return err
+	}
+
+	return dockerSource.Chunks(ctx, chunksChan)
+}
+
+// dockerSourceFor builds a docker.Source configured with images and the same
+// credentials as the registry source, so registry scanning can delegate the
+// actual pull-and-chunk work to the existing docker source.
+func (s *Source) dockerSourceFor(images []string) (*docker.Source, *anypb.Any, error) {
+	dockerConn := &sourcespb.Docker{Images: images}
+
+	switch s.conn.GetCredential().(type) {
+	case *sourcespb.Registry_BasicAuth:
+		dockerConn.Credential = &sourcespb.Docker_BasicAuth{
+			BasicAuth: &sourcespb.BasicAuth{
+				Username: s.conn.GetBasicAuth().GetUsername(),
+				Password: s.conn.GetBasicAuth().GetPassword(),
+			},
+		}
+	case *sourcespb.Registry_BearerToken:
+		dockerConn.Credential = &sourcespb.Docker_BearerToken{BearerToken: s.conn.GetBearerToken()}
+	default:
+		dockerConn.Credential = &sourcespb.Docker_Unauthenticated{}
+	}
+
+	var connAny anypb.Any
+	if err := anypb.MarshalFrom(&connAny, dockerConn, proto.MarshalOptions{}); err != nil {
+		return nil, nil, fmt.Errorf("error marshalling docker connection: %w", err)
+	}
+
+	return &docker.Source{}, &connAny, nil
+}
+
+// remoteOpts resolves the go-containerregistry auth option for this
+// registry's configured credential, supporting anonymous, basic auth, and
+// bearer-token flows (including the WWW-Authenticate challenge/token
+// exchange remote.WithAuth drives under the hood).
+func (s *Source) remoteOpts() ([]remote.Option, error) {
+	switch s.conn.GetCredential().(type) {
+	case *sourcespb.Registry_Unauthenticated, nil:
+		return nil, nil
+	case *sourcespb.Registry_BasicAuth:
+		return []remote.Option{
+			remote.WithAuth(&authn.Basic{
+				Username: s.conn.GetBasicAuth().GetUsername(),
+				Password: s.conn.GetBasicAuth().GetPassword(),
+			}),
+		}, nil
+	case *sourcespb.Registry_BearerToken:
+		return []remote.Option{
+			remote.WithAuth(&authn.Bearer{
+				Token: s.conn.GetBearerToken(),
+			}),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential type: %T", s.conn.Credential)
+	}
+}
+
+// registryHost strips a scheme from url, since name.NewRegistry expects a
+// bare host[:port].
+func registryHost(url string) string {
+	host := strings.TrimPrefix(url, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	return strings.TrimSuffix(host, "/")
+}
+
+// matchesFilters reports whether name satisfies the include/exclude glob
+// lists: it must match at least one include pattern (or there must be none)
+// and must match no exclude pattern.
+func matchesFilters(name string, includes, excludes []string) bool {
+	for _, exclude := range excludes {
+		if ok, _ := path.Match(exclude, name); ok {
+			return false
+		}
+	}
+
+	if len(includes) == 0 {
+		return true
+	}
+
+	for _, include := range includes {
+		if ok, _ := path.Match(include, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
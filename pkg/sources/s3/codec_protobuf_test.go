@@ -0,0 +1,98 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+func TestProtobufCodecRoundTrip(t *testing.T) {
+	u := SourceUnit{Bucket: "my-bucket", Key: "path/to/object.txt"}
+
+	codec, err := sources.SourceUnitCodecFor(protobufContentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := codec.MarshalSourceUnit(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := codec.UnmarshalSourceUnit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SourceUnitID() != u.SourceUnitID() {
+		t.Fatalf("got %v, want %v", got, u)
+	}
+}
+
+func TestProtobufCodecMissingRequiredField(t *testing.T) {
+	codec, err := sources.SourceUnitCodecFor(protobufContentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := codec.MarshalSourceUnit(SourceUnit{Bucket: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := codec.UnmarshalSourceUnit(data); err == nil {
+		t.Fatal("expected an error for a unit missing its key")
+	}
+}
+
+func TestProtobufCodecRejectsOtherUnitTypes(t *testing.T) {
+	codec, _ := sources.SourceUnitCodecFor(protobufContentType)
+	if _, err := codec.MarshalSourceUnit(sources.CommonSourceUnit{ID: "x"}); err == nil {
+		t.Fatal("expected an error marshaling a unit type this codec doesn't know")
+	}
+}
+
+func TestJSONCodecRoundTripsViaKindEnvelope(t *testing.T) {
+	u := SourceUnit{Bucket: "my-bucket", Key: "path/to/object.txt"}
+
+	codec, err := sources.SourceUnitCodecFor("application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := codec.MarshalSourceUnit(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := codec.UnmarshalSourceUnit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SourceUnitID() != u.SourceUnitID() {
+		t.Fatalf("got %v, want %v", got, u)
+	}
+}
+
+// BenchmarkJSONCodec and BenchmarkProtobufCodec marshal and unmarshal the
+// same SourceUnit through each registered codec, to compare the per-unit
+// cost an enumerator negotiating between them would actually pay.
+func BenchmarkJSONCodec(b *testing.B) {
+	benchmarkCodec(b, "application/json")
+}
+
+func BenchmarkProtobufCodec(b *testing.B) {
+	benchmarkCodec(b, protobufContentType)
+}
+
+func benchmarkCodec(b *testing.B, contentType string) {
+	codec, err := sources.SourceUnitCodecFor(contentType)
+	if err != nil {
+		b.Fatal(err)
+	}
+	u := SourceUnit{Bucket: "my-bucket-name", Key: "some/long/ish/object/key/path.txt"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := codec.MarshalSourceUnit(u)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := codec.UnmarshalSourceUnit(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
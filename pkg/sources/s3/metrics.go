@@ -0,0 +1,91 @@
+package s3
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metrics holds the Prometheus collectors used to observe S3 source behavior: API call
+// volume, bytes transferred, request errors (broken down by AWS error code), and
+// throttling events, all labeled by bucket, region, and assumed role so operators can
+// size concurrency and spot IAM or throttling problems per target.
+type metrics struct {
+	listObjectsCalls *prometheus.CounterVec
+	getObjectCalls   *prometheus.CounterVec
+	bytesDownloaded  *prometheus.CounterVec
+	requestErrors    *prometheus.CounterVec
+	throttlingEvents *prometheus.CounterVec
+	objectLatency    *prometheus.HistogramVec
+}
+
+var commonLabels = []string{"bucket", "region", "role_arn"}
+
+var sharedMetrics = &metrics{
+	listObjectsCalls: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "s3",
+		Name:      "list_objects_total",
+		Help:      "Total number of ListObjectsV2 API calls made against S3.",
+	}, commonLabels),
+	getObjectCalls: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "s3",
+		Name:      "get_object_total",
+		Help:      "Total number of GetObject API calls made against S3.",
+	}, commonLabels),
+	bytesDownloaded: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "s3",
+		Name:      "bytes_downloaded_total",
+		Help:      "Total number of object bytes downloaded from S3.",
+	}, commonLabels),
+	requestErrors: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "s3",
+		Name:      "request_errors_total",
+		Help:      "Total number of S3 API request errors, broken down by AWS error code.",
+	}, append(append([]string{}, commonLabels...), "code")),
+	throttlingEvents: promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trufflehog",
+		Subsystem: "s3",
+		Name:      "throttling_events_total",
+		Help:      "Total number of throttling responses (SlowDown, RequestLimitExceeded) received from S3.",
+	}, commonLabels),
+	objectLatency: promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "trufflehog",
+		Subsystem: "s3",
+		Name:      "object_handling_seconds",
+		Help:      "Time taken to fetch and hand off a single S3 object for scanning.",
+		Buckets:   prometheus.DefBuckets,
+	}, commonLabels)}
+
+// incListObjectsCalls records a ListObjectsV2 API call for the given bucket/region/role.
+func (m *metrics) incListObjectsCalls(bucket, region, roleArn string) {
+	m.listObjectsCalls.WithLabelValues(bucket, region, roleArn).Inc()
+}
+
+// incGetObjectCalls records a GetObject API call for the given bucket/region/role.
+func (m *metrics) incGetObjectCalls(bucket, region, roleArn string) {
+	m.getObjectCalls.WithLabelValues(bucket, region, roleArn).Inc()
+}
+
+// addBytesDownloaded records bytes downloaded for a single GetObject call.
+func (m *metrics) addBytesDownloaded(bucket, region, roleArn string, n int) {
+	m.bytesDownloaded.WithLabelValues(bucket, region, roleArn).Add(float64(n))
+}
+
+// incRequestErrors records an S3 API error, labeled by the AWS error code (e.g.
+// "AccessDenied", "NoSuchKey", "SlowDown").
+func (m *metrics) incRequestErrors(bucket, region, roleArn, code string) {
+	m.requestErrors.WithLabelValues(bucket, region, roleArn, code).Inc()
+}
+
+// incThrottlingEvents records a throttling response from S3.
+func (m *metrics) incThrottlingEvents(bucket, region, roleArn string) {
+	m.throttlingEvents.WithLabelValues(bucket, region, roleArn).Inc()
+}
+
+// observeObjectLatency records the time taken to handle a single object.
+func (m *metrics) observeObjectLatency(bucket, region, roleArn string, seconds float64) {
+	m.objectLatency.WithLabelValues(bucket, region, roleArn).Observe(seconds)
+}
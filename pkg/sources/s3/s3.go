@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -17,6 +19,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/go-errors/errors"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
@@ -53,9 +56,74 @@ type Source struct {
 	jobPool       *errgroup.Group
 	maxObjectSize int64
 
+	// throttleLimiter is shared across all jobPool workers so that a throttling
+	// response observed by one worker slows down every other worker hitting the same
+	// source, instead of each worker hammering the bucket independently.
+	throttleLimiter *rate.Limiter
+
 	sources.CommonSourceUnitUnmarshaller
 }
 
+const (
+	defaultGetObjectTimeout   = 30 * time.Second
+	defaultListObjectsTimeout = 30 * time.Second
+	defaultMaxRetries         = 10
+	defaultMinRetryDelay      = 50 * time.Millisecond
+	defaultMaxRetryDelay      = 5 * time.Second
+	defaultThrottleRateLimit  = 50 // requests/sec once throttling has been observed
+
+	// credentialExpiryWindow is how far ahead of actual expiry assumed-role and
+	// web-identity credentials are refreshed. Without this, a scan that outlives the
+	// session's TTL fails partway through instead of transparently re-assuming the role.
+	credentialExpiryWindow = 2 * time.Minute
+)
+
+// requestConfig resolves the configured RequestConfig, falling back to sane defaults
+// for any field that was left unset.
+type requestConfig struct {
+	getObjectTimeout   time.Duration
+	listObjectsTimeout time.Duration
+	maxRetries         int
+	minRetryDelay      time.Duration
+	maxRetryDelay      time.Duration
+	retryOnThrottle    bool
+}
+
+func (s *Source) requestConfig() requestConfig {
+	rc := s.conn.GetRequestConfig()
+
+	cfg := requestConfig{
+		getObjectTimeout:   defaultGetObjectTimeout,
+		listObjectsTimeout: defaultListObjectsTimeout,
+		maxRetries:         defaultMaxRetries,
+		minRetryDelay:      defaultMinRetryDelay,
+		maxRetryDelay:      defaultMaxRetryDelay,
+		retryOnThrottle:    true,
+	}
+	if rc == nil {
+		return cfg
+	}
+
+	if d := rc.GetGetObjectTimeout(); d != nil {
+		cfg.getObjectTimeout = d.AsDuration()
+	}
+	if d := rc.GetListObjectsTimeout(); d != nil {
+		cfg.listObjectsTimeout = d.AsDuration()
+	}
+	if rc.GetMaxRetries() > 0 {
+		cfg.maxRetries = int(rc.GetMaxRetries())
+	}
+	if d := rc.GetMinRetryDelay(); d != nil {
+		cfg.minRetryDelay = d.AsDuration()
+	}
+	if d := rc.GetMaxRetryDelay(); d != nil {
+		cfg.maxRetryDelay = d.AsDuration()
+	}
+	cfg.retryOnThrottle = rc.GetRetryOnThrottle()
+
+	return cfg
+}
+
 // Ensure the Source satisfies the interfaces at compile time
 var _ sources.Source = (*Source)(nil)
 var _ sources.SourceUnitUnmarshaller = (*Source)(nil)
@@ -86,6 +154,7 @@ func (s *Source) Init(
 	s.errorCount = &sync.Map{}
 	s.jobPool = &errgroup.Group{}
 	s.jobPool.SetLimit(concurrency)
+	s.throttleLimiter = rate.NewLimiter(rate.Inf, 1)
 
 	var conn sourcespb.S3
 	if err := anypb.UnmarshalTo(connection, &conn, proto.UnmarshalOptions{}); err != nil {
@@ -137,6 +206,16 @@ func (s *Source) newClient(region, roleArn string) (*s3.S3, error) {
 	cfg.CredentialsChainVerboseErrors = aws.Bool(true)
 	cfg.Region = aws.String(region)
 
+	rc := s.requestConfig()
+	cfg.MaxRetries = aws.Int(rc.maxRetries)
+	cfg.Retryer = client.DefaultRetryer{
+		NumMaxRetries:    rc.maxRetries,
+		MinRetryDelay:    rc.minRetryDelay,
+		MinThrottleDelay: rc.minRetryDelay,
+		MaxRetryDelay:    rc.maxRetryDelay,
+		MaxThrottleDelay: rc.maxRetryDelay,
+	}
+
 	switch cred := s.conn.GetCredential().(type) {
 	case *sourcespb.S3_SessionToken:
 		cfg.Credentials = credentials.NewStaticCredentials(
@@ -151,6 +230,26 @@ func (s *Source) newClient(region, roleArn string) (*s3.S3, error) {
 		log.RedactGlobally(cred.AccessKey.GetSecret())
 	case *sourcespb.S3_Unauthenticated:
 		cfg.Credentials = credentials.AnonymousCredentials
+	case *sourcespb.S3_WebIdentity:
+		// IAM Roles for Service Accounts (IRSA): exchange a projected Kubernetes service
+		// account token (or any other OIDC web identity token) for temporary
+		// credentials via sts:AssumeRoleWithWebIdentity.
+		sess, err := session.NewSession(cfg)
+		if err != nil {
+			return nil, err
+		}
+		stsClient := sts.New(sess)
+		sessionName := cred.WebIdentity.GetSessionName()
+		if sessionName == "" {
+			sessionName = "trufflehog"
+		}
+		cfg.Credentials = credentials.NewCredentials(stscreds.NewWebIdentityRoleProviderWithOptions(
+			stsClient,
+			cred.WebIdentity.GetRoleArn(),
+			sessionName,
+			stscreds.FetchTokenPath(cred.WebIdentity.GetTokenFile()),
+			func(p *stscreds.WebIdentityRoleProvider) { p.ExpiryWindow = credentialExpiryWindow },
+		))
 	default:
 		// In all other cases, the AWS SDK will follow its normal waterfall logic to pick up credentials (i.e. they can
 		// come from the environment or the credentials file or whatever else AWS gets up to).
@@ -165,9 +264,23 @@ func (s *Source) newClient(region, roleArn string) (*s3.S3, error) {
 		stsClient := sts.New(sess)
 		cfg.Credentials = stscreds.NewCredentialsWithClient(stsClient, roleArn, func(p *stscreds.AssumeRoleProvider) {
 			p.RoleSessionName = "trufflehog"
+			// Re-fetch ahead of actual expiry so that long scans that outlive a
+			// session's TTL get fresh credentials before AWS starts rejecting requests,
+			// rather than discovering expiry mid-request via ErrStaticCredentialsEmpty or
+			// an ExpiredToken error.
+			p.ExpiryWindow = credentialExpiryWindow
 		})
 	}
 
+	// Support S3-compatible object stores (MinIO, Ceph RadosGW, Wasabi, DigitalOcean
+	// Spaces, Backblaze B2, Cloudflare R2, LocalStack, etc.) that are reached through a
+	// custom endpoint rather than AWS's regional endpoints.
+	if endpoint := s.conn.GetEndpoint(); endpoint != "" {
+		cfg.Endpoint = aws.String(endpoint)
+		cfg.DisableSSL = aws.Bool(s.conn.GetDisableSsl())
+		cfg.S3ForcePathStyle = aws.Bool(s.conn.GetForcePathStyle())
+	}
+
 	sess, err := session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
 		Config:            *cfg,
@@ -179,6 +292,29 @@ func (s *Source) newClient(region, roleArn string) (*s3.S3, error) {
 	return s3.New(sess), nil
 }
 
+// usingCustomEndpoint reports whether the source is configured to talk to an
+// S3-compatible store rather than AWS itself.
+func (s *Source) usingCustomEndpoint() bool { return s.conn.GetEndpoint() != "" }
+
+// isExpiredCredentialsErr reports whether err indicates the request was signed with
+// credentials that expired before (or during) an in-flight request, which a retry with
+// freshly-fetched credentials can recover from.
+func isExpiredCredentialsErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	switch awsErr.Code() {
+	case credentials.ErrCodeStaticCredentialsEmpty, "ExpiredToken", "ExpiredTokenException", "RequestExpired":
+		return true
+	default:
+		return false
+	}
+}
+
 // getBucketsToScan returns a list of S3 buckets to scan.
 // If the connection has a list of buckets specified, those are returned.
 // Otherwise, it lists all buckets the client has access to and filters out the ignored ones.
@@ -241,6 +377,7 @@ func (ws *workerSignal) WasCancelled() bool { return ws.cancelled.Load() }
 // pageMetadata contains metadata about a single page of S3 objects being scanned.
 type pageMetadata struct {
 	bucket     string                  // The name of the S3 bucket being scanned
+	roleArn    string                  // The assumed role used for this scan, if any
 	pageNumber int                     // Current page number in the pagination sequence
 	client     *s3.S3                  // AWS S3 client configured for the appropriate region
 	page       *s3.ListObjectsV2Output // Contains the list of S3 objects in this page
@@ -300,42 +437,18 @@ func (s *Source) scanBuckets(
 			continue
 		}
 
-		errorCount := sync.Map{}
-
-		input := &s3.ListObjectsV2Input{Bucket: &bucket}
-		if bucket == resumePoint.CurrentBucket && resumePoint.StartAfter != "" {
-			input.StartAfter = &resumePoint.StartAfter
-			ctx.Logger().V(3).Info(
-				"Resuming bucket scan",
-				"start_after", resumePoint.StartAfter,
-			)
+		var startAfter string
+		if bucket == resumePoint.CurrentBucket {
+			startAfter = resumePoint.StartAfter
 		}
 
-		pageNumber := 1
-		err = regionalClient.ListObjectsV2PagesWithContext(
-			ctx,
-			input,
-			func(page *s3.ListObjectsV2Output, _ bool) bool {
-				pageMetadata := pageMetadata{
-					bucket:     bucket,
-					pageNumber: pageNumber,
-					client:     regionalClient,
-					page:       page,
-				}
-				processingState := processingState{
-					errorCount:   &errorCount,
-					objectCount:  &objectCount,
-					workerSignal: workerSignal,
-				}
-				s.pageChunker(ctx, pageMetadata, processingState, chunksChan)
-
-				if workerSignal.WasCancelled() {
-					return false // Stop pagination
-				}
+		prefixes, err := s.prefixesToScan(ctx, regionalClient, bucket)
+		if err != nil {
+			ctx.Logger().Error(err, "could not determine prefixes to scan for bucket")
+			continue
+		}
 
-				pageNumber++
-				return true
-			})
+		err = s.scanBucketPrefixes(ctx, regionalClient, bucket, role, prefixes, startAfter, workerSignal, &objectCount, chunksChan)
 
 		// Check if we stopped due to cancellation.
 		if workerSignal.WasCancelled() {
@@ -365,6 +478,126 @@ func (s *Source) scanBuckets(
 	return nil
 }
 
+// prefixesToScan returns the set of key prefixes that should be scanned, each in its
+// own shard. If the connection specifies explicit prefixes, those are used (minus any
+// in the ignore list). Otherwise, if a bucket_shard_delimiter is configured, the
+// top-level "directories" of the bucket are discovered via a delimited listing and
+// used as the shard boundaries. With neither configured, a single empty prefix is
+// returned, preserving the previous whole-bucket behavior.
+func (s *Source) prefixesToScan(ctx context.Context, client *s3.S3, bucket string) ([]string, error) {
+	if prefixes := s.conn.GetPrefixes(); len(prefixes) > 0 {
+		ignore := make(map[string]struct{}, len(s.conn.GetIgnorePrefixes()))
+		for _, p := range s.conn.GetIgnorePrefixes() {
+			ignore[p] = struct{}{}
+		}
+		var filtered []string
+		for _, p := range prefixes {
+			if _, ok := ignore[p]; !ok {
+				filtered = append(filtered, p)
+			}
+		}
+		return filtered, nil
+	}
+
+	delimiter := s.conn.GetBucketShardDelimiter()
+	if delimiter == "" {
+		return []string{""}, nil
+	}
+
+	ignore := make(map[string]struct{}, len(s.conn.GetIgnorePrefixes()))
+	for _, p := range s.conn.GetIgnorePrefixes() {
+		ignore[p] = struct{}{}
+	}
+
+	var prefixes []string
+	err := client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:    &bucket,
+		Delimiter: &delimiter,
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, cp := range page.CommonPrefixes {
+			if cp.Prefix == nil {
+				continue
+			}
+			if _, ok := ignore[*cp.Prefix]; !ok {
+				prefixes = append(prefixes, *cp.Prefix)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not discover shard prefixes for bucket %q: %w", bucket, err)
+	}
+	if len(prefixes) == 0 {
+		return []string{""}, nil
+	}
+
+	return prefixes, nil
+}
+
+// scanBucketPrefixes lists and chunks the given bucket, sharded across one concurrent
+// pager per prefix. A single empty prefix scans the whole bucket exactly as before.
+func (s *Source) scanBucketPrefixes(
+	ctx context.Context,
+	client *s3.S3,
+	bucket string,
+	roleArn string,
+	prefixes []string,
+	startAfter string,
+	workerSignal *workerSignal,
+	objectCount *uint64,
+	chunksChan chan *sources.Chunk,
+) error {
+	region := ""
+	if client.Config.Region != nil {
+		region = *client.Config.Region
+	}
+
+	var wg errgroup.Group
+	for _, prefix := range prefixes {
+		prefix := prefix
+		wg.Go(func() error {
+			ctx := context.WithValue(ctx, "prefix", prefix)
+
+			input := &s3.ListObjectsV2Input{Bucket: &bucket}
+			if prefix != "" {
+				input.Prefix = &prefix
+			}
+			if startAfter != "" {
+				input.StartAfter = &startAfter
+			}
+
+			errorCount := sync.Map{}
+			pageNumber := 1
+			return client.ListObjectsV2PagesWithContext(ctx, input, func(page *s3.ListObjectsV2Output, _ bool) bool {
+				sharedMetrics.incListObjectsCalls(bucket, region, roleArn)
+
+				pageMetadata := pageMetadata{
+					bucket:     bucket,
+					roleArn:    roleArn,
+					pageNumber: pageNumber,
+					client:     client,
+					page:       page,
+				}
+				processingState := processingState{
+					errorCount:   &errorCount,
+					objectCount:  objectCount,
+					workerSignal: workerSignal,
+				}
+				s.pageChunker(ctx, pageMetadata, processingState, chunksChan)
+
+				if workerSignal.WasCancelled() {
+					return false // Stop pagination
+				}
+
+				pageNumber++
+				return true
+			})
+		})
+	}
+
+	return wg.Wait()
+}
+
 // Chunks emits chunks of bytes over a channel.
 func (s *Source) Chunks(ctx context.Context, chunksChan chan *sources.Chunk, _ ...sources.ChunkingTarget) error {
 	visitor := func(c context.Context, defaultRegionClient *s3.S3, roleArn string, buckets []string) error {
@@ -380,6 +613,13 @@ func (s *Source) getRegionalClientForBucket(
 	role string,
 	bucket string,
 ) (*s3.S3, error) {
+	// Many S3-compatible providers don't implement the `GetBucketLocation` call that
+	// `GetBucketRegionWithClient` relies on, and since the endpoint is already pinned
+	// to a single place there's no regional redirection to resolve anyway.
+	if s.usingCustomEndpoint() {
+		return defaultRegionClient, nil
+	}
+
 	region, err := s3manager.GetBucketRegionWithClient(ctx, defaultRegionClient, bucket)
 	if err != nil {
 		return nil, fmt.Errorf("could not get s3 region for bucket: %s", bucket)
@@ -483,14 +723,32 @@ func (s *Source) pageChunker(
 			}
 			// Make sure we use a separate context for the GetObjectWithContext call.
 			// This ensures that the timeout is isolated and does not affect any downstream operations. (e.g. HandleFile)
-			const getObjectTimeout = 30 * time.Second
-			objCtx, cancel := context.WithTimeout(ctx, getObjectTimeout)
+			objCtx, cancel := context.WithTimeout(ctx, s.requestConfig().getObjectTimeout)
 			defer cancel()
 
-			res, err := metadata.client.GetObjectWithContext(objCtx, &s3.GetObjectInput{
-				Bucket: &metadata.bucket,
-				Key:    obj.Key,
-			})
+			if err := s.throttleLimiter.Wait(objCtx); err != nil {
+				return nil
+			}
+
+			region := ""
+			if metadata.client.Config.Region != nil {
+				region = *metadata.client.Config.Region
+			}
+
+			getStart := time.Now()
+			getInput := &s3.GetObjectInput{Bucket: &metadata.bucket, Key: obj.Key}
+			res, err := metadata.client.GetObjectWithContext(objCtx, getInput)
+			sharedMetrics.incGetObjectCalls(metadata.bucket, region, metadata.roleArn)
+			if isExpiredCredentialsErr(err) {
+				// The credential provider's ExpiryWindow should have refreshed ahead of
+				// this, but if an assume-role/web-identity session expired mid-flight,
+				// re-signing and retrying once picks up the now-refreshed credentials
+				// instead of failing the object and (eventually) excluding the prefix.
+				if res != nil && res.Body != nil {
+					res.Body.Close()
+				}
+				res, err = metadata.client.GetObjectWithContext(objCtx, getInput)
+			}
 			if err != nil {
 				if !strings.Contains(err.Error(), "AccessDenied") {
 					ctx.Logger().Error(err, "could not get S3 object")
@@ -503,6 +761,20 @@ func (s *Source) pageChunker(
 					res.Body.Close()
 				}
 
+				code := "Unknown"
+				throttled := false
+				if awsErr, ok := err.(awserr.Error); ok {
+					code = awsErr.Code()
+					if code == "SlowDown" || code == "RequestLimitExceeded" {
+						throttled = true
+						sharedMetrics.incThrottlingEvents(metadata.bucket, region, metadata.roleArn)
+						if s.requestConfig().retryOnThrottle {
+							s.throttleLimiter.SetLimit(rate.Limit(defaultThrottleRateLimit))
+						}
+					}
+				}
+				sharedMetrics.incRequestErrors(metadata.bucket, region, metadata.roleArn, code)
+
 				nErr, ok := state.errorCount.Load(prefix)
 				if !ok {
 					nErr = 0
@@ -515,11 +787,21 @@ func (s *Source) pageChunker(
 				state.errorCount.Store(prefix, nErr)
 				// too many consecutive errors on this page
 				if nErr.(int) > 3 {
-					ctx.Logger().V(2).Info("Too many consecutive errors, excluding prefix", "prefix", prefix)
+					if throttled {
+						ctx.Logger().Info("excessive errors on prefix caused by throttling, excluding prefix", "prefix", prefix)
+					} else {
+						ctx.Logger().V(2).Info("Too many consecutive errors, excluding prefix", "prefix", prefix)
+					}
 				}
 				return nil
 			}
 			defer res.Body.Close()
+			defer func() {
+				sharedMetrics.observeObjectLatency(metadata.bucket, region, metadata.roleArn, time.Since(getStart).Seconds())
+			}()
+			if obj.Size != nil {
+				sharedMetrics.addBytesDownloaded(metadata.bucket, region, metadata.roleArn, int(*obj.Size))
+			}
 
 			email := "Unknown"
 			if obj.Owner != nil {
@@ -536,7 +818,7 @@ func (s *Source) pageChunker(
 						S3: &source_metadatapb.S3{
 							Bucket:    metadata.bucket,
 							File:      sanitizer.UTF8(*obj.Key),
-							Link:      sanitizer.UTF8(makeS3Link(metadata.bucket, *metadata.client.Config.Region, *obj.Key)),
+							Link:      sanitizer.UTF8(s.makeS3Link(metadata.bucket, *metadata.client.Config.Region, *obj.Key)),
 							Email:     sanitizer.UTF8(email),
 							Timestamp: sanitizer.UTF8(modified),
 						},
@@ -643,9 +925,25 @@ func (s *Source) visitRoles(
 	return nil
 }
 
-// S3 links currently have the general format of:
+// makeS3Link returns a human-followable URL for the given object. Against AWS
+// it has the general format of:
 // https://[bucket].s3[.region unless us-east-1].amazonaws.com/[key]
-func makeS3Link(bucket, region, key string) string {
+// Against a configured S3-compatible endpoint, it instead emits a
+// provider-appropriate URL: path-style (https://endpoint/bucket/key) when
+// force_path_style is set, virtual-hosted-style (https://bucket.endpoint/key)
+// otherwise.
+func (s *Source) makeS3Link(bucket, region, key string) string {
+	if endpoint := s.conn.GetEndpoint(); endpoint != "" {
+		scheme := "https"
+		if s.conn.GetDisableSsl() {
+			scheme = "http"
+		}
+		if s.conn.GetForcePathStyle() {
+			return fmt.Sprintf("%s://%s/%s/%s", scheme, endpoint, bucket, key)
+		}
+		return fmt.Sprintf("%s://%s.%s/%s", scheme, bucket, endpoint, key)
+	}
+
 	if region == defaultAWSRegion {
 		region = ""
 	} else {
@@ -0,0 +1,171 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/testcontainers/testcontainers-go/modules/minio"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
+)
+
+func TestMakeS3Link(t *testing.T) {
+	tests := []struct {
+		name   string
+		conn   *sourcespb.S3
+		region string
+		want   string
+	}{
+		{
+			name:   "AWS, default region",
+			conn:   &sourcespb.S3{},
+			region: defaultAWSRegion,
+			want:   "https://my-bucket.s3.amazonaws.com/path/to/object.txt",
+		},
+		{
+			name:   "AWS, non-default region",
+			conn:   &sourcespb.S3{},
+			region: "eu-west-1",
+			want:   "https://my-bucket.s3.eu-west-1.amazonaws.com/path/to/object.txt",
+		},
+		{
+			name:   "custom endpoint, virtual-hosted style",
+			conn:   &sourcespb.S3{Endpoint: "minio.example.com:9000"},
+			region: defaultAWSRegion,
+			want:   "https://my-bucket.minio.example.com:9000/path/to/object.txt",
+		},
+		{
+			name:   "custom endpoint, force_path_style",
+			conn:   &sourcespb.S3{Endpoint: "minio.example.com:9000", ForcePathStyle: true},
+			region: defaultAWSRegion,
+			want:   "https://minio.example.com:9000/my-bucket/path/to/object.txt",
+		},
+		{
+			name:   "custom endpoint, force_path_style and disable_ssl",
+			conn:   &sourcespb.S3{Endpoint: "minio.example.com:9000", ForcePathStyle: true, DisableSsl: true},
+			region: defaultAWSRegion,
+			want:   "http://minio.example.com:9000/my-bucket/path/to/object.txt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Source{conn: tt.conn}
+			if got := s.makeS3Link("my-bucket", tt.region, "path/to/object.txt"); got != tt.want {
+				t.Errorf("makeS3Link() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientCustomEndpoint(t *testing.T) {
+	s := &Source{conn: &sourcespb.S3{
+		Endpoint:       "minio.example.com:9000",
+		ForcePathStyle: true,
+		DisableSsl:     true,
+		Credential:     &sourcespb.S3_Unauthenticated{Unauthenticated: &sourcespb.S3Unauthenticated{}},
+	}}
+
+	client, err := s.newClient(defaultAWSRegion, "")
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	if got := aws.StringValue(client.Config.Endpoint); got != "minio.example.com:9000" {
+		t.Errorf("Config.Endpoint = %q, want the configured custom endpoint", got)
+	}
+	if !aws.BoolValue(client.Config.DisableSSL) {
+		t.Error("Config.DisableSSL = false, want true")
+	}
+	if !aws.BoolValue(client.Config.S3ForcePathStyle) {
+		t.Error("Config.S3ForcePathStyle = false, want true")
+	}
+}
+
+func TestNewClientNoCustomEndpoint(t *testing.T) {
+	s := &Source{conn: &sourcespb.S3{
+		Credential: &sourcespb.S3_Unauthenticated{Unauthenticated: &sourcespb.S3Unauthenticated{}},
+	}}
+
+	client, err := s.newClient(defaultAWSRegion, "")
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	if got := aws.StringValue(client.Config.Endpoint); got != "" {
+		t.Errorf("Config.Endpoint = %q, want empty - no custom endpoint was configured", got)
+	}
+}
+
+// TestNewClientAgainstMinIO starts a real MinIO container and drives a
+// client built by newClient end-to-end against it, exercising the same
+// endpoint/force_path_style/disable_ssl wiring TestNewClientCustomEndpoint
+// checks in isolation, but against an actual S3-compatible store rather
+// than just inspecting the resulting client.Config.
+func TestNewClientAgainstMinIO(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := minio.RunContainer(ctx)
+	if err != nil {
+		t.Fatalf("could not start minio container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Logf("could not terminate minio container: %v", err)
+		}
+	})
+
+	endpoint, err := container.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("could not get minio connection string: %v", err)
+	}
+
+	s := &Source{conn: &sourcespb.S3{
+		Endpoint:       endpoint,
+		ForcePathStyle: true,
+		DisableSsl:     true,
+		Credential: &sourcespb.S3_AccessKey{
+			AccessKey: &sourcespb.S3AccessKey{Key: container.Username, Secret: container.Password},
+		},
+	}}
+
+	client, err := s.newClient(defaultAWSRegion, "")
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	const bucket, key, body = "trufflehog-test", "path/to/object.txt", "the secret is s3cr3t"
+
+	if _, err := client.CreateBucket(&awss3.CreateBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		t.Fatalf("could not create bucket: %v", err)
+	}
+	if _, err := client.PutObject(&awss3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   strings.NewReader(body),
+	}); err != nil {
+		t.Fatalf("could not put object: %v", err)
+	}
+
+	out, err := client.GetObject(&awss3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		t.Fatalf("could not get object: %v", err)
+	}
+	defer out.Body.Close()
+
+	got, err := io.ReadAll(out.Body)
+	if err != nil {
+		t.Fatalf("could not read object body: %v", err)
+	}
+	if string(got) != body {
+		t.Errorf("object body = %q, want %q", got, body)
+	}
+
+	if want := fmt.Sprintf("http://%s/%s/%s", endpoint, bucket, key); s.makeS3Link(bucket, defaultAWSRegion, key) != want {
+		t.Errorf("makeS3Link() = %q, want %q", s.makeS3Link(bucket, defaultAWSRegion, key), want)
+	}
+}
@@ -0,0 +1,36 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/sources"
+)
+
+//go:generate go run github.com/trufflesecurity/trufflehog/v3/pkg/sources/gen -type SourceUnit -out source_unit_gen.go
+
+// SourceUnit identifies one object within one S3 bucket. Bucket and Key are
+// both required: a unit with one but not the other doesn't name anything
+// ListObjects or GetObject could fetch.
+type SourceUnit struct {
+	Bucket string `json:"bucket" truffle:"required"`
+	Key    string `json:"key" truffle:"required"`
+}
+
+const sourceUnitKind = "s3"
+
+func init() {
+	sources.RegisterSourceUnitKind(sourceUnitKind, func() sources.SourceUnit { return &SourceUnit{} })
+}
+
+// SourceUnitID implements sources.SourceUnit.
+func (u SourceUnit) SourceUnitID() string {
+	return fmt.Sprintf("s3://%s/%s", u.Bucket, u.Key)
+}
+
+// SourceUnitKind implements sources.SourceUnitKinder, so the generic JSON
+// codec (sources.CommonSourceUnitUnmarshaller) wraps this unit's generated
+// MarshalJSON output in the "s3" envelope instead of emitting its bare
+// {"bucket", "key"} fields unwrapped.
+func (u SourceUnit) SourceUnitKind() string {
+	return sourceUnitKind
+}
@@ -1,8 +1,10 @@
 package sources
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"sync"
 )
 
 // Ensure CommonSourceUnit implements SourceUnit at compile time.
@@ -19,13 +21,146 @@ func (c CommonSourceUnit) SourceUnitID() string {
 	return c.ID
 }
 
+// CanonicalID implements CanonicalIDer, so CanonicalID(c) doesn't need to
+// canonicalMarshal the whole struct for what is, today, a single string
+// field.
+func (c CommonSourceUnit) CanonicalID() [32]byte {
+	return sha256.Sum256([]byte(c.ID))
+}
+
+// MarshalJSON implements json.Marshaler, wrapping c in the "common" kind
+// envelope (see RegisterSourceUnitKind) so a unit round-trips through
+// CommonSourceUnitUnmarshaller.UnmarshalSourceUnit unchanged. It marshals
+// through the unexported commonSourceUnitAlias type, not c directly, so
+// json.Marshal doesn't just call this method again and recurse forever.
+func (c CommonSourceUnit) MarshalJSON() ([]byte, error) {
+	type commonSourceUnitAlias CommonSourceUnit
+	return marshalSourceUnit(commonSourceUnitKind, commonSourceUnitAlias(c))
+}
+
+func init() {
+	RegisterSourceUnitKind(commonSourceUnitKind, func() SourceUnit { return &CommonSourceUnit{} })
+}
+
+// commonSourceUnitKind is the kind CommonSourceUnit registers itself under.
+const commonSourceUnitKind = "common"
+
+// sourceUnitEnvelope is the discriminated-union wire format a SourceUnit
+// serializes to: {"kind": "<registered kind>", "data": <kind-specific
+// payload>} - similar to how go-ethereum's Request dispatches on a
+// {type, data} envelope. Kind selects which registered factory
+// UnmarshalSourceUnit hands Data off to.
+type sourceUnitEnvelope struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+// marshalSourceUnit wraps unit in a sourceUnitEnvelope tagged kind.
+func marshalSourceUnit(kind string, unit any) ([]byte, error) {
+	data, err := json.Marshal(unit)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sourceUnitEnvelope{Kind: kind, Data: data})
+}
+
+var (
+	sourceUnitKindsMu sync.RWMutex
+	sourceUnitKinds   = make(map[string]func() SourceUnit)
+)
+
+// RegisterSourceUnitKind registers factory under kind, so a SourceUnit
+// envelope tagged {"kind": kind, ...} unmarshals into a fresh instance from
+// factory - which must return a pointer, so json.Unmarshal can populate it -
+// instead of falling back to the bare CommonSourceUnit payload. Each Source
+// that wants a richer unit type (carrying, say, a repo URL and commit, or a
+// bucket and key) registers its own kind in an init(), typically alongside
+// the Source's package declaration. Call it during package initialization,
+// before any SourceUnit envelopes tagged with kind are unmarshaled.
+func RegisterSourceUnitKind(kind string, factory func() SourceUnit) {
+	sourceUnitKindsMu.Lock()
+	defer sourceUnitKindsMu.Unlock()
+	sourceUnitKinds[kind] = factory
+}
+
+// sourceUnitFactory looks up the factory registered for kind.
+func sourceUnitFactory(kind string) (func() SourceUnit, bool) {
+	sourceUnitKindsMu.RLock()
+	defer sourceUnitKindsMu.RUnlock()
+	factory, ok := sourceUnitKinds[kind]
+	return factory, ok
+}
+
+// jsonContentType is the MIME type CommonSourceUnitUnmarshaller registers
+// itself under as a SourceUnitCodec.
+const jsonContentType = "application/json"
+
+func init() {
+	RegisterSourceUnitCodec(CommonSourceUnitUnmarshaller{})
+}
+
 // CommonSourceUnitUnmarshaller is an implementation of SourceUnitUnmarshaller
 // for the CommonSourceUnit. A source can embed this struct to gain the
-// functionality of converting []byte to a CommonSourceUnit.
+// functionality of converting []byte to a CommonSourceUnit. It also
+// implements the broader SourceUnitCodec as the registered codec for
+// "application/json" - the original, and still the default, wire format.
 type CommonSourceUnitUnmarshaller struct{}
 
-// Implement the SourceUnitUnmarshaller interface.
+// ContentType implements SourceUnitCodec.
+func (c CommonSourceUnitUnmarshaller) ContentType() string {
+	return jsonContentType
+}
+
+// SourceUnitKinder is implemented by a SourceUnit whose concrete type was
+// registered with RegisterSourceUnitKind, so MarshalSourceUnit knows which
+// envelope kind to wrap its own JSON encoding in. CommonSourceUnit doesn't
+// need this - its MarshalJSON already wraps itself - but a type generated
+// by pkg/sources/gen (see pkg/sources/s3/source_unit.go) marshals its bare
+// fields and relies on this to be wrapped into an envelope a later
+// UnmarshalSourceUnit call can dispatch back to the same kind.
+type SourceUnitKinder interface {
+	SourceUnitKind() string
+}
+
+// MarshalSourceUnit implements SourceUnitCodec. For a SourceUnit that
+// already wraps itself in the {"kind", "data"} envelope (CommonSourceUnit),
+// or that isn't registered under any kind at all, it delegates straight to
+// unit's own json.Marshaler. For a SourceUnitKinder, it wraps unit's own
+// JSON encoding in that envelope, so a later UnmarshalSourceUnit call - by
+// anyone, not just the process that produced these bytes - can dispatch it
+// back to the same concrete type via RegisterSourceUnitKind.
+func (c CommonSourceUnitUnmarshaller) MarshalSourceUnit(unit SourceUnit) ([]byte, error) {
+	kinder, ok := unit.(SourceUnitKinder)
+	if !ok {
+		return json.Marshal(unit)
+	}
+	data, err := json.Marshal(unit)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sourceUnitEnvelope{Kind: kinder.SourceUnitKind(), Data: data})
+}
+
+// UnmarshalSourceUnit implements the SourceUnitUnmarshaller interface. It
+// first tries data as a {"kind": ..., "data": ...} envelope, delegating to
+// whatever factory registered that kind via RegisterSourceUnitKind; failing
+// that (no "kind" field, or one that wasn't an object at all), it falls back
+// to parsing data as a bare {"source_unit_id": "..."} payload, so a job queue
+// populated before the kind/data envelope existed still unmarshals.
 func (c CommonSourceUnitUnmarshaller) UnmarshalSourceUnit(data []byte) (SourceUnit, error) {
+	var env sourceUnitEnvelope
+	if err := json.Unmarshal(data, &env); err == nil && env.Kind != "" {
+		factory, ok := sourceUnitFactory(env.Kind)
+		if !ok {
+			return nil, fmt.Errorf("unregistered source unit kind: %q", env.Kind)
+		}
+		unit := factory()
+		if err := json.Unmarshal(env.Data, unit); err != nil {
+			return nil, fmt.Errorf("unmarshaling %q source unit: %w", env.Kind, err)
+		}
+		return unit, nil
+	}
+
 	var unit CommonSourceUnit
 	if err := json.Unmarshal(data, &unit); err != nil {
 		return nil, err
@@ -1,12 +1,15 @@
 package sources
 
 import (
+	"io"
 	"sync"
+	"time"
 
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/trufflesecurity/trufflehog/v3/pkg/common"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+	"github.com/trufflesecurity/trufflehog/v3/pkg/gitignore"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/source_metadatapb"
 	"github.com/trufflesecurity/trufflehog/v3/pkg/pb/sourcespb"
 )
@@ -26,6 +29,24 @@ type Chunk struct {
 	Data []byte
 	// Verify specifies whether any secrets in the Chunk should be verified.
 	Verify bool
+
+	// DecodedRegions records, for Data that passed through one or more of
+	// pkg/decoders' transformations, where each decoded span came from in
+	// the data the decoder was given - so a finding's offset into Data can
+	// be translated back to its position in the original source.
+	DecodedRegions []DecodedRegion
+}
+
+// DecodedRegion records that the bytes [DecodedStart:DecodedEnd) in a
+// decode pass's output were decoded from [OriginalStart:OriginalEnd) in
+// the data that pass was given, and names which decoder produced it. For a
+// chunk that went through more than one decode round, a region from a
+// later round is relative to the previous round's output, not necessarily
+// the original top-level chunk.
+type DecodedRegion struct {
+	OriginalStart, OriginalEnd int
+	DecodedStart, DecodedEnd   int
+	Decoder                    string
 }
 
 // Source defines the interface required to implement a source chunker.
@@ -150,6 +171,11 @@ type GitConfig struct {
 	ExcludeGlobs []string
 
 	SinceDate string
+
+	// RespectGitignore, when true, skips files matched by the repository's
+	// own .gitignore files and .gitattributes linguist-generated rules, in
+	// addition to whatever Filter/ExcludeGlobs already exclude.
+	RespectGitignore bool
 }
 
 // GithubConfig defines the optional configuration for a github source.
@@ -189,6 +215,17 @@ type GitlabConfig struct {
 	Repos []string
 	// Filter is the filter to use to scan the source.
 	Filter *common.Filter
+	// Concurrency is the number of concurrent workers to use to scan the source.
+	Concurrency int
+	// Depth, if greater than zero, clones each repository shallowly to
+	// this many commits instead of cloning full history.
+	Depth int
+	// Since, if non-zero, skips any project whose last activity predates
+	// it entirely, and excludes commits older than it from the scan.
+	Since time.Time
+	// SingleBranch, if set, clones only this branch instead of all
+	// branches.
+	SingleBranch string
 }
 
 // FilesystemConfig defines the optional configuration for a filesystem source.
@@ -197,6 +234,23 @@ type FilesystemConfig struct {
 	Paths []string
 	// Filter is the filter to use to scan the source.
 	Filter *common.Filter
+	// IgnoreMatcher, if set, is consulted for every candidate file
+	// alongside Filter; a path it reports as ignored is skipped before a
+	// chunk is ever produced for it. Build one with
+	// gitignore.BuildMatcher(root) to honor any .trufflehogignore files
+	// found under root.
+	IgnoreMatcher *gitignore.Matcher
+}
+
+// DiffConfig defines the optional configuration for a unified-diff source,
+// which scans only the added/context lines of each hunk in a diff (e.g. the
+// output of `git diff`, a GitHub/GitLab pull request diff, or a .patch
+// file) rather than whole file blobs. Each resulting chunk's hunk carries
+// the post-image path and starting line needed to compute a correct
+// #L<n> link back to the changed line.
+type DiffConfig struct {
+	// Reader supplies the unified diff to parse.
+	Reader io.Reader
 }
 
 // S3Config defines the optional configuration for an S3 source.
@@ -232,6 +286,77 @@ type SyslogConfig struct {
 	Concurrency int
 }
 
+// JournaldConfig defines the optional configuration for a journald source.
+type JournaldConfig struct {
+	// Unit restricts the scan to a single systemd unit. If empty, the whole journal
+	// is scanned.
+	Unit,
+	// Since restricts the scan to entries at or after this time, in any format
+	// accepted by `journalctl --since`.
+	Since string
+	// Follow indicates whether to continue tailing the journal for new entries
+	// after the backlog has been scanned.
+	Follow bool
+}
+
+// GCLConfig defines the optional configuration for a Google Cloud Logging source.
+type GCLConfig struct {
+	// ProjectID is the GCP project whose log entries should be scanned.
+	ProjectID,
+	// Filter is an optional Cloud Logging query used to restrict which entries are
+	// returned.
+	Filter,
+	// ApiKey is the API key to use to authenticate with the source.
+	ApiKey,
+	// AccessToken is an OAuth2 access token to use to authenticate with the source.
+	AccessToken string
+}
+
+// DockerConfig defines the optional configuration for a Docker source.
+type DockerConfig struct {
+	// Images is the list of images to scan. Use the file:// prefix to point
+	// to a local tarball, otherwise an image registry is assumed.
+	Images []string
+	// BearerToken authenticates with the registry. If empty, and
+	// UseDockerKeychain is false, images are pulled anonymously.
+	BearerToken string
+	// UseDockerKeychain authenticates with the registry using the local
+	// Docker config's credential store.
+	UseDockerKeychain bool
+	// Platforms restricts which platform(s) of a multi-architecture image are
+	// scanned, e.g. "linux/amd64". If empty and AllPlatforms is false, only
+	// the host's platform is scanned.
+	Platforms []string
+	// AllPlatforms scans every platform in a multi-architecture image,
+	// ignoring Platforms.
+	AllPlatforms bool
+}
+
+// RegistryConfig defines the optional configuration for scanning every image
+// in a container registry.
+type RegistryConfig struct {
+	// URL is the base URL of the registry, e.g. https://registry-1.docker.io.
+	URL string
+	// RepoIncludes and RepoExcludes are glob patterns matched against
+	// repository names returned by the registry's catalog. A repository is
+	// scanned only if it matches an include pattern (or there are none) and
+	// matches no exclude pattern.
+	RepoIncludes, RepoExcludes []string
+	// TagIncludes and TagExcludes are glob patterns matched against tag names,
+	// applied the same way as RepoIncludes/RepoExcludes.
+	TagIncludes, TagExcludes []string
+	// Username and Password authenticate with HTTP Basic auth. Token
+	// authenticates with a bearer token. At most one of the two should be set;
+	// if neither is set, requests are made anonymously.
+	Username, Password, Token string
+	// Platform restricts which platform's layers are scanned for a
+	// multi-architecture image, e.g. "linux/amd64". If empty, the registry's
+	// default platform is used.
+	Platform string
+	// Concurrency is the number of repositories scanned concurrently.
+	Concurrency int
+}
+
 // Progress is used to update job completion progress across sources.
 type Progress struct {
 	mut               sync.Mutex
@@ -0,0 +1,240 @@
+// Package support builds a "support bundle": a single gzip-compressed tar
+// archive gathering everything needed to triage a user-reported scan
+// problem — the build version, resolved CLI flags (with credentials
+// redacted), the detector list and its enabled/disabled state, recent log
+// output, metrics from the most recent scan, the contents of any --config
+// file, and Go runtime / OS info — so a user can attach one file to a bug
+// report instead of re-describing their environment by hand.
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bundle is the set of inputs serialized into a support bundle.
+type Bundle struct {
+	Version     string            `json:"version"`
+	GeneratedAt string            `json:"generated_at"`
+	Flags       map[string]string `json:"flags"`
+	Detectors   []DetectorStatus  `json:"detectors"`
+	RecentLog   []string          `json:"recent_log"`
+	LastRun     *RunMetrics       `json:"last_run,omitempty"`
+	ConfigFile  string            `json:"config_file,omitempty"`
+	Runtime     RuntimeInfo       `json:"runtime"`
+}
+
+// DetectorStatus records whether a detector was enabled under the
+// --include-detectors/--exclude-detectors configuration in effect.
+type DetectorStatus struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// RunMetrics mirrors the subset of engine.Metrics and
+// Engine.GetDetectorsMetrics worth shipping in a bug report.
+type RunMetrics struct {
+	ChunksScanned          uint64            `json:"chunks_scanned"`
+	BytesScanned           uint64            `json:"bytes_scanned"`
+	VerifiedSecretsFound   uint64            `json:"verified_secrets_found"`
+	UnverifiedSecretsFound uint64            `json:"unverified_secrets_found"`
+	ScanDuration           string            `json:"scan_duration"`
+	DetectorLatency        map[string]string `json:"detector_latency,omitempty"`
+}
+
+// RuntimeInfo captures the Go runtime and OS trufflehog ran under.
+type RuntimeInfo struct {
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	NumCPU    int    `json:"num_cpu"`
+}
+
+// NewRuntimeInfo returns the current process's Go runtime and OS info.
+func NewRuntimeInfo() RuntimeInfo {
+	return RuntimeInfo{
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+	}
+}
+
+// redactedFlagNameSuffixes lists (case-insensitive) flag-name suffixes whose
+// value is always stripped before it goes into a support bundle.
+var redactedFlagNameSuffixes = []string{"token", "secret", "password", "key", "apikey"}
+
+// secretLikePattern is a best-effort fallback for flag values that look like
+// a credential even when their flag name doesn't, covering the kind of
+// values built-in detectors match: well-known credential prefixes (AWS
+// access keys, GitHub/GitLab/Slack tokens), private key headers, and long
+// base64/hex runs.
+var secretLikePattern = regexp.MustCompile(`(?i)AKIA[0-9A-Z]{16}|gh[pousr]_[0-9A-Za-z]{36}|glpat-[0-9A-Za-z\-_]{20}|xox[baprs]-[0-9A-Za-z-]{10,}|-----BEGIN [A-Z ]*PRIVATE KEY-----|\b[0-9A-Za-z+/]{32,}={0,2}\b`)
+
+// RedactFlagValue returns value unchanged unless name looks like a
+// credential-bearing flag, or value itself looks like a secret a built-in
+// detector would flag, in which case it returns "REDACTED".
+func RedactFlagValue(name, value string) string {
+	if value == "" {
+		return value
+	}
+
+	lower := strings.ToLower(name)
+	for _, suffix := range redactedFlagNameSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return "REDACTED"
+		}
+	}
+
+	if secretLikePattern.MatchString(value) {
+		return "REDACTED"
+	}
+
+	return value
+}
+
+// LogBuffer retains the most recently written lines, for inclusion in a
+// support bundle. It is safe for concurrent use; wire it up as an
+// io.MultiWriter sink alongside the main logger's usual output.
+type LogBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	max   int
+}
+
+// NewLogBuffer returns a LogBuffer retaining at most max lines.
+func NewLogBuffer(max int) *LogBuffer {
+	return &LogBuffer{max: max}
+}
+
+// Write implements io.Writer, splitting p into lines and retaining only the
+// most recent max of them.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines = append(b.lines, strings.Split(strings.TrimRight(string(p), "\n"), "\n")...)
+	if overflow := len(b.lines) - b.max; overflow > 0 {
+		b.lines = b.lines[overflow:]
+	}
+
+	return len(p), nil
+}
+
+// Lines returns a copy of the currently retained lines, oldest first.
+func (b *LogBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]string, len(b.lines))
+	copy(out, b.lines)
+	return out
+}
+
+// Generate serializes bundle as bundle.json into a gzip-compressed tar
+// archive and returns the archive's bytes.
+func Generate(bundle Bundle) ([]byte, error) {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal support bundle: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	if err := addFile(tw, "bundle.json", data); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("could not finalize support bundle archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("could not finalize support bundle archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func addFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("could not write %s header: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("could not write %s: %w", name, err)
+	}
+	return nil
+}
+
+// lastRunCachePath returns the path SaveLastRunMetrics/LoadLastRunMetrics
+// persist to, so a later `support bundle` invocation can report on the most
+// recent scan without one having to be running.
+func lastRunCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine cache directory: %w", err)
+	}
+
+	dir = filepath.Join(dir, "trufflehog")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("could not create cache directory: %w", err)
+	}
+
+	return filepath.Join(dir, "last_run.json"), nil
+}
+
+// SaveLastRunMetrics persists metrics so a later `support bundle` invocation
+// can include them.
+func SaveLastRunMetrics(metrics RunMetrics) error {
+	path, err := lastRunCachePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		return fmt.Errorf("could not marshal last run metrics: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// LoadLastRunMetrics returns the metrics saved by the most recent
+// SaveLastRunMetrics call, or nil if no scan has run yet.
+func LoadLastRunMetrics() (*RunMetrics, error) {
+	path, err := lastRunCachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read last run metrics: %w", err)
+	}
+
+	var metrics RunMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		return nil, fmt.Errorf("could not parse last run metrics: %w", err)
+	}
+
+	return &metrics, nil
+}
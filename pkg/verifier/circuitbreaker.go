@@ -0,0 +1,84 @@
+package verifier
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker trips to open after tripThreshold consecutive failures
+// (5xx responses or transport errors), fails every request fast while open,
+// and after cooldown has elapsed lets exactly one trial request through
+// (half-open): its success closes the breaker, its failure reopens it.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	tripThreshold int
+	cooldown      time.Duration
+
+	state           breakerState
+	consecutiveFail int
+	openedAt        time.Time
+	trialInFlight   bool
+}
+
+func newCircuitBreaker(tripThreshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{tripThreshold: tripThreshold, cooldown: cooldown}
+}
+
+// Allow reports whether a request should be let through right now.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.trialInFlight = true
+		return true
+	case breakerHalfOpen:
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	default: // breakerClosed
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets its failure count.
+func (b *circuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFail = 0
+	b.state = breakerClosed
+	b.trialInFlight = false
+}
+
+// RecordFailure counts a failure, tripping the breaker open if it was the
+// half-open trial request or if tripThreshold consecutive failures have now
+// accumulated.
+func (b *circuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasTrial := b.trialInFlight
+	b.trialInFlight = false
+	b.consecutiveFail++
+
+	if wasTrial || b.consecutiveFail >= b.tripThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
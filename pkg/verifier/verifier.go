@@ -0,0 +1,266 @@
+// Package verifier layers a shared rate limiter, concurrency cap, circuit
+// breaker, and response cache between detectors and the network. It exists
+// so that many detectors hitting the same verification endpoint (as custom
+// regex webhook detectors and overlapping chunk detectors both do) share one
+// well-behaved client instead of each issuing independent, uncoordinated
+// HTTP requests.
+package verifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+)
+
+// Transport performs the HTTP round trip for a verification request.
+// Implementations can be swapped in tests (a mock) or in production (a
+// signed-request transport) without touching Verifier itself.
+type Transport interface {
+	RoundTrip(req *http.Request) (*http.Response, error)
+}
+
+// HTTPTransport adapts an *http.Client to Transport.
+type HTTPTransport struct {
+	Client *http.Client
+}
+
+// RoundTrip implements Transport.
+func (t HTTPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// Result is a cacheable summary of a verification response.
+type Result struct {
+	StatusCode int
+	Body       []byte
+}
+
+const (
+	defaultRatePerSecond = 5
+	defaultBurst         = 5
+	defaultMaxInFlight   = 10
+	defaultTripThreshold = 5
+	defaultCooldown      = 30 * time.Second
+	defaultCacheTTL      = 5 * time.Minute
+)
+
+// Option configures a Verifier.
+type Option func(*Verifier)
+
+// WithRateLimit sets the per-host sustained rate and burst size.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(v *Verifier) {
+		v.ratePerSecond = perSecond
+		v.burst = burst
+	}
+}
+
+// WithMaxInFlight sets the per-host cap on concurrent in-flight requests.
+func WithMaxInFlight(n int) Option {
+	return func(v *Verifier) { v.maxInFlight = n }
+}
+
+// WithTripThreshold sets the number of consecutive failures (5xx responses
+// or transport errors) a host's circuit breaker tolerates before opening.
+func WithTripThreshold(n int) Option {
+	return func(v *Verifier) { v.tripThreshold = n }
+}
+
+// WithCooldown sets how long an open circuit breaker waits before letting a
+// single half-open trial request through.
+func WithCooldown(d time.Duration) Option {
+	return func(v *Verifier) { v.cooldown = d }
+}
+
+// WithCacheTTL sets how long a (endpoint, secret) result is reused before a
+// fresh request is made.
+func WithCacheTTL(d time.Duration) Option {
+	return func(v *Verifier) { v.cacheTTL = d }
+}
+
+// Verifier issues verification HTTP requests on behalf of detectors,
+// sharing one rate limiter, in-flight cap, and circuit breaker per host, and
+// deduping identical (endpoint, secret) requests through a response cache.
+type Verifier struct {
+	transport Transport
+
+	ratePerSecond float64
+	burst         int
+	maxInFlight   int
+	tripThreshold int
+	cooldown      time.Duration
+	cacheTTL      time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+
+	cacheMu sync.Mutex
+	cache   map[string]cacheEntry
+
+	inflight singleflight.Group
+}
+
+type hostState struct {
+	limiter  *rate.Limiter
+	inFlight chan struct{}
+	breaker  *circuitBreaker
+}
+
+type cacheEntry struct {
+	result  *Result
+	err     error
+	expires time.Time
+}
+
+// inflightResult is the value shared by singleflight.Group.Do among callers
+// that collapsed onto the same in-flight request, carrying the round trip's
+// error alongside its result since singleflight.Do's own error return is
+// reserved for a panic/goroutine-exit in the shared function itself.
+type inflightResult struct {
+	result *Result
+	err    error
+}
+
+// New returns a Verifier issuing requests through transport.
+func New(transport Transport, opts ...Option) *Verifier {
+	v := &Verifier{
+		transport:     transport,
+		ratePerSecond: defaultRatePerSecond,
+		burst:         defaultBurst,
+		maxInFlight:   defaultMaxInFlight,
+		tripThreshold: defaultTripThreshold,
+		cooldown:      defaultCooldown,
+		cacheTTL:      defaultCacheTTL,
+		hosts:         make(map[string]*hostState),
+		cache:         make(map[string]cacheEntry),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Do issues req, scoped to secret for caching and circuit breaking, and
+// returns the (possibly cached) result. Concurrent calls sharing the same
+// (endpoint, secret) collapse onto a single round trip via an
+// inflight singleflight.Group, keyed the same as the cache, so a burst of
+// detectors probing the same credential at once (the common case this
+// package exists for) only ever reaches the network once. Callers should
+// treat a non-nil error as "verification undetermined", not "verification
+// failed": it may mean the host's circuit breaker is currently open rather
+// than that the secret is invalid.
+func (v *Verifier) Do(ctx context.Context, req *http.Request, secret string) (*Result, error) {
+	key := cacheKey(req, secret)
+	if entry, ok := v.lookupCache(key); ok {
+		return entry.result, entry.err
+	}
+
+	v_, _, _ := v.inflight.Do(key, func() (any, error) {
+		// Re-check the cache now that we hold the in-flight slot for key:
+		// whichever caller lost the singleflight race already populated it.
+		if entry, ok := v.lookupCache(key); ok {
+			return inflightResult{entry.result, entry.err}, nil
+		}
+
+		host := req.URL.Host
+		hs := v.hostState(host)
+
+		if !hs.breaker.Allow() {
+			return inflightResult{nil, fmt.Errorf("verifier: circuit breaker open for host %q", host)}, nil
+		}
+
+		if err := hs.limiter.Wait(ctx); err != nil {
+			return inflightResult{nil, err}, nil
+		}
+
+		select {
+		case hs.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return inflightResult{nil, ctx.Err()}, nil
+		}
+		defer func() { <-hs.inFlight }()
+
+		result, err := v.roundTrip(req, hs)
+		v.storeCache(key, result, err)
+		return inflightResult{result, err}, nil
+	})
+
+	ir := v_.(inflightResult)
+	return ir.result, ir.err
+}
+
+func (v *Verifier) roundTrip(req *http.Request, hs *hostState) (*Result, error) {
+	res, err := v.transport.RoundTrip(req)
+	if err != nil {
+		hs.breaker.RecordFailure()
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		hs.breaker.RecordFailure()
+		return nil, err
+	}
+
+	if res.StatusCode >= 500 {
+		hs.breaker.RecordFailure()
+	} else {
+		hs.breaker.RecordSuccess()
+	}
+
+	return &Result{StatusCode: res.StatusCode, Body: body}, nil
+}
+
+func (v *Verifier) hostState(host string) *hostState {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	hs, ok := v.hosts[host]
+	if !ok {
+		hs = &hostState{
+			limiter:  rate.NewLimiter(rate.Limit(v.ratePerSecond), v.burst),
+			inFlight: make(chan struct{}, v.maxInFlight),
+			breaker:  newCircuitBreaker(v.tripThreshold, v.cooldown),
+		}
+		v.hosts[host] = hs
+	}
+	return hs
+}
+
+func (v *Verifier) lookupCache(key string) (cacheEntry, bool) {
+	v.cacheMu.Lock()
+	defer v.cacheMu.Unlock()
+
+	entry, ok := v.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (v *Verifier) storeCache(key string, result *Result, err error) {
+	v.cacheMu.Lock()
+	defer v.cacheMu.Unlock()
+	v.cache[key] = cacheEntry{result: result, err: err, expires: time.Now().Add(v.cacheTTL)}
+}
+
+// cacheKey scopes a cache entry to both the endpoint and a hash of the
+// secret being verified, so distinct secrets against the same endpoint never
+// share a cached result.
+func cacheKey(req *http.Request, secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return req.URL.String() + ":" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,117 @@
+package verifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRequest(t *testing.T, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	return req
+}
+
+func TestVerifierCachesOverlappingRequests(t *testing.T) {
+	var calls int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	v := New(HTTPTransport{Client: srv.Client()})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := v.Do(context.Background(), newRequest(t, srv.URL), "same-secret"); err != nil {
+				t.Errorf("Do() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&calls); got != 1 {
+		t.Errorf("server received %d requests, want 1 (overlapping detectors should dedupe via cache)", got)
+	}
+}
+
+func TestCircuitBreakerTripsAndRecovers(t *testing.T) {
+	var failing int32 = 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	v := New(HTTPTransport{Client: srv.Client()},
+		WithTripThreshold(3),
+		WithCooldown(20*time.Millisecond),
+		WithCacheTTL(0),
+	)
+
+	for i := 0; i < 3; i++ {
+		if _, err := v.Do(context.Background(), newRequest(t, srv.URL), "secret-a"); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+
+	// The breaker should now be open, failing fast without hitting the server.
+	if _, err := v.Do(context.Background(), newRequest(t, srv.URL), "secret-b"); err == nil {
+		t.Fatal("expected circuit breaker to be open and reject the request")
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	time.Sleep(30 * time.Millisecond)
+
+	// The half-open trial request should succeed and close the breaker again.
+	if _, err := v.Do(context.Background(), newRequest(t, srv.URL), "secret-c"); err != nil {
+		t.Fatalf("expected the half-open trial request to succeed, got error = %v", err)
+	}
+	if _, err := v.Do(context.Background(), newRequest(t, srv.URL), "secret-d"); err != nil {
+		t.Fatalf("expected the breaker to stay closed after recovery, got error = %v", err)
+	}
+}
+
+func TestVerifierConcurrentAccessRace(t *testing.T) {
+	var flip int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&flip, 1)%5 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	v := New(HTTPTransport{Client: srv.Client()},
+		WithMaxInFlight(4),
+		WithTripThreshold(2),
+		WithCooldown(5*time.Millisecond),
+		WithCacheTTL(0),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			secret := string(rune('a' + i%7))
+			_, _ = v.Do(context.Background(), newRequest(t, srv.URL), secret)
+		}(i)
+	}
+	wg.Wait()
+}
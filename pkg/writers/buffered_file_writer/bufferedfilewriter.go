@@ -13,12 +13,84 @@ import (
 	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
 )
 
-// bufferPool is used to store buffers for reuse.
-var bufferPool = sync.Pool{
-	// TODO: Consider growing the buffer before returning it if we can find an optimal size.
-	// Ideally the size would cover the majority of cases without being too large.
-	// This would avoid the need to grow the buffer when writing to it, reducing allocations.
-	New: func() any { return new(bytes.Buffer) },
+// minBucketCapacity is the smallest size class pooled buffers are bucketed
+// into; requests smaller than this still get a minBucketCapacity buffer; in
+// exchange nothing below the threshold walks away without a matching bucket.
+const minBucketCapacity = 4 * 1024 // 4KB
+
+// maxBucketCapacity is the largest size class pooled. A buffer requested
+// larger than this is allocated directly and never returned to a pool -
+// holding onto, say, a 64MB buffer in a 4KB-8KB-...-16MB bucket ladder would
+// defeat the point of bucketing by keeping an outsized buffer alive.
+const maxBucketCapacity = 16 * 1024 * 1024 // 16MB
+
+// bucketCapacities lists the pooled size classes, in ascending powers of two
+// from minBucketCapacity to maxBucketCapacity.
+var bucketCapacities = func() []int {
+	var sizes []int
+	for c := minBucketCapacity; c <= maxBucketCapacity; c *= 2 {
+		sizes = append(sizes, c)
+	}
+	return sizes
+}()
+
+// bufferPools holds one sync.Pool per entry in bucketCapacities; bufferPools[i]
+// only ever holds buffers with at least bucketCapacities[i] of capacity.
+var bufferPools = func() []*sync.Pool {
+	pools := make([]*sync.Pool, len(bucketCapacities))
+	for i, capacity := range bucketCapacities {
+		capacity := capacity
+		pools[i] = &sync.Pool{New: func() any {
+			buf := new(bytes.Buffer)
+			buf.Grow(capacity)
+			return buf
+		}}
+	}
+	return pools
+}()
+
+// noBucket marks a buffer that was allocated directly rather than pulled
+// from a pooled bucket, and so must not be returned to one.
+const noBucket = -1
+
+// bucketIndex returns the index into bucketCapacities/bufferPools of the
+// smallest bucket that can hold size bytes without growing, or noBucket if
+// size exceeds every bucket.
+func bucketIndex(size int) int {
+	for i, capacity := range bucketCapacities {
+		if capacity >= size {
+			return i
+		}
+	}
+	return noBucket
+}
+
+// getBuffer returns a reset buffer with at least size capacity, along with
+// the bucket it came from (noBucket if it was allocated directly and
+// shouldn't be pooled on return).
+func getBuffer(size int) (*bytes.Buffer, int) {
+	idx := bucketIndex(size)
+	if idx == noBucket {
+		return new(bytes.Buffer), noBucket
+	}
+
+	buf, ok := bufferPools[idx].Get().(*bytes.Buffer)
+	if !ok {
+		buf = new(bytes.Buffer)
+		buf.Grow(bucketCapacities[idx])
+	}
+	buf.Reset()
+	return buf, idx
+}
+
+// putBuffer returns buf to the bucket it was obtained from. It's a no-op for
+// a buffer that was allocated directly (idx == noBucket).
+func putBuffer(buf *bytes.Buffer, idx int) {
+	if idx == noBucket {
+		return
+	}
+	buf.Reset()
+	bufferPools[idx].Put(buf)
 }
 
 // BufferedFileWriter manages a buffer for writing data, flushing to a file when a threshold is exceeded.
@@ -26,9 +98,11 @@ type BufferedFileWriter struct {
 	threshold uint64 // Threshold for switching to file writing.
 	size      uint64 // Total size of the data written.
 
-	buf      bytes.Buffer   // Buffer for storing data under the threshold in memory.
-	filename string         // Name of the temporary file.
-	file     io.WriteCloser // File for storing data over the threshold.
+	buf         bytes.Buffer   // Buffer for storing data under the threshold in memory.
+	bufBucket   int            // Index of the pool bucket buf was acquired from; noBucket if unpooled.
+	bufAcquired bool           // Whether buf has been acquired from a pool yet.
+	filename    string         // Name of the temporary file.
+	file        io.WriteCloser // File for storing data over the threshold.
 }
 
 // Option is a function that modifies a BufferedFileWriter.
@@ -55,6 +129,30 @@ func (w *BufferedFileWriter) Len() int { return w.buf.Len() }
 // String returns the contents of the buffer as a string.
 func (w *BufferedFileWriter) String() string { return w.buf.String() }
 
+// Grow grows the writer's in-memory buffer's capacity to guarantee space for
+// another n bytes, pulling the buffer from the size-matched pool bucket if
+// one hasn't been acquired yet. Callers that know their output's approximate
+// size up front (e.g. a git blob reader given the blob's object size, or an
+// archive extractor given a header's uncompressed size) should call this
+// before Write to avoid repeated bytes.Buffer growth.
+func (w *BufferedFileWriter) Grow(n int) {
+	w.ensureBuffer(n)
+	w.buf.Grow(n)
+}
+
+// ensureBuffer acquires a buffer from the bucket matching sizeHint if one
+// hasn't already been acquired. Subsequent calls are no-ops, so the first
+// Write's or Grow's size hint decides which bucket this writer draws from.
+func (w *BufferedFileWriter) ensureBuffer(sizeHint int) {
+	if w.bufAcquired {
+		return
+	}
+	buf, idx := getBuffer(sizeHint)
+	w.buf = *buf
+	w.bufBucket = idx
+	w.bufAcquired = true
+}
+
 // Write writes data to the buffer or a file, depending on the size.
 func (w *BufferedFileWriter) Write(ctx context.Context, data []byte) (int, error) {
 	size := uint64(len(data))
@@ -68,15 +166,7 @@ func (w *BufferedFileWriter) Write(ctx context.Context, data []byte) (int, error
 		)
 	}()
 
-	if w.buf.Len() == 0 {
-		bufPtr, ok := bufferPool.Get().(*bytes.Buffer)
-		if !ok {
-			ctx.Logger().Error(fmt.Errorf("buffer pool returned unexpected type"), "using new buffer")
-			bufPtr = new(bytes.Buffer)
-		}
-		bufPtr.Reset() // Reset the buffer to clear any existing data
-		w.buf = *bufPtr
-	}
+	w.ensureBuffer(len(data))
 
 	if uint64(w.buf.Len())+size <= w.threshold {
 		// If the total size is within the threshold, write to the buffer.
@@ -106,9 +196,9 @@ func (w *BufferedFileWriter) Write(ctx context.Context, data []byte) (int, error
 			if _, err := w.file.Write(w.buf.Bytes()); err != nil {
 				return 0, err
 			}
-			// Reset the buffer to clear any existing data and return it to the pool.
-			w.buf.Reset()
-			bufferPool.Put(&w.buf)
+			// Return the buffer to its originating bucket.
+			putBuffer(&w.buf, w.bufBucket)
+			w.bufAcquired = false
 		}
 	}
 	ctx.Logger().V(4).Info("writing to file", "data_size", size)
@@ -148,9 +238,10 @@ func (w *BufferedFileWriter) ReadCloser() (io.ReadCloser, error) {
 	}
 
 	// Data is in memory.
+	bufBucket := w.bufBucket
 	return &bufferReadCloser{
 		Reader:  bytes.NewReader(w.buf.Bytes()),
-		onClose: func() { bufferPool.Put(&w.buf) },
+		onClose: func() { putBuffer(&w.buf, bufBucket) },
 	}, nil
 }
 
@@ -0,0 +1,103 @@
+package bufferedfilewriter
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/trufflesecurity/trufflehog/v3/pkg/context"
+)
+
+func TestBucketIndex(t *testing.T) {
+	tests := map[string]struct {
+		size int
+		want int
+	}{
+		"smaller than smallest bucket rounds up to it": {size: 1, want: 0},
+		"exact match to a bucket":                      {size: minBucketCapacity * 4, want: 2},
+		"just over a bucket rounds up to the next one": {size: minBucketCapacity + 1, want: 1},
+		"larger than every bucket":                     {size: maxBucketCapacity + 1, want: noBucket},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := bucketIndex(tc.size); got != tc.want {
+				t.Errorf("bucketIndex(%d) = %d, want %d", tc.size, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetPutBufferRoundTrip(t *testing.T) {
+	buf, idx := getBuffer(1024)
+	if idx == noBucket {
+		t.Fatalf("expected a pooled bucket for a 1024-byte request")
+	}
+	if buf.Cap() < bucketCapacities[idx] {
+		t.Errorf("buffer capacity %d is below its bucket's guaranteed capacity %d", buf.Cap(), bucketCapacities[idx])
+	}
+	buf.WriteString("some data")
+	putBuffer(buf, idx)
+
+	buf2, idx2 := getBuffer(1024)
+	if idx2 != idx {
+		t.Fatalf("expected the same bucket to be reused, got %d want %d", idx2, idx)
+	}
+	if buf2.Len() != 0 {
+		t.Errorf("expected a reused buffer to be reset, got length %d", buf2.Len())
+	}
+}
+
+func TestGetBufferTooLargeIsUnpooled(t *testing.T) {
+	buf, idx := getBuffer(maxBucketCapacity + 1)
+	if idx != noBucket {
+		t.Errorf("expected an oversized request to bypass pooling, got bucket %d", idx)
+	}
+	putBuffer(buf, idx) // Should be a no-op and not panic.
+}
+
+func TestBufferedFileWriterGrow(t *testing.T) {
+	w := New()
+	w.Grow(1024)
+	if w.buf.Cap() < 1024 {
+		t.Errorf("Grow(1024) left capacity at %d", w.buf.Cap())
+	}
+
+	ctx := context.Background()
+	if _, err := w.Write(ctx, []byte("hello")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if w.String() != "hello" {
+		t.Errorf("String() = %q, want %q", w.String(), "hello")
+	}
+}
+
+// mixedSizeWrites simulates the mixed-size workload the tiered pool targets:
+// a handful of large writes (e.g. git blobs) interleaved with many small
+// ones (e.g. archive entries), each via its own writer.
+func mixedSizeWrites(b *testing.B) {
+	ctx := context.Background()
+	sizes := []int{256, 1024, 4096, 9 * 1024 * 1024, 512, 2048}
+	data := make(map[int][]byte, len(sizes))
+	for _, size := range sizes {
+		buf := make([]byte, size)
+		rand.New(rand.NewSource(int64(size))).Read(buf)
+		data[size] = buf
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, size := range sizes {
+			w := New()
+			w.Grow(size)
+			if _, err := w.Write(ctx, data[size]); err != nil {
+				b.Fatalf("Write returned error: %v", err)
+			}
+			_ = w.Close()
+		}
+	}
+}
+
+func BenchmarkMixedSizeWrites(b *testing.B) {
+	b.Run(fmt.Sprintf("buckets=%d", len(bucketCapacities)), mixedSizeWrites)
+}